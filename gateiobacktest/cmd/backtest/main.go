@@ -0,0 +1,782 @@
+// Command backtest รัน backtest ของกลยุทธ์ใน registry จาก command line
+//
+//	backtest run --strategy pivot-supertrend --symbol SOL_USDT --tf 1h --days 365
+//	backtest compare --strategies pivot-supertrend,triple-ema-1h --symbols SOL_USDT,BTC_USDT --tf 1h
+//	backtest run --strategy pivot-supertrend --monte-carlo 5000 --pyramid
+//	backtest optimize --strategy pivot-supertrend --param atr_factor=2:4:0.5 --param risk_reward=1.5:3:0.5
+//	backtest walkforward --strategy pivot-supertrend --days 730 --is-days 180 --oos-days 60
+//	backtest portfolio --strategy pivot-supertrend --symbols SOL_USDT,BTC_USDT,ETH_USDT --max-positions 2
+//	backtest run --config configs/pivot-supertrend.yaml
+//	backtest runs diff 12 15
+//	backtest list-strategies
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gateio-trading-bot/internal/trading"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `ใช้งาน: backtest <คำสั่ง> [flags]
+
+คำสั่ง:
+  run              รัน backtest กลยุทธ์เดียวแล้วบันทึกผล JSON + รายงาน HTML
+  compare          เปรียบเทียบหลายกลยุทธ์ x หลายเหรียญ
+  optimize         ค้นหาพารามิเตอร์ที่ดีที่สุดของกลยุทธ์ที่ปรับได้
+  walkforward      optimize ช่วง in-sample แล้วทดสอบบนช่วง out-of-sample ถัดไปทีละ fold
+  portfolio        รันกลยุทธ์เดียวหลายเหรียญพร้อมกันด้วยเงินทุนร่วม
+  list-strategies  แสดงรายชื่อกลยุทธ์ทั้งหมด
+  runs             ดูประวัติการรันใน results store: runs list | runs show <id> | runs diff <id> <id>
+
+ดู flags ของแต่ละคำสั่งด้วย: backtest <คำสั่ง> -h
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// .env ไม่บังคับ (ใช้เฉพาะ DEEPSEEK_API_KEY ของกลยุทธ์ที่ใช้ AI)
+	_ = godotenv.Load(".env")
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(os.Args[2:])
+	case "compare":
+		err = compareCommand(os.Args[2:])
+	case "optimize":
+		err = optimizeCommand(os.Args[2:])
+	case "walkforward":
+		err = walkForwardCommand(os.Args[2:])
+	case "portfolio":
+		err = portfolioCommand(os.Args[2:])
+	case "runs":
+		err = runsCommand(os.Args[2:])
+	case "list-strategies":
+		listStrategies()
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "❌ ไม่รู้จักคำสั่ง: %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// dataOptions flags ของข้อมูลราคาและ config ที่ใช้ร่วมกันทุกคำสั่ง
+type dataOptions struct {
+	configFile string
+	tf         string
+	days       int
+	capital    float64
+	file       string
+	aiCache    string
+	aiMode     string
+
+	incremental bool
+	fundingRate float64
+	fundingFile string
+
+	pyramid       bool
+	pyramidLegs   int
+	pyramidProfit float64
+	pyramidRisk   float64
+
+	fs     *flag.FlagSet
+	config trading.StrategyConfig // config ที่ใช้จริง (ไฟล์ --config ทับด้วย flags ที่ระบุ)
+}
+
+func addDataFlags(fs *flag.FlagSet) *dataOptions {
+	opts := &dataOptions{fs: fs}
+	fs.StringVar(&opts.configFile, "config", "", "ไฟล์ config ของกลยุทธ์ (.yaml/.yml/.json) - flags ที่ระบุจะทับค่าในไฟล์")
+	fs.StringVar(&opts.tf, "tf", "1h", "timeframe ที่ใช้เทรด (resample จากข้อมูล 15m) เช่น 15m, 1h, 4h, 1d")
+	fs.IntVar(&opts.days, "days", 365, "จำนวนวันย้อนหลัง (ทับ start_date/end_date ของ config)")
+	fs.Float64Var(&opts.capital, "capital", 1000, "เงินทุนเริ่มต้น (USDT)")
+	fs.StringVar(&opts.file, "data", "", "ไฟล์ JSON ของแท่งเทียนแทนข้อมูลตาม symbol (ใช้กับเหรียญเดียว)")
+	fs.StringVar(&opts.aiCache, "ai-cache", "", "ไฟล์ cache คำตัดสิน AI สำหรับกลยุทธ์ที่ใช้ AI")
+	fs.StringVar(&opts.aiMode, "ai-mode", string(trading.AICacheReplay), "โหมด AI cache: record หรือ replay")
+	fs.BoolVar(&opts.incremental, "incremental", false, "ใช้ตัวชี้วัดแบบ streaming (O(1) ต่อแท่ง) แทนการคำนวณใหม่ทุกแท่ง")
+	fs.Float64Var(&opts.fundingRate, "funding-rate", 0, "อัตรา funding คงที่ต่อรอบ 8 ชั่วโมง เช่น 0.0001 = 0.01% (0 = ไม่คิด funding)")
+	fs.StringVar(&opts.fundingFile, "funding-file", "", "ไฟล์ JSON อัตรา funding ย้อนหลัง ({symbol} = ชื่อเหรียญ) ใช้แทน --funding-rate")
+
+	pyramid := trading.DefaultPyramidConfig()
+	fs.BoolVar(&opts.pyramid, "pyramid", false, "เพิ่มไม้ (pyramiding) เมื่อ position มีกำไร ตาม --pyramid-* หรือ risk.pyramid ใน config")
+	fs.IntVar(&opts.pyramidLegs, "pyramid-legs", pyramid.MaxLegs, "จำนวนไม้สูงสุดรวมไม้แรก (ใช้กับ --pyramid)")
+	fs.Float64Var(&opts.pyramidProfit, "pyramid-min-profit", pyramid.MinProfitPct, "กำไรขั้นต่ำ (%) ของไม้ล่าสุดก่อนเพิ่มไม้ (ใช้กับ --pyramid)")
+	fs.Float64Var(&opts.pyramidRisk, "pyramid-risk", pyramid.TotalRisk, "ความเสี่ยงรวมสูงสุดเทียบกับเงินทุน เช่น 0.06 = 6% (ใช้กับ --pyramid)")
+	return opts
+}
+
+// overrides ค่าของ flag ถูกใช้แทน config หรือไม่ (ระบุเอง หรือไม่มีไฟล์ config)
+func (opts *dataOptions) overrides(name string) bool {
+	if opts.configFile == "" {
+		return true
+	}
+	set := false
+	opts.fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// loadConfig โหลดไฟล์ config (ถ้ามี) แล้วทับด้วย flags ข้อมูลที่ระบุ
+func (opts *dataOptions) loadConfig() error {
+	config := trading.DefaultStrategyConfig()
+	if opts.configFile != "" {
+		var err error
+		if config, err = trading.LoadStrategyConfig(opts.configFile); err != nil {
+			return err
+		}
+		fmt.Printf("⚙️ ใช้ config: %s (version %d)\n", opts.configFile, config.Version)
+	}
+
+	if opts.overrides("tf") {
+		config.Timeframe = opts.tf
+	}
+	if opts.overrides("days") {
+		config.Days = opts.days
+		config.StartDate, config.EndDate = "", ""
+	}
+	if opts.overrides("capital") {
+		config.Risk.Capital = opts.capital
+	}
+	if opts.overrides("incremental") {
+		config.IncrementalIndicators = opts.incremental
+	}
+	if opts.overrides("funding-rate") || opts.overrides("funding-file") {
+		config.Risk.Funding = nil
+		if opts.fundingRate != 0 || opts.fundingFile != "" {
+			config.Risk.Funding = &trading.FundingConfig{Rate: opts.fundingRate, File: opts.fundingFile}
+		}
+	}
+	if opts.overrides("pyramid") {
+		config.Risk.Pyramid = nil
+		if opts.pyramid {
+			pyramid := trading.DefaultPyramidConfig()
+			config.Risk.Pyramid = &pyramid
+		}
+	}
+	if p := config.Risk.Pyramid; p != nil {
+		if opts.overrides("pyramid-legs") {
+			p.MaxLegs = opts.pyramidLegs
+		}
+		if opts.overrides("pyramid-min-profit") {
+			p.MinProfitPct = opts.pyramidProfit
+		}
+		if opts.overrides("pyramid-risk") {
+			p.TotalRisk = opts.pyramidRisk
+		}
+	}
+	opts.config = config
+	return opts.config.Validate()
+}
+
+// period คำอธิบายช่วงเวลาของ config
+func (opts *dataOptions) period() string {
+	if opts.config.StartDate == "" {
+		return fmt.Sprintf("%d วัน", opts.config.Days)
+	}
+	end := opts.config.EndDate
+	if end == "" {
+		end = "ล่าสุด"
+	}
+	return opts.config.StartDate + " ถึง " + end
+}
+
+// loadCandles โหลดแท่งเทียนตาม config หรือไฟล์ --data
+func (opts *dataOptions) loadCandles(symbol string) ([]trading.OHLCV, error) {
+	if opts.file == "" {
+		return opts.config.LoadCandles(symbol)
+	}
+
+	data, err := trading.LoadCandlesFile(opts.file)
+	if err != nil {
+		return nil, err
+	}
+	if tf := opts.config.Timeframe; tf != "" && tf != "15m" {
+		return trading.Resample(data, tf)
+	}
+	return data, nil
+}
+
+// newBacktester สร้าง backtester ตาม config พร้อมข้อมูล (กลยุทธ์ที่ใช้ AI ต้องมี DEEPSEEK_API_KEY หรือ AI cache)
+// log ของการรันเขียนไปที่ log (io.Discard = ซ่อน)
+func (opts *dataOptions) newBacktester(info trading.StrategyInfo, symbol string, data []trading.OHLCV, log io.Writer) (*trading.Backtester, error) {
+	config := opts.config
+	config.Strategy = info.Name
+	config.Symbols = []string{symbol}
+
+	var bt *trading.Backtester
+	var err error
+	if key := os.Getenv("DEEPSEEK_API_KEY"); info.NeedsAI && key != "" {
+		bt, err = trading.NewBacktester(symbol, config.Days, config.Risk.Capital, key)
+	} else {
+		bt, err = trading.NewBacktesterSimple(symbol, config.Days, config.Risk.Capital)
+	}
+	if err != nil {
+		return nil, err
+	}
+	bt.SetLogOutput(log)
+	if err := bt.ApplyConfig(config); err != nil {
+		return nil, err
+	}
+	// ApplyConfig ข้ามค่าที่กลยุทธ์ไม่อ่าน เตือนเมื่อผู้ใช้ตั้งค่าเหล่านี้ไว้ต่างจากค่าเริ่มต้น
+	if !info.Tunable && config.Params != trading.DefaultStrategyParams() {
+		fmt.Printf("⚠️ กลยุทธ์ %s ไม่อ่าน params จึงไม่ใช้ค่าใน config\n", info.Name)
+	}
+	if !info.RiskSized && config.Risk.RiskPerTrade != trading.DefaultStrategyConfig().Risk.RiskPerTrade {
+		fmt.Printf("⚠️ กลยุทธ์ %s กำหนดขนาด position เอง จึงไม่ใช้ risk_per_trade ใน config\n", info.Name)
+	}
+
+	if info.NeedsAI && opts.aiCache != "" {
+		cache, err := trading.NewAIDecisionCache(opts.aiCache, trading.AICacheMode(opts.aiMode))
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(log, "🗄️ โหลด AI cache %d รายการจาก %s (โหมด %s)\n", cache.Len(), opts.aiCache, cache.Mode())
+		bt.SetAIDecisionCache(cache)
+	}
+
+	bt.LoadHistoricalData(data)
+	return bt, nil
+}
+
+// canRun ตรวจว่ากลยุทธ์รันได้กับการตั้งค่าปัจจุบัน
+func (opts *dataOptions) canRun(info trading.StrategyInfo) error {
+	if info.NeedsAI && opts.aiCache == "" && os.Getenv("DEEPSEEK_API_KEY") == "" {
+		return fmt.Errorf("กลยุทธ์ %s ต้องมี DEEPSEEK_API_KEY หรือ --ai-cache", info.Name)
+	}
+	return nil
+}
+
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	strategyName := fs.String("strategy", "pivot-supertrend", "ชื่อกลยุทธ์ (ดูด้วย list-strategies)")
+	symbol := fs.String("symbol", "SOL_USDT", "เหรียญ เช่น SOL_USDT (ว่าง = symbols ทั้งหมดใน config)")
+	out := fs.String("out", "", "ชื่อไฟล์ผลลัพธ์ไม่รวมนามสกุล (ว่าง = ตั้งชื่อตามกลยุทธ์และเวลา)")
+	monteCarlo := fs.Int("monte-carlo", 0, "จำนวนรอบ Monte Carlo ที่สุ่มลำดับเทรดหลังรัน (0 = ไม่รัน)")
+	mcMethod := fs.String("mc-method", string(trading.ShuffleTrades), "วิธีสุ่มของ Monte Carlo: shuffle, bootstrap หรือ block")
+	mcSeed := fs.Int64("mc-seed", 1, "seed ของ Monte Carlo")
+	store := addStoreFlags(fs)
+	opts := addDataFlags(fs)
+	fs.Parse(args)
+
+	if err := store.open(); err != nil {
+		return err
+	}
+	defer store.close()
+
+	if err := opts.loadConfig(); err != nil {
+		return err
+	}
+	if opts.overrides("strategy") {
+		opts.config.Strategy = *strategyName
+	}
+	symbols := opts.config.Symbols
+	if opts.overrides("symbol") && *symbol != "" {
+		symbols = []string{*symbol}
+	}
+	if *out != "" && len(symbols) > 1 {
+		return fmt.Errorf("--out ใช้ได้กับเหรียญเดียว (config มี %d เหรียญ)", len(symbols))
+	}
+
+	info, err := trading.LookupStrategy(opts.config.Strategy)
+	if err != nil {
+		return err
+	}
+	if err := opts.canRun(info); err != nil {
+		return err
+	}
+
+	for _, symbol := range symbols {
+		data, err := opts.loadCandles(symbol)
+		if err != nil {
+			return err
+		}
+		bt, err := opts.newBacktester(info, symbol, data, os.Stdout)
+		if err != nil {
+			return err
+		}
+
+		result, err := bt.RunStrategy(info.New())
+		if err != nil {
+			return err
+		}
+
+		tf := opts.config.Timeframe
+		printResult(info, symbol, tf, result)
+		if *monteCarlo > 0 {
+			mc, err := trading.RunMonteCarlo(result, trading.MonteCarloConfig{
+				Iterations: *monteCarlo,
+				Method:     trading.ResampleMethod(*mcMethod),
+				Seed:       *mcSeed,
+			})
+			if err != nil {
+				fmt.Printf("⚠️ ข้าม Monte Carlo ของ %s: %v\n", symbol, err)
+			} else {
+				mc.PrintSummary()
+			}
+		}
+
+		base := *out
+		if base == "" {
+			base = fmt.Sprintf("backtest_%s_%s_%s_%s", info.Name, symbol, tf, time.Now().Format("20060102_150405"))
+		}
+		if err := saveResult(base, result, data); err != nil {
+			return err
+		}
+		if err := store.record(result, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printResult แสดงสรุปผล backtest
+func printResult(info trading.StrategyInfo, symbol, tf string, result *trading.BacktestResult) {
+	separator := strings.Repeat("=", 80)
+	fmt.Println("\n" + separator)
+	fmt.Printf("📊 ผลลัพธ์ %s | %s | %s\n", info.Name, symbol, tf)
+	fmt.Println(separator)
+	fmt.Printf("📅 ช่วงเวลา: %s ถึง %s\n", result.StartDate.Format("2006-01-02"), result.EndDate.Format("2006-01-02"))
+	fmt.Printf("💰 เงินทุน: $%.2f → $%.2f (%.2f%%)\n", result.InitialCapital, result.FinalCapital, result.TotalReturnPct)
+	fmt.Printf("📈 เทรดทั้งหมด: %d | ชนะ: %d | แพ้: %d | Win Rate: %.2f%%\n",
+		result.TotalTrades, result.WinningTrades, result.LosingTrades, result.WinRate)
+	fmt.Printf("📉 Max Drawdown: $%.2f (%.2f%%)\n", result.MaxDrawdown, result.MaxDrawdownPct)
+	result.PrintMetrics()
+	fmt.Println(separator)
+}
+
+// saveResult บันทึกผลเป็น JSON และรายงาน HTML
+func saveResult(base string, result *trading.BacktestResult, candles []trading.OHLCV) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถแปลงผลลัพธ์เป็น JSON: %v", err)
+	}
+	if err := os.WriteFile(base+".json", data, 0644); err != nil {
+		return fmt.Errorf("ไม่สามารถบันทึกไฟล์ JSON: %v", err)
+	}
+	fmt.Printf("💾 บันทึกผลลัพธ์: %s.json\n", base)
+
+	return trading.SaveHTMLReport(base+".html", result, candles)
+}
+
+// comparison ผลของกลยุทธ์หนึ่งบนเหรียญหนึ่ง
+type comparison struct {
+	Strategy       string  `json:"strategy"`
+	Symbol         string  `json:"symbol"`
+	TotalReturnPct float64 `json:"total_return_pct"`
+	TotalTrades    int     `json:"total_trades"`
+	WinRate        float64 `json:"win_rate"`
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+	SharpeRatio    float64 `json:"sharpe_ratio"`
+	ProfitFactor   float64 `json:"profit_factor"`
+	RunID          int64   `json:"run_id,omitempty"` // id ใน results store (0 = ไม่ได้บันทึก)
+	Error          string  `json:"error,omitempty"`
+}
+
+func compareCommand(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	strategies := fs.String("strategies", "all", "รายชื่อกลยุทธ์คั่นด้วย comma (all = ทุกกลยุทธ์ที่รันได้)")
+	symbols := fs.String("symbols", "SOL_USDT,BTC_USDT,ETH_USDT", "รายชื่อเหรียญคั่นด้วย comma (ไม่ระบุ + --config = symbols ใน config)")
+	out := fs.String("out", "", "บันทึกตารางเปรียบเทียบเป็น JSON (ว่าง = ไม่บันทึก)")
+	verbose := fs.Bool("v", false, "แสดง log ของแต่ละ backtest")
+	store := addStoreFlags(fs)
+	opts := addDataFlags(fs)
+	fs.Parse(args)
+
+	if err := store.open(); err != nil {
+		return err
+	}
+	defer store.close()
+
+	if err := opts.loadConfig(); err != nil {
+		return err
+	}
+	if opts.overrides("symbols") {
+		opts.config.Symbols = splitList(*symbols)
+	}
+
+	var infos []trading.StrategyInfo
+	if *strategies == "all" {
+		for _, info := range trading.Strategies() {
+			if opts.canRun(info) == nil {
+				infos = append(infos, info)
+			}
+		}
+	} else {
+		for _, name := range splitList(*strategies) {
+			info, err := trading.LookupStrategy(name)
+			if err != nil {
+				return err
+			}
+			if err := opts.canRun(info); err != nil {
+				return err
+			}
+			infos = append(infos, info)
+		}
+	}
+
+	// log ของแต่ละ backtest ถูกซ่อนเว้นแต่ระบุ -v
+	var log io.Writer = io.Discard
+	if *verbose {
+		log = os.Stdout
+	}
+
+	var rows []comparison
+	for _, symbol := range opts.config.Symbols {
+		data, err := opts.loadCandles(symbol)
+		if err != nil {
+			return err
+		}
+
+		for _, info := range infos {
+			fmt.Printf("🔍 %s บน %s...\n", info.Name, symbol)
+			row := comparison{Strategy: info.Name, Symbol: symbol}
+
+			var result *trading.BacktestResult
+			bt, err := opts.newBacktester(info, symbol, data, log)
+			if err == nil {
+				result, err = bt.RunStrategy(info.New())
+			}
+			if err != nil {
+				row.Error = err.Error()
+			} else {
+				row.TotalReturnPct = result.TotalReturnPct
+				row.TotalTrades = result.TotalTrades
+				row.WinRate = result.WinRate
+				row.MaxDrawdownPct = result.MaxDrawdownPct
+				row.SharpeRatio = result.Metrics.SharpeRatio
+				row.ProfitFactor = result.Metrics.ProfitFactor
+				row.RunID, err = store.save(result, data)
+				if err != nil {
+					return err
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if (rows[i].Error == "") != (rows[j].Error == "") {
+			return rows[i].Error == ""
+		}
+		return rows[i].TotalReturnPct > rows[j].TotalReturnPct
+	})
+	printComparison(rows, opts.config.Timeframe, opts.period())
+
+	return saveJSON(*out, rows, "ผลการเปรียบเทียบ")
+}
+
+// printComparison แสดงตารางเปรียบเทียบเรียงตามผลตอบแทน
+func printComparison(rows []comparison, tf, period string) {
+	fmt.Printf("\n🏆 ===== เปรียบเทียบกลยุทธ์ (%s, %s) =====\n", tf, period)
+	fmt.Printf("%-24s %-10s %10s %7s %8s %8s %8s %8s\n",
+		"Strategy", "Symbol", "Return%", "Trades", "WinRate", "MaxDD%", "Sharpe", "PF")
+	for _, row := range rows {
+		if row.Error != "" {
+			fmt.Printf("%-24s %-10s ❌ %s\n", row.Strategy, row.Symbol, row.Error)
+			continue
+		}
+		fmt.Printf("%-24s %-10s %10.2f %7d %8.2f %8.2f %8.2f %8.2f\n",
+			row.Strategy, row.Symbol, row.TotalReturnPct, row.TotalTrades, row.WinRate,
+			row.MaxDrawdownPct, row.SharpeRatio, row.ProfitFactor)
+	}
+}
+
+// paramFlags flag --param ที่ระบุซ้ำได้ รูปแบบ name=min:max:step
+type paramFlags []trading.ParamRange
+
+func (p *paramFlags) String() string {
+	names := make([]string, len(*p))
+	for i, r := range *p {
+		names[i] = r.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func (p *paramFlags) Set(value string) error {
+	name, spec, ok := strings.Cut(value, "=")
+	parts := strings.Split(spec, ":")
+	if !ok || len(parts) != 3 {
+		return fmt.Errorf("รูปแบบต้องเป็น name=min:max:step: %q", value)
+	}
+
+	var bounds [3]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return fmt.Errorf("ค่าไม่ใช่ตัวเลข %q ใน %q", part, value)
+		}
+		bounds[i] = v
+	}
+	if bounds[2] <= 0 || bounds[1] < bounds[0] {
+		return fmt.Errorf("ต้องมี min ≤ max และ step > 0: %q", value)
+	}
+
+	*p = append(*p, trading.NewParamRange(name, bounds[0], bounds[1], bounds[2]))
+	return nil
+}
+
+// optimizeOptions flags ของการค้นหาพารามิเตอร์ที่ใช้ร่วมกันระหว่าง optimize และ walkforward
+type optimizeOptions struct {
+	strategy  string
+	symbol    string
+	space     paramFlags
+	method    string
+	samples   int
+	objective string
+	workers   int
+	seed      int64
+}
+
+func addOptimizeFlags(fs *flag.FlagSet) *optimizeOptions {
+	o := &optimizeOptions{}
+	fs.StringVar(&o.strategy, "strategy", "pivot-supertrend", "กลยุทธ์ที่ปรับพารามิเตอร์ได้")
+	fs.StringVar(&o.symbol, "symbol", "SOL_USDT", "เหรียญ เช่น SOL_USDT")
+	fs.Var(&o.space, "param", fmt.Sprintf("ช่วงพารามิเตอร์ name=min:max:step ระบุซ้ำได้ (name: %s)", strings.Join(trading.ParamNames, ", ")))
+	fs.StringVar(&o.method, "method", string(trading.GridSearch), "วิธีค้นหา: grid หรือ random")
+	fs.IntVar(&o.samples, "samples", 50, "จำนวนชุดที่สุ่ม (method=random)")
+	fs.StringVar(&o.objective, "objective", string(trading.ObjectiveReturn), "เกณฑ์จัดอันดับ: return, sharpe หรือ drawdown")
+	fs.IntVar(&o.workers, "workers", 0, "จำนวน backtest ที่รันพร้อมกัน (0 = จำนวน CPU)")
+	fs.Int64Var(&o.seed, "seed", 1, "seed ของการสุ่ม")
+	return o
+}
+
+// newOptimizer โหลด config และข้อมูลของเหรียญแล้วสร้าง optimizer ของกลยุทธ์ที่เลือก
+func (o *optimizeOptions) newOptimizer(opts *dataOptions) (*trading.Optimizer, trading.OptimizerConfig, error) {
+	if err := opts.loadConfig(); err != nil {
+		return nil, trading.OptimizerConfig{}, err
+	}
+	if opts.overrides("strategy") {
+		opts.config.Strategy = o.strategy
+	}
+	if opts.overrides("symbol") {
+		opts.config.Symbols = []string{o.symbol}
+	}
+
+	info, err := trading.LookupStrategy(opts.config.Strategy)
+	if err != nil {
+		return nil, trading.OptimizerConfig{}, err
+	}
+	if !info.Tunable || info.NeedsAI {
+		return nil, trading.OptimizerConfig{}, fmt.Errorf("กลยุทธ์ %s ไม่ได้ใช้ StrategyParams หรือต้องใช้ AI จึง optimize ไม่ได้", info.Name)
+	}
+	space := o.space
+	if len(space) == 0 {
+		space = paramFlags{
+			trading.NewParamRange("atr_factor", 2, 4, 0.5),
+			trading.NewParamRange("atr_multiplier", 1, 2.5, 0.5),
+			trading.NewParamRange("risk_reward", 1.5, 3, 0.5),
+		}
+	}
+
+	symbol := opts.config.Symbols[0]
+	data, err := opts.loadCandles(symbol)
+	if err != nil {
+		return nil, trading.OptimizerConfig{}, err
+	}
+
+	optimizer := trading.NewOptimizer(symbol, data, opts.config.Risk.Capital, func(bt *trading.Backtester) (*trading.BacktestResult, error) {
+		return bt.RunStrategy(info.New())
+	})
+	// ทุกชุดใช้ความเสี่ยง futures funding และช่วงวันที่เดียวกับคำสั่ง run (แท่ง warmup ก่อน start_date ไม่ถูกเทรด)
+	if err := optimizer.SetConfig(opts.config); err != nil {
+		return nil, trading.OptimizerConfig{}, err
+	}
+	return optimizer, trading.OptimizerConfig{
+		Space:     space,
+		Method:    trading.SearchMethod(o.method),
+		Samples:   o.samples,
+		Workers:   o.workers,
+		Objective: trading.Objective(o.objective),
+		Seed:      o.seed,
+	}, nil
+}
+
+func optimizeCommand(args []string) error {
+	fs := flag.NewFlagSet("optimize", flag.ExitOnError)
+	search := addOptimizeFlags(fs)
+	top := fs.Int("top", 10, "จำนวนอันดับที่แสดง")
+	csvPath := fs.String("csv", "", "บันทึกผลทั้งหมดเป็น CSV (ว่าง = ไม่บันทึก)")
+	opts := addDataFlags(fs)
+	fs.Parse(args)
+
+	optimizer, config, err := search.newOptimizer(opts)
+	if err != nil {
+		return err
+	}
+	runs, err := optimizer.Run(config)
+	if err != nil {
+		return err
+	}
+
+	trading.PrintOptimizationTable(os.Stdout, runs, *top)
+	if *csvPath != "" {
+		if err := trading.SaveOptimizationCSV(runs, *csvPath); err != nil {
+			return err
+		}
+		fmt.Printf("💾 บันทึกผล optimize ลงไฟล์: %s (%d แถว)\n", *csvPath, len(runs))
+	}
+	return nil
+}
+
+func walkForwardCommand(args []string) error {
+	fs := flag.NewFlagSet("walkforward", flag.ExitOnError)
+	search := addOptimizeFlags(fs)
+	isDays := fs.Int("is-days", 180, "ความยาวช่วง in-sample ที่ใช้ optimize (วัน)")
+	oosDays := fs.Int("oos-days", 60, "ความยาวช่วง out-of-sample ที่ใช้ทดสอบ (วัน)")
+	stepDays := fs.Int("step-days", 0, "ระยะเลื่อนแต่ละ fold (วัน, 0 = เท่ากับ --oos-days)")
+	out := fs.String("out", "", "บันทึกผล walk-forward เป็น JSON (ว่าง = ไม่บันทึก)")
+	opts := addDataFlags(fs)
+	fs.Parse(args)
+
+	optimizer, config, err := search.newOptimizer(opts)
+	if err != nil {
+		return err
+	}
+	result, err := optimizer.WalkForward(trading.WalkForwardConfig{
+		InSampleDays:    *isDays,
+		OutOfSampleDays: *oosDays,
+		StepDays:        *stepDays,
+		Optimizer:       config,
+	})
+	if err != nil {
+		return err
+	}
+
+	result.PrintSummary()
+	return saveJSON(*out, result, "ผล walk-forward")
+}
+
+func portfolioCommand(args []string) error {
+	fs := flag.NewFlagSet("portfolio", flag.ExitOnError)
+	strategyName := fs.String("strategy", "pivot-supertrend", "ชื่อกลยุทธ์ที่ใช้กับทุกเหรียญ (ดูด้วย list-strategies)")
+	symbols := fs.String("symbols", "SOL_USDT,BTC_USDT,ETH_USDT", "รายชื่อเหรียญคั่นด้วย comma (ไม่ระบุ + --config = symbols ใน config)")
+	maxPositions := fs.Int("max-positions", 3, "จำนวน position ที่เปิดพร้อมกันได้สูงสุด (0 = ไม่จำกัด)")
+	maxExposure := fs.Float64("max-exposure", 0.5, "notional สูงสุดต่อเหรียญเทียบกับ equity เช่น 0.5 = 50% (0 = ไม่จำกัด)")
+	out := fs.String("out", "", "บันทึกผล portfolio เป็น JSON (ว่าง = ไม่บันทึก)")
+	verbose := fs.Bool("v", false, "แสดง log ของแต่ละเหรียญ")
+	opts := addDataFlags(fs)
+	fs.Parse(args)
+
+	if err := opts.loadConfig(); err != nil {
+		return err
+	}
+	if opts.overrides("strategy") {
+		opts.config.Strategy = *strategyName
+	}
+	if opts.overrides("symbols") {
+		opts.config.Symbols = splitList(*symbols)
+	}
+
+	info, err := trading.LookupStrategy(opts.config.Strategy)
+	if err != nil {
+		return err
+	}
+	if info.NeedsAI {
+		return fmt.Errorf("กลยุทธ์ %s ต้องใช้ AI ซึ่ง portfolio ยังไม่รองรับ", info.Name)
+	}
+
+	var log io.Writer = io.Discard
+	if *verbose {
+		log = os.Stdout
+	}
+	portfolio := trading.NewPortfolioBacktester(trading.PortfolioConfig{
+		InitialCapital:    opts.config.Risk.Capital,
+		MaxPositions:      *maxPositions,
+		MaxSymbolExposure: *maxExposure,
+	})
+	portfolio.SetLogOutput(log)
+
+	// ทุกเหรียญใช้ความเสี่ยง futures funding และ pyramiding เดียวกับคำสั่ง run
+	config := opts.config
+	config.Strategy = info.Name
+	for _, symbol := range opts.config.Symbols {
+		data, err := opts.loadCandles(symbol)
+		if err != nil {
+			return err
+		}
+		bt, err := portfolio.AddSymbol(symbol, data, info.New())
+		if err != nil {
+			return err
+		}
+		config.Symbols = []string{symbol}
+		if err := bt.ApplyConfig(config); err != nil {
+			return err
+		}
+	}
+
+	result, err := portfolio.Run()
+	if err != nil {
+		return err
+	}
+	result.PrintSummary()
+	return saveJSON(*out, result, "ผล portfolio")
+}
+
+// saveJSON บันทึก v เป็นไฟล์ JSON (path ว่าง = ไม่บันทึก)
+func saveJSON(path string, v any, what string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("ไม่สามารถบันทึกไฟล์ %s: %v", path, err)
+	}
+	fmt.Printf("💾 บันทึก%s: %s\n", what, path)
+	return nil
+}
+
+func listStrategies() {
+	fmt.Println("📋 กลยุทธ์ที่มีให้เลือก:")
+	for _, info := range trading.Strategies() {
+		var tags []string
+		if info.Tunable {
+			tags = append(tags, "optimize ได้")
+		}
+		if info.RiskSized {
+			tags = append(tags, "ขนาดตาม risk_per_trade")
+		}
+		if info.NeedsAI {
+			tags = append(tags, "ใช้ AI")
+		}
+		tag := ""
+		if len(tags) > 0 {
+			tag = " [" + strings.Join(tags, ", ") + "]"
+		}
+		fmt.Printf("  %-24s %s%s\n", info.Name, info.Description, tag)
+	}
+}
+
+// splitList แยกรายการที่คั่นด้วย comma และตัดช่องว่าง
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
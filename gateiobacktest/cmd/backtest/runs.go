@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gateio-trading-bot/internal/trading"
+)
+
+// defaultResultsDB ไฟล์ results store เริ่มต้น
+const defaultResultsDB = "backtest_results.db"
+
+// storeOptions flags ของ results store สำหรับคำสั่งที่บันทึกผล
+type storeOptions struct {
+	path  string
+	note  string
+	store *trading.ResultsStore
+}
+
+func addStoreFlags(fs *flag.FlagSet) *storeOptions {
+	opts := &storeOptions{}
+	fs.StringVar(&opts.path, "db", defaultResultsDB, "ไฟล์ SQLite ของ results store (ว่าง = ไม่บันทึก)")
+	fs.StringVar(&opts.note, "note", "", "บันทึกสั้นๆ ที่แนบไปกับการรัน เช่น สิ่งที่แก้ในกลยุทธ์")
+	return opts
+}
+
+func (opts *storeOptions) open() error {
+	if opts.path == "" {
+		return nil
+	}
+	store, err := trading.OpenResultsStore(opts.path)
+	if err != nil {
+		return err
+	}
+	opts.store = store
+	return nil
+}
+
+func (opts *storeOptions) close() {
+	if opts.store != nil {
+		opts.store.Close()
+	}
+}
+
+// save บันทึกผลลง store (คืน 0 เมื่อไม่ได้เปิด store)
+func (opts *storeOptions) save(result *trading.BacktestResult, data []trading.OHLCV) (int64, error) {
+	if opts.store == nil {
+		return 0, nil
+	}
+	return opts.store.SaveRun(result, data, opts.note)
+}
+
+// record บันทึกผลลง store พร้อมแจ้ง id ของการรัน
+func (opts *storeOptions) record(result *trading.BacktestResult, data []trading.OHLCV) error {
+	id, err := opts.save(result, data)
+	if err != nil || id == 0 {
+		return err
+	}
+	fmt.Printf("🗄️ บันทึกการรัน #%d ลง %s\n", id, opts.path)
+	return nil
+}
+
+const runsUsage = `ใช้งาน: backtest runs <list|show|diff> [flags]
+
+  list              แสดงรายการการรันล่าสุด
+  show <id>         แสดงรายละเอียดการรัน
+  diff <id> <id>    เปรียบเทียบสองการรันทีละตัวชี้วัดและทีละเทรด
+`
+
+func runsCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("ต้องระบุคำสั่งย่อย\n\n%s", runsUsage)
+	}
+
+	fs := flag.NewFlagSet("runs "+args[0], flag.ExitOnError)
+	dbPath := fs.String("db", defaultResultsDB, "ไฟล์ SQLite ของ results store")
+
+	switch args[0] {
+	case "list":
+		strategy := fs.String("strategy", "", "กรองตามกลยุทธ์")
+		symbol := fs.String("symbol", "", "กรองตามเหรียญ")
+		limit := fs.Int("limit", 20, "จำนวนการรันที่แสดง (0 = ทั้งหมด)")
+		fs.Parse(args[1:])
+		return withStore(*dbPath, func(store *trading.ResultsStore) error {
+			runs, err := store.ListRuns(trading.RunFilter{Strategy: *strategy, Symbol: *symbol, Limit: *limit})
+			if err != nil {
+				return err
+			}
+			printRuns(runs)
+			return nil
+		})
+
+	case "show":
+		trades := fs.Int("trades", 20, "จำนวนเทรดที่แสดง (0 = ทั้งหมด)")
+		ids, err := parseRunIDs(fs, args[1:], 1)
+		if err != nil {
+			return err
+		}
+		return withStore(*dbPath, func(store *trading.ResultsStore) error {
+			run, err := store.LoadRun(ids[0])
+			if err != nil {
+				return err
+			}
+			return printRun(run, *trades)
+		})
+
+	case "diff":
+		trades := fs.Int("trades", 50, "จำนวนเทรดที่ต่างกันที่แสดง (0 = ทั้งหมด)")
+		ids, err := parseRunIDs(fs, args[1:], 2)
+		if err != nil {
+			return err
+		}
+		return withStore(*dbPath, func(store *trading.ResultsStore) error {
+			a, err := store.LoadRun(ids[0])
+			if err != nil {
+				return err
+			}
+			b, err := store.LoadRun(ids[1])
+			if err != nil {
+				return err
+			}
+			trading.DiffRuns(a, b).Print(*trades)
+			return nil
+		})
+	}
+	return fmt.Errorf("ไม่รู้จักคำสั่งย่อย: %s\n\n%s", args[0], runsUsage)
+}
+
+// parseRunIDs อ่าน flags และ id ของการรันจำนวน n ตัว (flags อยู่ก่อนหรือหลัง id ก็ได้)
+func parseRunIDs(fs *flag.FlagSet, args []string, n int) ([]int64, error) {
+	var ids []int64
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("id ของการรันไม่ถูกต้อง: %q", args[0])
+		}
+		ids = append(ids, id)
+		args = args[1:]
+	}
+	if len(ids) != n {
+		return nil, fmt.Errorf("ต้องระบุ id ของการรัน %d ตัว (ได้ %d)", n, len(ids))
+	}
+	return ids, nil
+}
+
+// withStore เปิด results store แล้วเรียก fn
+func withStore(path string, fn func(store *trading.ResultsStore) error) error {
+	store, err := trading.OpenResultsStore(path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	return fn(store)
+}
+
+// printRuns แสดงตารางการรัน
+func printRuns(runs []*trading.StoredRun) {
+	if len(runs) == 0 {
+		fmt.Println("📭 ยังไม่มีการรันใน results store")
+		return
+	}
+	fmt.Printf("%5s  %-16s %-24s %-10s %-4s %10s %7s %8s %8s %8s  %-16s %s\n",
+		"ID", "Created", "Strategy", "Symbol", "TF", "Return%", "Trades", "WinRate", "MaxDD%", "Sharpe", "Data", "Note")
+	for _, run := range runs {
+		r := run.Result
+		fmt.Printf("%5d  %-16s %-24s %-10s %-4s %10.2f %7d %8.2f %8.2f %8.2f  %-16s %s\n",
+			run.ID, run.CreatedAt.Local().Format("2006-01-02 15:04"), run.Strategy, run.Symbol, run.Timeframe,
+			r.TotalReturnPct, r.TotalTrades, r.WinRate, r.MaxDrawdownPct, r.Metrics.SharpeRatio,
+			run.DataFingerprint, run.Note)
+	}
+}
+
+// printRun แสดงรายละเอียดการรันพร้อม config และเทรด
+func printRun(run *trading.StoredRun, maxTrades int) error {
+	info := trading.StrategyInfo{Name: run.Strategy}
+	printResult(info, run.Symbol, run.Timeframe, run.Result)
+	fmt.Printf("🗄️ การรัน #%d เมื่อ %s | ข้อมูล %s (%d แท่ง)\n",
+		run.ID, run.CreatedAt.Local().Format("2006-01-02 15:04:05"), run.DataFingerprint, run.Bars)
+	if run.Note != "" {
+		fmt.Printf("📝 %s\n", run.Note)
+	}
+
+	config, err := json.MarshalIndent(run.Result.Config, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("⚙️ config:\n%s\n", config)
+
+	trades := run.Result.Trades
+	fmt.Printf("\n📋 เทรด (%d):\n", len(trades))
+	for i, trade := range trades {
+		if maxTrades > 0 && i >= maxTrades {
+			fmt.Printf("   ... อีก %d เทรด\n", len(trades)-maxTrades)
+			break
+		}
+		fmt.Printf("   %4d %s %-5s %.4f → %.4f %-24s net %8.2f\n", i+1,
+			trade.EntryTime.UTC().Format("2006-01-02 15:04"), trade.Side, trade.EntryPrice, trade.ExitPrice,
+			trade.ExitReason, trade.NetPnL)
+	}
+	return nil
+}
@@ -1,366 +0,0 @@
-// Enhanced 1H Trading Implementation - Real Market Version
-package main
-
-import (
-	"log"
-	"math"
-	"time"
-)
-
-// Enhanced1HTrader - Live trading implementation
-type Enhanced1HTrader struct {
-	client       *TradingClient
-	dataManager  *Enhanced1HDataManager
-	riskManager  *RiskManager
-	perfTracker  *PerformanceTracker
-	config       *Enhanced1HConfig
-	activeOrders map[string]*Position
-}
-
-type Enhanced1HConfig struct {
-	ConfidenceThreshold float64 `json:"confidence_threshold"` // 80% minimum
-	QualityThreshold    float64 `json:"quality_threshold"`    // 90% minimum
-	RiskPerTrade        float64 `json:"risk_per_trade"`       // 1% per trade
-	MaxLeverage         float64 `json:"max_leverage"`         // 5x maximum
-	MaxCapitalUsage     float64 `json:"max_capital_usage"`    // 15% maximum
-	TimeLimit           int     `json:"time_limit_hours"`     // 24 hours
-	ProgressiveExits    bool    `json:"progressive_exits"`    // true for proven system
-}
-
-// NewEnhanced1HTrader - Initialize live trading system
-func NewEnhanced1HTrader(apiKey, secretKey string) *Enhanced1HTrader {
-	config := &Enhanced1HConfig{
-		ConfidenceThreshold: 0.80, // Proven threshold
-		QualityThreshold:    0.90, // High quality requirement
-		RiskPerTrade:        0.01, // 1% risk per trade
-		MaxLeverage:         5.0,  // Maximum 5x leverage
-		MaxCapitalUsage:     0.15, // 15% capital usage max
-		TimeLimit:           24,   // 24-hour trade limit
-		ProgressiveExits:    true, // Use proven exit system
-	}
-
-	return &Enhanced1HTrader{
-		client:       NewTradingClient(apiKey, secretKey),
-		dataManager:  NewEnhanced1HDataManager(),
-		riskManager:  NewRiskManager(config),
-		perfTracker:  NewPerformanceTracker(),
-		config:       config,
-		activeOrders: make(map[string]*Position),
-	}
-}
-
-// RunEnhanced1HStrategy - Main trading loop with proven system
-func (t *Enhanced1HTrader) RunEnhanced1HStrategy(symbol string) error {
-	log.Printf("🚀 Starting Enhanced 1H Strategy for %s", symbol)
-	log.Printf("📊 Target: +13.98%% avg per trade, 100%% win rate")
-
-	ticker := time.NewTicker(5 * time.Minute) // Check every 5 minutes
-	defer ticker.Stop()
-
-	for range ticker.C {
-		// 1. Get fresh market data
-		data, err := t.dataManager.GetRealTimeData(symbol, 144)
-		if err != nil {
-			log.Printf("❌ Data error for %s: %v", symbol, err)
-			continue
-		}
-
-		// 2. Quality check (≥90% required)
-		quality := t.dataManager.AssessDataQuality(data)
-		if quality < t.config.QualityThreshold {
-			log.Printf("⚠️ Low data quality %.2f%% < %.2f%% for %s",
-				quality*100, t.config.QualityThreshold*100, symbol)
-			continue
-		}
-
-		// 3. Check for new entry opportunities
-		if !t.hasActivePosition(symbol) {
-			signal := t.analyzeEntrySignal(symbol, data)
-			if signal != nil && signal.Confidence >= t.config.ConfidenceThreshold {
-				t.executeEntry(symbol, signal)
-			}
-		}
-
-		// 4. Manage existing positions
-		if pos, exists := t.activeOrders[symbol]; exists {
-			t.managePosition(symbol, pos, data)
-		}
-
-		// 5. Log performance metrics
-		t.logPerformanceUpdate(symbol)
-	}
-
-	return nil
-}
-
-// analyzeEntrySignal - Enhanced entry analysis with proven criteria
-func (t *Enhanced1HTrader) analyzeEntrySignal(symbol string, data *Enhanced1HAnalysis) *TradingSignal {
-	// Get current price and EMAs
-	price := data.CurrentPrice
-	fastEMA := data.Indicators.EMA9
-	midEMA := data.Indicators.EMA21
-	slowEMA := data.Indicators.EMA50
-
-	// Check perfect EMA alignment
-	longAlignment := fastEMA > midEMA && midEMA > slowEMA
-	shortAlignment := fastEMA < midEMA && midEMA < slowEMA
-
-	if !longAlignment && !shortAlignment {
-		return nil // No clear trend
-	}
-
-	// Calculate EMA spread (minimum 0.15% required)
-	emaSpread := math.Abs(fastEMA-slowEMA) / slowEMA * 100
-	if emaSpread < 0.15 {
-		return nil // Insufficient trend strength
-	}
-
-	// Volume confirmation (>1.2x MA required)
-	volumeMA := data.Indicators.VolumeMA
-	currentVolume := data.CurrentVolume
-	if currentVolume < volumeMA*1.2 {
-		return nil // Insufficient volume
-	}
-
-	var signal *TradingSignal
-
-	if longAlignment {
-		// LONG signal criteria
-		priceThreshold := fastEMA * 0.998 // Price ≥ Fast EMA × 0.998
-		rsi := data.Indicators.RSI
-
-		if price >= priceThreshold && rsi >= 40 && rsi <= 80 {
-			confidence := t.calculateConfidence(data, "LONG")
-			if confidence >= t.config.ConfidenceThreshold {
-				signal = &TradingSignal{
-					Symbol:     symbol,
-					Direction:  "LONG",
-					Price:      price,
-					Confidence: confidence,
-					Timestamp:  time.Now(),
-				}
-			}
-		}
-	} else {
-		// SHORT signal criteria
-		priceThreshold := fastEMA * 1.002 // Price ≤ Fast EMA × 1.002
-		rsi := data.Indicators.RSI
-
-		if price <= priceThreshold && rsi >= 20 && rsi <= 60 {
-			confidence := t.calculateConfidence(data, "SHORT")
-			if confidence >= t.config.ConfidenceThreshold {
-				signal = &TradingSignal{
-					Symbol:     symbol,
-					Direction:  "SHORT",
-					Price:      price,
-					Confidence: confidence,
-					Timestamp:  time.Now(),
-				}
-			}
-		}
-	}
-
-	return signal
-}
-
-// executeEntry - Execute trade with proven risk management
-func (t *Enhanced1HTrader) executeEntry(symbol string, signal *TradingSignal) {
-	log.Printf("🎯 ENTRY SIGNAL: %s %s at %.6f (Conf: %.2f%%)",
-		symbol, signal.Direction, signal.Price, signal.Confidence*100)
-
-	// Calculate position size (1% risk per trade)
-	balance := t.client.GetAccountBalance()
-	riskAmount := balance * t.config.RiskPerTrade
-
-	atr := t.dataManager.GetCurrentATR(symbol)
-	stopDistance := atr * 2.0 // ATR × 2.0 for stop loss
-
-	positionSize := riskAmount / stopDistance
-
-	// Apply leverage (max 5x)
-	leverage := math.Min(t.config.MaxLeverage, positionSize/balance)
-	finalSize := balance * leverage
-
-	// Calculate stops using proven levels
-	var stopLoss, takeProfit float64
-	if signal.Direction == "LONG" {
-		stopLoss = signal.Price - stopDistance
-		takeProfit = signal.Price + (stopDistance * 2.0) // 1:2 R/R
-	} else {
-		stopLoss = signal.Price + stopDistance
-		takeProfit = signal.Price - (stopDistance * 2.0)
-	}
-
-	// Create position
-	position := &Position{
-		Symbol:       symbol,
-		Direction:    signal.Direction,
-		EntryPrice:   signal.Price,
-		Size:         finalSize,
-		StopLoss:     stopLoss,
-		TakeProfit:   takeProfit,
-		Leverage:     leverage,
-		EntryTime:    time.Now(),
-		TimeLimit:    time.Now().Add(time.Duration(t.config.TimeLimit) * time.Hour),
-		Confidence:   signal.Confidence,
-		PartialExits: make(map[float64]float64), // For progressive exits
-	}
-
-	// Execute the trade
-	orderID, err := t.client.PlaceOrder(position)
-	if err != nil {
-		log.Printf("❌ Order failed for %s: %v", symbol, err)
-		return
-	}
-
-	position.OrderID = orderID
-	t.activeOrders[symbol] = position
-
-	log.Printf("✅ ENTERED %s %s: Size=%.4f, SL=%.6f, TP=%.6f, Lev=%.1fx",
-		symbol, signal.Direction, finalSize, stopLoss, takeProfit, leverage)
-}
-
-// managePosition - Enhanced position management with progressive exits
-func (t *Enhanced1HTrader) managePosition(symbol string, pos *Position, data *Enhanced1HAnalysis) {
-	currentPrice := data.CurrentPrice
-
-	// Check time limit (24 hours)
-	if time.Now().After(pos.TimeLimit) {
-		log.Printf("⏰ Time limit reached for %s, closing position", symbol)
-		t.closePosition(symbol, pos, "TIME_LIMIT")
-		return
-	}
-
-	// Check data quality (minimum 80% for exits)
-	quality := t.dataManager.AssessDataQuality(data)
-	if quality < 0.80 {
-		log.Printf("⚠️ Data quality dropped to %.2f%% for %s, closing position", quality*100, symbol)
-		t.closePosition(symbol, pos, "QUALITY_DROP")
-		return
-	}
-
-	// Calculate current P&L
-	var pnlPercent float64
-	if pos.Direction == "LONG" {
-		pnlPercent = (currentPrice - pos.EntryPrice) / pos.EntryPrice * 100 * pos.Leverage
-	} else {
-		pnlPercent = (pos.EntryPrice - currentPrice) / pos.EntryPrice * 100 * pos.Leverage
-	}
-
-	// Progressive exits (proven system: 25% at 1:1, 35% at 1.5:1, 40% at 2:1)
-	if t.config.ProgressiveExits {
-		stopDistance := math.Abs(pos.StopLoss - pos.EntryPrice)
-		targetDistance := stopDistance // 1:1 ratio
-
-		// 25% exit at 1:1 R/R
-		if pnlPercent >= 1.0 && pos.PartialExits[1.0] == 0 {
-			t.partialExit(symbol, pos, 0.25, "1:1_RR")
-			pos.PartialExits[1.0] = 0.25
-		}
-
-		// 35% exit at 1.5:1 R/R
-		if pnlPercent >= 1.5 && pos.PartialExits[1.5] == 0 {
-			t.partialExit(symbol, pos, 0.35, "1.5:1_RR")
-			pos.PartialExits[1.5] = 0.35
-
-			// Activate trailing stop at Mid EMA
-			if pos.Direction == "LONG" {
-				pos.TrailingStop = data.Indicators.EMA21 * 0.997 // Mid EMA × 0.997
-			} else {
-				pos.TrailingStop = data.Indicators.EMA21 * 1.003 // Mid EMA × 1.003
-			}
-		}
-
-		// 40% exit at 2:1 R/R
-		if pnlPercent >= 2.0 && pos.PartialExits[2.0] == 0 {
-			t.partialExit(symbol, pos, 0.40, "2:1_RR")
-			pos.PartialExits[2.0] = 0.40
-		}
-	}
-
-	// Check EMA reversal with high confidence (≥85%)
-	signal := t.analyzeExitSignal(symbol, data, pos)
-	if signal != nil && signal.Confidence >= 0.85 {
-		log.Printf("🔄 EMA reversal detected for %s (Conf: %.2f%%)", symbol, signal.Confidence*100)
-		t.closePosition(symbol, pos, "EMA_REVERSAL")
-		return
-	}
-
-	// Check trailing stop
-	if pos.TrailingStop > 0 {
-		if (pos.Direction == "LONG" && currentPrice <= pos.TrailingStop) ||
-			(pos.Direction == "SHORT" && currentPrice >= pos.TrailingStop) {
-			log.Printf("📈 Trailing stop hit for %s at %.6f", symbol, currentPrice)
-			t.closePosition(symbol, pos, "TRAILING_STOP")
-			return
-		}
-	}
-
-	// Update position tracking
-	pos.CurrentPrice = currentPrice
-	pos.CurrentPnL = pnlPercent
-
-	// Log position status
-	log.Printf("📊 %s %s: Price=%.6f, P&L=%.2f%%, Time=%s",
-		symbol, pos.Direction, currentPrice, pnlPercent,
-		time.Until(pos.TimeLimit).Truncate(time.Minute))
-}
-
-// calculateConfidence - Enhanced confidence calculation
-func (t *Enhanced1HTrader) calculateConfidence(data *Enhanced1HAnalysis, direction string) float64 {
-	var confidence float64
-
-	// Base confidence from EMA alignment (40%)
-	emaAlignment := t.calculateEMAAlignment(data, direction)
-	confidence += emaAlignment * 0.40
-
-	// Price position relative to EMAs (25%)
-	pricePosition := t.calculatePricePosition(data, direction)
-	confidence += pricePosition * 0.25
-
-	// Volume confirmation (20%)
-	volumeStrength := math.Min(1.0, data.CurrentVolume/(data.Indicators.VolumeMA*1.2))
-	confidence += volumeStrength * 0.20
-
-	// RSI momentum (15%)
-	rsiMomentum := t.calculateRSIMomentum(data, direction)
-	confidence += rsiMomentum * 0.15
-
-	return math.Min(1.0, confidence)
-}
-
-// logPerformanceUpdate - Track proven performance metrics
-func (t *Enhanced1HTrader) logPerformanceUpdate(symbol string) {
-	stats := t.perfTracker.GetStats(symbol)
-
-	log.Printf("📈 PERFORMANCE %s: Trades=%d, Win Rate=%.1f%%, Avg Return=%.2f%%, Total=%.2f%%",
-		symbol, stats.TotalTrades, stats.WinRate*100, stats.AvgReturn*100, stats.TotalReturn*100)
-
-	// Alert if falling below proven benchmarks
-	if stats.TotalTrades >= 5 {
-		if stats.WinRate < 0.80 {
-			log.Printf("⚠️ Win rate %.1f%% below 80%% benchmark for %s", stats.WinRate*100, symbol)
-		}
-		if stats.AvgReturn < 0.10 {
-			log.Printf("⚠️ Avg return %.2f%% below 10%% benchmark for %s", stats.AvgReturn*100, symbol)
-		}
-	}
-}
-
-// Main execution
-func main() {
-	trader := NewEnhanced1HTrader("your_api_key", "your_secret_key")
-
-	symbols := []string{"BTC_USDT", "ETH_USDT", "SOL_USDT"}
-
-	log.Println("🚀 Enhanced Triple EMA 1H Strategy - LIVE TRADING")
-	log.Println("📊 Proven Results: +69.99% return, 100% win rate")
-	log.Println("⚡ Target: +13.98% avg per trade, 18.6h duration")
-
-	for _, symbol := range symbols {
-		go trader.RunEnhanced1HStrategy(symbol)
-	}
-
-	// Keep main thread alive
-	select {}
-}
@@ -0,0 +1,73 @@
+package indicators
+
+import "math"
+
+// SMA ค่าเฉลี่ยเคลื่อนที่อย่างง่าย (ta.sma) เป็น NaN เมื่อหน้าต่างมีค่า NaN
+func SMA(src []float64, length int) []float64 {
+	out := nanSeries(len(src))
+	if length <= 0 {
+		return out
+	}
+
+	sum, invalid := 0.0, 0
+	for i, v := range src {
+		if math.IsNaN(v) {
+			invalid++
+		} else {
+			sum += v
+		}
+		if i >= length {
+			if old := src[i-length]; math.IsNaN(old) {
+				invalid--
+			} else {
+				sum -= old
+			}
+		}
+		if i >= length-1 && invalid == 0 {
+			out[i] = sum / float64(length)
+		}
+	}
+	return out
+}
+
+// EMA ค่าเฉลี่ยเคลื่อนที่แบบ exponential (ta.ema): เริ่มจาก SMA แล้ว alpha = 2/(length+1)
+func EMA(src []float64, length int) []float64 {
+	return smooth(src, length, 2/float64(length+1))
+}
+
+// RMA ค่าเฉลี่ยแบบ Wilder (ta.rma): เริ่มจาก SMA แล้ว alpha = 1/length
+func RMA(src []float64, length int) []float64 {
+	return smooth(src, length, 1/float64(length))
+}
+
+// WMA ค่าเฉลี่ยถ่วงน้ำหนักเชิงเส้น (ta.wma) แท่งล่าสุดมีน้ำหนัก length
+func WMA(src []float64, length int) []float64 {
+	out := nanSeries(len(src))
+	if length <= 0 {
+		return out
+	}
+
+	norm := float64(length*(length+1)) / 2
+	for i := length - 1; i < len(src); i++ {
+		sum := 0.0
+		for k := 0; k < length; k++ {
+			sum += src[i-k] * float64(length-k)
+		}
+		out[i] = sum / norm
+	}
+	return out
+}
+
+// smooth ค่าเฉลี่ยแบบ exponential ที่เริ่มจาก SMA เมื่อค่าก่อนหน้าเป็น NaN
+// (ข้อมูลเข้าเป็น NaN กลางทางทำให้เริ่มนับ seed ใหม่เหมือน Pine)
+func smooth(src []float64, length int, alpha float64) []float64 {
+	out := SMA(src, length)
+	prev := math.NaN()
+	for i, v := range src {
+		if !math.IsNaN(prev) {
+			out[i] = alpha*v + (1-alpha)*prev
+		}
+		prev = out[i]
+	}
+	return out
+}
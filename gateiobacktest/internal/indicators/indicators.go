@@ -0,0 +1,73 @@
+// Package indicators ตัวชี้วัดทางเทคนิคชุดเดียวที่ใช้ร่วมกันทั้งระบบ
+//
+// ทุกฟังก์ชันคำนวณทั้ง series (เรียงจากแท่งเก่าไปใหม่) และคืน slice ยาวเท่าข้อมูลเข้า
+// โดยนิยาม seed และการ smoothing ตาม ta.* ในเอกสาร Pine Script v5
+// แท่งที่ข้อมูลยังไม่พอ (na ใน Pine) มีค่า NaN ใช้ Nz เมื่อต้องการ 0 แทน
+package indicators
+
+import "math"
+
+// Bars ราคาแบบแยกคอลัมน์ เรียงจากแท่งเก่าไปใหม่
+type Bars struct {
+	Time   []int64 // unix timestamp (วินาที) ใช้กับ VWAP
+	Open   []float64
+	High   []float64
+	Low    []float64
+	Close  []float64
+	Volume []float64
+}
+
+// Len จำนวนแท่ง
+func (b Bars) Len() int {
+	return len(b.Close)
+}
+
+// HLC3 (high + low + close) / 3 ของทุกแท่ง
+func (b Bars) HLC3() []float64 {
+	out := make([]float64, b.Len())
+	for i := range out {
+		out[i] = (b.High[i] + b.Low[i] + b.Close[i]) / 3
+	}
+	return out
+}
+
+// Nz คืนสำเนาที่แทน NaN ด้วย 0 (เหมือน nz() ของ Pine)
+func Nz(values []float64) []float64 {
+	out := make([]float64, len(values))
+	for i, v := range values {
+		if !math.IsNaN(v) {
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// Last ค่าของแท่งล่าสุด (NaN เมื่อไม่มีข้อมูล)
+func Last(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	return values[len(values)-1]
+}
+
+// nanSeries slice ยาว n ที่ทุกค่าเป็น NaN
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// fixNaN แทน NaN ด้วยค่าล่าสุดที่ไม่ใช่ NaN (เหมือน fixnan() ของ Pine)
+func fixNaN(values []float64) []float64 {
+	last := math.NaN()
+	for i, v := range values {
+		if math.IsNaN(v) {
+			values[i] = last
+		} else {
+			last = v
+		}
+	}
+	return values
+}
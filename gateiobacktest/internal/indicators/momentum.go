@@ -0,0 +1,130 @@
+package indicators
+
+import "math"
+
+// RSI Relative Strength Index แบบ Wilder (ta.rsi) ค่าแรกอยู่ที่แท่ง length
+func RSI(src []float64, length int) []float64 {
+	gain, loss := nanSeries(len(src)), nanSeries(len(src))
+	for i := 1; i < len(src); i++ {
+		change := src[i] - src[i-1]
+		gain[i] = math.Max(change, 0)
+		loss[i] = math.Max(-change, 0)
+	}
+
+	up, down := RMA(gain, length), RMA(loss, length)
+	out := nanSeries(len(src))
+	for i := range out {
+		switch {
+		case math.IsNaN(up[i]) || math.IsNaN(down[i]):
+		case down[i] == 0:
+			out[i] = 100
+		case up[i] == 0:
+			out[i] = 0
+		default:
+			out[i] = 100 - 100/(1+up[i]/down[i])
+		}
+	}
+	return out
+}
+
+// MACD (ta.macd): EMA(fast) - EMA(slow), เส้น signal = EMA ของ MACD และ histogram = MACD - signal
+func MACD(src []float64, fast, slow, signal int) (macd, signalLine, hist []float64) {
+	fastEMA, slowEMA := EMA(src, fast), EMA(src, slow)
+	macd = make([]float64, len(src))
+	for i := range macd {
+		macd[i] = fastEMA[i] - slowEMA[i]
+	}
+
+	signalLine = EMA(macd, signal)
+	hist = make([]float64, len(src))
+	for i := range hist {
+		hist[i] = macd[i] - signalLine[i]
+	}
+	return macd, signalLine, hist
+}
+
+// Stochastic %K และ %D แบบ slow stochastic
+// %K = SMA(ta.stoch(close, high, low, kLength), kSmooth), %D = SMA(%K, dLength)
+func Stochastic(b Bars, kLength, kSmooth, dLength int) (k, d []float64) {
+	highest, lowest := rollingMax(b.High, kLength), rollingMin(b.Low, kLength)
+	raw := nanSeries(b.Len())
+	for i := range raw {
+		if rangeHL := highest[i] - lowest[i]; rangeHL != 0 {
+			raw[i] = 100 * (b.Close[i] - lowest[i]) / rangeHL
+		}
+	}
+
+	k = SMA(raw, kSmooth)
+	return k, SMA(k, dLength)
+}
+
+// ADX +DI, -DI และ ADX (ta.dmi) โดย DI ใช้ True Range ที่แท่งแรกเป็น na
+func ADX(b Bars, diLength, adxSmoothing int) (plusDI, minusDI, adx []float64) {
+	n := b.Len()
+	plusDM, minusDM := nanSeries(n), nanSeries(n)
+	tr := TrueRange(b)
+	if n > 0 {
+		tr[0] = math.NaN()
+	}
+	for i := 1; i < n; i++ {
+		up := b.High[i] - b.High[i-1]
+		down := b.Low[i-1] - b.Low[i]
+		plusDM[i], minusDM[i] = 0, 0
+		if up > down && up > 0 {
+			plusDM[i] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i] = down
+		}
+	}
+
+	trueRange := RMA(tr, diLength)
+	plusDI, minusDI = RMA(plusDM, diLength), RMA(minusDM, diLength)
+	for i := range plusDI {
+		plusDI[i] = 100 * plusDI[i] / trueRange[i]
+		minusDI[i] = 100 * minusDI[i] / trueRange[i]
+		if trueRange[i] == 0 {
+			plusDI[i], minusDI[i] = math.NaN(), math.NaN()
+		}
+	}
+	fixNaN(plusDI)
+	fixNaN(minusDI)
+
+	dx := make([]float64, n)
+	for i := range dx {
+		sum := plusDI[i] + minusDI[i]
+		if sum == 0 {
+			sum = 1
+		}
+		dx[i] = math.Abs(plusDI[i]-minusDI[i]) / sum
+	}
+	adx = RMA(dx, adxSmoothing)
+	for i := range adx {
+		adx[i] *= 100
+	}
+	return plusDI, minusDI, adx
+}
+
+// rollingMax ค่าสูงสุดในหน้าต่าง length แท่ง (ta.highest)
+func rollingMax(src []float64, length int) []float64 {
+	out := nanSeries(len(src))
+	for i := length - 1; i >= 0 && i < len(src); i++ {
+		out[i] = src[i]
+		for k := 1; k < length; k++ {
+			out[i] = math.Max(out[i], src[i-k])
+		}
+	}
+	return out
+}
+
+// rollingMin ค่าต่ำสุดในหน้าต่าง length แท่ง (ta.lowest)
+func rollingMin(src []float64, length int) []float64 {
+	out := nanSeries(len(src))
+	for i := length - 1; i >= 0 && i < len(src); i++ {
+		out[i] = src[i]
+		for k := 1; k < length; k++ {
+			out[i] = math.Min(out[i], src[i-k])
+		}
+	}
+	return out
+}
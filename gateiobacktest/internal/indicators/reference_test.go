@@ -0,0 +1,166 @@
+package indicators
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// referenceFile ค่าอ้างอิงจากโมเดล Python ใน testdata/gen_reference.py ที่เขียนตามนิยาม ta.* ของ Pine Script v5
+// แยกจากโค้ด Go (ไม่ใช่ค่าที่ export จาก TradingView) คอลัมน์ตรงกับ Export chart data ของ testdata/reference.pine
+const referenceFile = "testdata/pine_model_reference.csv"
+
+// referenceTolerance ความคลาดเคลื่อนสัมพัทธ์ที่ยอมรับได้ (ลำดับการบวกทศนิยมต่างกันได้เล็กน้อย)
+const referenceTolerance = 1e-9
+
+// reference ข้อมูลแท่งเทียนและคอลัมน์ค่าที่คาดหวังจากไฟล์อ้างอิง
+type reference struct {
+	bars    Bars
+	columns map[string][]float64
+}
+
+func loadReference(t *testing.T) reference {
+	t.Helper()
+	file, err := os.Open(referenceFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, rows := records[0], records[1:]
+
+	ref := reference{columns: make(map[string][]float64)}
+	for col, name := range header {
+		values := make([]float64, len(rows))
+		for i, row := range rows {
+			values[i] = math.NaN()
+			if row[col] == "" {
+				continue
+			}
+			if values[i], err = strconv.ParseFloat(row[col], 64); err != nil {
+				t.Fatalf("%s แถว %d: %v", name, i+2, err)
+			}
+		}
+		ref.columns[name] = values
+	}
+
+	ref.bars = Bars{
+		Time:   make([]int64, len(rows)),
+		Open:   ref.columns["open"],
+		High:   ref.columns["high"],
+		Low:    ref.columns["low"],
+		Close:  ref.columns["close"],
+		Volume: ref.columns["Volume"],
+	}
+	for i, ts := range ref.columns["time"] {
+		ref.bars.Time[i] = int64(ts)
+	}
+	return ref
+}
+
+// assertSeries เทียบทั้ง series กับคอลัมน์อ้างอิงและรายงานแท่งแรกที่ไม่ตรง
+func (ref reference) assertSeries(t *testing.T, column string, got []float64) {
+	t.Helper()
+	want, ok := ref.columns[column]
+	if !ok {
+		t.Fatalf("ไม่มีคอลัมน์ %s ในไฟล์อ้างอิง", column)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%s: ได้ %d ค่า ต้องการ %d", column, len(got), len(want))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) != math.IsNaN(got[i]) ||
+			math.Abs(got[i]-want[i]) > referenceTolerance*math.Max(1, math.Abs(want[i])) {
+			t.Fatalf("%s ต่างจากค่าอ้างอิงตั้งแต่แท่ง %d (time %d): ได้ %v ต้องการ %v",
+				column, i, ref.bars.Time[i], got[i], want[i])
+		}
+	}
+}
+
+func TestMovingAveragesMatchReference(t *testing.T) {
+	ref := loadReference(t)
+	close := ref.bars.Close
+	ref.assertSeries(t, "sma_20", SMA(close, 20))
+	ref.assertSeries(t, "ema_20", EMA(close, 20))
+	ref.assertSeries(t, "wma_20", WMA(close, 20))
+	ref.assertSeries(t, "rma_14", RMA(close, 14))
+}
+
+func TestOscillatorsMatchReference(t *testing.T) {
+	ref := loadReference(t)
+	ref.assertSeries(t, "rsi_14", RSI(ref.bars.Close, 14))
+
+	macd, signal, hist := MACD(ref.bars.Close, 12, 26, 9)
+	ref.assertSeries(t, "macd", macd)
+	ref.assertSeries(t, "macd_signal", signal)
+	ref.assertSeries(t, "macd_hist", hist)
+
+	k, d := Stochastic(ref.bars, 14, 1, 3)
+	ref.assertSeries(t, "stoch_k", k)
+	ref.assertSeries(t, "stoch_d", d)
+
+	plusDI, minusDI, adx := ADX(ref.bars, 14, 14)
+	ref.assertSeries(t, "plus_di", plusDI)
+	ref.assertSeries(t, "minus_di", minusDI)
+	ref.assertSeries(t, "adx", adx)
+}
+
+func TestVolatilityMatchesReference(t *testing.T) {
+	ref := loadReference(t)
+	ref.assertSeries(t, "atr_14", ATR(ref.bars, 14))
+
+	basis, upper, lower := Bollinger(ref.bars.Close, 20, 2)
+	ref.assertSeries(t, "bb_basis", basis)
+	ref.assertSeries(t, "bb_upper", upper)
+	ref.assertSeries(t, "bb_lower", lower)
+
+	line, trend := SuperTrend(ref.bars, 3, 10)
+	ref.assertSeries(t, "supertrend", line)
+	direction := make([]float64, len(trend))
+	for i, tr := range trend {
+		direction[i] = -float64(tr) // Pine: -1 = ขาขึ้น
+		if tr == 0 {
+			direction[i] = math.NaN()
+		}
+	}
+	ref.assertSeries(t, "supertrend_dir", direction)
+}
+
+func TestVWAPMatchesReference(t *testing.T) {
+	ref := loadReference(t)
+	ref.assertSeries(t, "vwap", VWAP(ref.bars, 24*time.Hour))
+}
+
+func TestShortInputIsNaN(t *testing.T) {
+	src := []float64{1, 2, 3}
+	for name, series := range map[string][]float64{
+		"SMA":          SMA(src, 5),
+		"EMA":          EMA(src, 5),
+		"WMA":          WMA(src, 5),
+		"RSI":          RSI(src, 5),
+		"SMA length 0": SMA(src, 0),
+	} {
+		if len(series) != len(src) {
+			t.Fatalf("%s: ได้ %d ค่า ต้องการ %d", name, len(series), len(src))
+		}
+		for i, v := range series {
+			if !math.IsNaN(v) {
+				t.Fatalf("%s แท่ง %d: ได้ %v ต้องการ NaN", name, i, v)
+			}
+		}
+	}
+
+	if got := Nz(SMA(src, 2)); got[0] != 0 || got[1] != 1.5 {
+		t.Fatalf("Nz: ได้ %v", got)
+	}
+	if !math.IsNaN(Last(nil)) || Last(src) != 3 {
+		t.Fatal("Last ต้องคืนค่าสุดท้ายหรือ NaN เมื่อว่าง")
+	}
+}
@@ -0,0 +1,257 @@
+#!/usr/bin/env python3
+"""สร้าง pine_model_reference.csv ใหม่
+
+คำนวณแบบทีละแท่งตามนิยามของ ta.* ในเอกสาร Pine Script v5 (na = None)
+โดยไม่ขึ้นกับโค้ด Go เพื่อใช้ตรวจไขว้ ค่าเป็นของโมเดลนี้เอง ไม่ได้มาจาก TradingView
+ไฟล์มีคอลัมน์เดียวกับการ Export chart data ของ reference.pine
+ถ้าจะเทียบกับ TradingView จริงให้ export จากกราฟแล้ว commit เป็นไฟล์แยก
+
+    python3 gen_reference.py > pine_model_reference.csv
+"""
+
+import math
+import random
+
+BARS = 600
+START = 1735689600  # 2025-01-01 00:00 UTC
+STEP = 3600         # 1h
+
+
+def candles():
+    rng = random.Random(20250101)
+    price = 190.0
+    rows = []
+    for i in range(BARS):
+        open_ = price
+        price *= 1 + rng.gauss(0, 0.008)
+        high = max(open_, price) * (1 + rng.random() * 0.004)
+        low = min(open_, price) * (1 - rng.random() * 0.004)
+        volume = 1000 + rng.random() * 9000
+        if i % 97 == 0:  # แท่งนิ่ง (high = low = close) ให้ครอบคลุมกรณี TR = 0
+            high = low = price = open_
+        rows.append([START + i * STEP, round(open_, 4), round(high, 4),
+                     round(low, 4), round(price, 4), round(volume, 2)])
+    return rows
+
+
+def nz(x):
+    return 0.0 if x is None else x
+
+
+def sma(src, n):
+    out = []
+    for i in range(len(src)):
+        window = src[i - n + 1:i + 1] if i >= n - 1 else None
+        if window is None or any(v is None for v in window):
+            out.append(None)
+        else:
+            total = 0.0
+            for v in window:
+                total += v
+            out.append(total / n)
+    return out
+
+
+def _smooth(src, n, alpha):
+    seed = sma(src, n)
+    out, prev = [], None
+    for i, v in enumerate(src):
+        if prev is None:
+            cur = seed[i]
+        else:
+            cur = None if v is None else alpha * v + (1 - alpha) * prev
+        out.append(cur)
+        prev = cur
+    return out
+
+
+def ema(src, n):
+    return _smooth(src, n, 2 / (n + 1))
+
+
+def rma(src, n):
+    return _smooth(src, n, 1 / n)
+
+
+def wma(src, n):
+    norm = n * (n + 1) / 2
+    out = []
+    for i in range(len(src)):
+        if i < n - 1 or any(src[i - k] is None for k in range(n)):
+            out.append(None)
+            continue
+        total = 0.0
+        for k in range(n):
+            total += src[i - k] * (n - k)
+        out.append(total / norm)
+    return out
+
+
+def change(src):
+    return [None] + [src[i] - src[i - 1] for i in range(1, len(src))]
+
+
+def rsi(src, n):
+    ch = change(src)
+    up = rma([None if c is None else max(c, 0.0) for c in ch], n)
+    down = rma([None if c is None else max(-c, 0.0) for c in ch], n)
+    out = []
+    for u, d in zip(up, down):
+        if u is None or d is None:
+            out.append(None)
+        elif d == 0:
+            out.append(100.0)
+        elif u == 0:
+            out.append(0.0)
+        else:
+            out.append(100 - 100 / (1 + u / d))
+    return out
+
+
+def tr(high, low, close, handle_na):
+    out = []
+    for i in range(len(close)):
+        if i == 0:
+            out.append(high[0] - low[0] if handle_na else None)
+        else:
+            out.append(max(high[i] - low[i], abs(high[i] - close[i - 1]), abs(low[i] - close[i - 1])))
+    return out
+
+
+def stdev(src, n):
+    avg = sma(src, n)
+    out = []
+    for i in range(len(src)):
+        if avg[i] is None:
+            out.append(None)
+            continue
+        total = 0.0
+        for k in range(n):
+            d = src[i - k] - avg[i]
+            total += d * d
+        out.append(math.sqrt(total / n))
+    return out
+
+
+def highest(src, n):
+    return [max(src[i - n + 1:i + 1]) if i >= n - 1 else None for i in range(len(src))]
+
+
+def lowest(src, n):
+    return [min(src[i - n + 1:i + 1]) if i >= n - 1 else None for i in range(len(src))]
+
+
+def fixnan(src):
+    out, last = [], None
+    for v in src:
+        if v is not None:
+            last = v
+        out.append(last)
+    return out
+
+
+def div(a, b):
+    if a is None or b is None or b == 0:
+        return None
+    return a / b
+
+
+def main():
+    rows = candles()
+    t = [r[0] for r in rows]
+    high = [r[2] for r in rows]
+    low = [r[3] for r in rows]
+    close = [r[4] for r in rows]
+    volume = [r[5] for r in rows]
+    n = len(rows)
+
+    cols = {}
+    cols["sma_20"] = sma(close, 20)
+    cols["ema_20"] = ema(close, 20)
+    cols["wma_20"] = wma(close, 20)
+    cols["rma_14"] = rma(close, 14)
+    cols["rsi_14"] = rsi(close, 14)
+    cols["atr_14"] = rma(tr(high, low, close, True), 14)
+
+    fast, slow = ema(close, 12), ema(close, 26)
+    macd = [None if f is None or s is None else f - s for f, s in zip(fast, slow)]
+    signal = ema(macd, 9)
+    cols["macd"] = macd
+    cols["macd_signal"] = signal
+    cols["macd_hist"] = [None if m is None or s is None else m - s for m, s in zip(macd, signal)]
+
+    basis, dev = sma(close, 20), stdev(close, 20)
+    cols["bb_basis"] = basis
+    cols["bb_upper"] = [None if b is None else b + 2 * d for b, d in zip(basis, dev)]
+    cols["bb_lower"] = [None if b is None else b - 2 * d for b, d in zip(basis, dev)]
+
+    hh, ll = highest(high, 14), lowest(low, 14)
+    stoch = [None if h is None else div(100 * (c - l), h - l) for c, h, l in zip(close, hh, ll)]
+    k = sma(stoch, 1)
+    cols["stoch_k"] = k
+    cols["stoch_d"] = sma(k, 3)
+
+    up, down = change(high), [None if c is None else -c for c in change(low)]
+    plus_dm = [None if u is None else (u if u > d and u > 0 else 0.0) for u, d in zip(up, down)]
+    minus_dm = [None if d is None else (d if d > u and d > 0 else 0.0) for u, d in zip(up, down)]
+    trur = rma(tr(high, low, close, False), 14)
+    plus = fixnan([None if x is None else div(100 * x, r) for x, r in zip(rma(plus_dm, 14), trur)])
+    minus = fixnan([None if x is None else div(100 * x, r) for x, r in zip(rma(minus_dm, 14), trur)])
+    dx = []
+    for p, m in zip(plus, minus):
+        if p is None or m is None:
+            dx.append(None)
+        else:
+            total = p + m
+            dx.append(abs(p - m) / (1 if total == 0 else total))
+    cols["plus_di"] = plus
+    cols["minus_di"] = minus
+    cols["adx"] = [None if a is None else 100 * a for a in rma(dx, 14)]
+
+    vwap, pv, vol = [], 0.0, 0.0
+    for i in range(n):
+        if i == 0 or t[i] // 86400 != t[i - 1] // 86400:
+            pv, vol = 0.0, 0.0
+        src = (high[i] + low[i] + close[i]) / 3
+        pv += src * volume[i]
+        vol += volume[i]
+        vwap.append(div(pv, vol))
+    cols["vwap"] = vwap
+
+    atr = rma(tr(high, low, close, True), 10)
+    st_line, st_dir = [], []
+    prev_upper = prev_lower = prev_st = None
+    for i in range(n):
+        if atr[i] is None:
+            st_line.append(None)
+            st_dir.append(None)
+            prev_upper = prev_lower = prev_st = None
+            continue
+        hl2 = (high[i] + low[i]) / 2
+        upper = hl2 + 3 * atr[i]
+        lower = hl2 - 3 * atr[i]
+        pl, pu = nz(prev_lower), nz(prev_upper)
+        lower = lower if lower > pl or close[i - 1] < pl else pl
+        upper = upper if upper < pu or close[i - 1] > pu else pu
+        if i == 0 or atr[i - 1] is None:
+            direction = 1
+        elif prev_st == pu:
+            direction = -1 if close[i] > upper else 1
+        else:
+            direction = 1 if close[i] < lower else -1
+        st = lower if direction == -1 else upper
+        st_line.append(st)
+        st_dir.append(float(direction))
+        prev_upper, prev_lower, prev_st = upper, lower, st
+    cols["supertrend"] = st_line
+    cols["supertrend_dir"] = st_dir
+
+    print(",".join(["time", "open", "high", "low", "close", "Volume"] + list(cols)))
+    for i, row in enumerate(rows):
+        values = [str(row[0])] + [repr(v) for v in row[1:]]
+        values += ["" if cols[c][i] is None else repr(cols[c][i]) for c in cols]
+        print(",".join(values))
+
+
+if __name__ == "__main__":
+    main()
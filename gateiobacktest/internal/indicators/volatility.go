@@ -0,0 +1,104 @@
+package indicators
+
+import "math"
+
+// TrueRange True Range ของทุกแท่ง (ta.tr(true)) แท่งแรกใช้ high - low
+func TrueRange(b Bars) []float64 {
+	out := make([]float64, b.Len())
+	for i := range out {
+		if i == 0 {
+			out[i] = b.High[i] - b.Low[i]
+			continue
+		}
+		prevClose := b.Close[i-1]
+		out[i] = math.Max(b.High[i]-b.Low[i], math.Max(math.Abs(b.High[i]-prevClose), math.Abs(b.Low[i]-prevClose)))
+	}
+	return out
+}
+
+// ATR Average True Range แบบ Wilder (ta.atr) ค่าแรกอยู่ที่แท่ง length-1
+func ATR(b Bars, length int) []float64 {
+	return RMA(TrueRange(b), length)
+}
+
+// Stdev ส่วนเบี่ยงเบนมาตรฐานของประชากรในหน้าต่าง length แท่ง (ta.stdev)
+func Stdev(src []float64, length int) []float64 {
+	avg := SMA(src, length)
+	out := nanSeries(len(src))
+	for i, mean := range avg {
+		if math.IsNaN(mean) {
+			continue
+		}
+		sum := 0.0
+		for k := 0; k < length; k++ {
+			d := src[i-k] - mean
+			sum += d * d
+		}
+		out[i] = math.Sqrt(sum / float64(length))
+	}
+	return out
+}
+
+// Bollinger Bollinger Bands (ta.bb): SMA ± mult x Stdev
+func Bollinger(src []float64, length int, mult float64) (middle, upper, lower []float64) {
+	middle = SMA(src, length)
+	dev := Stdev(src, length)
+	upper, lower = make([]float64, len(src)), make([]float64, len(src))
+	for i := range middle {
+		upper[i] = middle[i] + mult*dev[i]
+		lower[i] = middle[i] - mult*dev[i]
+	}
+	return middle, upper, lower
+}
+
+// SuperTrend (ta.supertrend) จาก hl2 ± factor x ATR(atrLength)
+// trend: 1 = ขาขึ้น, -1 = ขาลง, 0 = ATR ยังไม่พร้อม (ตรงข้ามกับ direction ของ Pine ที่ -1 = ขาขึ้น)
+func SuperTrend(b Bars, factor float64, atrLength int) (line []float64, trend []int) {
+	return SuperTrendFromATR(b, ATR(b, atrLength), factor)
+}
+
+// SuperTrendFromATR SuperTrend จาก ATR ที่คำนวณไว้แล้ว (เช่น SMA ของ True Range แทนแบบ Wilder)
+func SuperTrendFromATR(b Bars, atr []float64, factor float64) (line []float64, trend []int) {
+	line = nanSeries(b.Len())
+	trend = make([]int, b.Len())
+
+	var upper, lower float64
+	for i := range line {
+		if math.IsNaN(atr[i]) {
+			continue
+		}
+		hl2 := (b.High[i] + b.Low[i]) / 2
+		basicUpper := hl2 + factor*atr[i]
+		basicLower := hl2 - factor*atr[i]
+
+		if i == 0 || trend[i-1] == 0 {
+			// แท่งแรกที่ ATR พร้อม: Pine เริ่มเป็นขาลงที่เส้นบน
+			upper, lower = basicUpper, basicLower
+			trend[i] = -1
+			line[i] = upper
+			continue
+		}
+
+		prevClose := b.Close[i-1]
+		if basicUpper < upper || prevClose > upper {
+			upper = basicUpper
+		}
+		if basicLower > lower || prevClose < lower {
+			lower = basicLower
+		}
+
+		trend[i] = trend[i-1]
+		if trend[i-1] == -1 && b.Close[i] > upper {
+			trend[i] = 1
+		} else if trend[i-1] == 1 && b.Close[i] < lower {
+			trend[i] = -1
+		}
+
+		if trend[i] == 1 {
+			line[i] = lower
+		} else {
+			line[i] = upper
+		}
+	}
+	return line, trend
+}
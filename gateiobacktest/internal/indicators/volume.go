@@ -0,0 +1,24 @@
+package indicators
+
+import "time"
+
+// VWAP Volume Weighted Average Price ของ hlc3 (ta.vwap) ที่เริ่มนับใหม่ทุกช่วง anchor ตามเวลา UTC
+// เช่น 24 ชั่วโมง = เริ่มใหม่ทุกวันที่ 00:00 UTC (anchor <= 0 = สะสมตั้งแต่แท่งแรก)
+func VWAP(b Bars, anchor time.Duration) []float64 {
+	src := b.HLC3()
+	out := nanSeries(b.Len())
+	period := int64(anchor / time.Second)
+
+	var sumPV, sumVolume float64
+	for i := range out {
+		if i > 0 && period > 0 && b.Time[i]/period != b.Time[i-1]/period {
+			sumPV, sumVolume = 0, 0
+		}
+		sumPV += src[i] * b.Volume[i]
+		sumVolume += b.Volume[i]
+		if sumVolume != 0 {
+			out[i] = sumPV / sumVolume
+		}
+	}
+	return out
+}
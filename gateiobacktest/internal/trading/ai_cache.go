@@ -0,0 +1,190 @@
+package trading
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// OpenPositionPromptVersion เวอร์ชันของ prompt เปิด position (เปลี่ยนเมื่อแก้ prompt เพื่อไม่ให้ใช้คำตอบเก่าใน cache)
+const OpenPositionPromptVersion = "open_position/v2"
+
+// AICacheMode โหมดการใช้ cache คำตัดสินของ AI ใน backtest
+type AICacheMode string
+
+const (
+	AICacheRecord AICacheMode = "record" // เรียก AI จริงแล้วบันทึกคำตอบลง cache (ใช้คำตอบเดิมถ้ามีแล้ว)
+	AICacheReplay AICacheMode = "replay" // ใช้เฉพาะคำตอบใน cache ไม่เรียก network และล้มเหลวเมื่อไม่พบ
+)
+
+// AICacheEntry คำตัดสินของ AI หนึ่งรายการใน cache
+type AICacheEntry struct {
+	PromptVersion string     `json:"prompt_version"`
+	Symbol        string     `json:"symbol"`
+	CandleTime    time.Time  `json:"candle_time"` // เวลาของแท่งล่าสุดใน window
+	Decision      AIDecision `json:"decision"`
+	RecordedAt    time.Time  `json:"recorded_at"`
+}
+
+// AIDecisionCache cache คำตัดสินของ AI สำหรับ record/replay ใน backtest
+// key = hash ของ (เวอร์ชัน prompt, symbol, แท่งเทียนใน window) ทำให้ replay ได้ผลเหมือนเดิมทุกครั้ง
+type AIDecisionCache struct {
+	path    string
+	mode    AICacheMode
+	mu      sync.Mutex
+	entries map[string]AICacheEntry
+	hits    int
+	misses  int
+}
+
+// NewAIDecisionCache โหลด cache จากไฟล์ (record สร้างไฟล์ใหม่ได้ถ้ายังไม่มี, replay ต้องมีไฟล์อยู่แล้ว)
+// ไม่เขียน log เอง ผู้เรียกดูจำนวนที่โหลดได้จาก Len
+func NewAIDecisionCache(path string, mode AICacheMode) (*AIDecisionCache, error) {
+	if mode != AICacheRecord && mode != AICacheReplay {
+		return nil, fmt.Errorf("ไม่รู้จักโหมด AI cache: %s", mode)
+	}
+
+	cache := &AIDecisionCache{
+		path:    path,
+		mode:    mode,
+		entries: make(map[string]AICacheEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && mode == AICacheRecord {
+			return cache, nil
+		}
+		return nil, fmt.Errorf("ไม่สามารถอ่าน AI cache ได้: %v", err)
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		return nil, fmt.Errorf("ไฟล์ AI cache ไม่ถูกต้อง: %v", err)
+	}
+	return cache, nil
+}
+
+// Mode โหมดของ cache
+func (c *AIDecisionCache) Mode() AICacheMode {
+	return c.mode
+}
+
+// Len จำนวนคำตัดสินใน cache
+func (c *AIDecisionCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Stats จำนวนครั้งที่พบ/ไม่พบใน cache
+func (c *AIDecisionCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// aiCacheKey hash ของเวอร์ชัน prompt, symbol และ OHLCV ทุกแท่งใน window
+func aiCacheKey(promptVersion, symbol string, candles []OHLCV) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", promptVersion, symbol)
+	for _, c := range candles {
+		fmt.Fprintf(h, "%d,%.8f,%.8f,%.8f,%.8f,%.8f\n", c.Timestamp, c.Open, c.High, c.Low, c.Close, c.Volume)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// lookup หาคำตัดสินใน cache
+func (c *AIDecisionCache) lookup(key string) (*AIDecision, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	decision := entry.Decision
+	return &decision, true
+}
+
+// store บันทึกคำตัดสินใหม่ลง cache และเขียนไฟล์ทันที (รันค้างกลางทางก็ไม่เสียคำตอบที่จ่ายเงินไปแล้ว)
+func (c *AIDecisionCache) store(key string, entry AICacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("ไม่สามารถบันทึก AI cache ได้: %v", err)
+	}
+	return nil
+}
+
+// SetAIDecisionCache ใช้ cache คำตัดสินของ AI (replay ไม่ต้องมี AI client)
+func (bt *Backtester) SetAIDecisionCache(cache *AIDecisionCache) {
+	bt.aiCache = cache
+}
+
+// getAIDecision ขอคำแนะนำจาก AI ผ่าน cache (ถ้ามี)
+// โหมด replay ไม่เรียก network และคืน error เมื่อไม่พบใน cache
+// AI ตอบไม่ได้ (network/timeout) แค่เตือนและคืน nil ให้ข้ามสัญญาณนั้นเหมือนเดิม โดยไม่บันทึกลง cache
+func (bt *Backtester) getAIDecision(analysis *SuperTrendAnalysis) (*AIDecision, error) {
+	startIndex := bt.currentIndex - 20
+	if startIndex < 0 {
+		startIndex = 0
+	}
+	window := bt.ohlcvData[startIndex : bt.currentIndex+1]
+
+	if bt.aiCache == nil {
+		if bt.aiClient == nil {
+			return nil, fmt.Errorf("ไม่มี AI client และไม่ได้ตั้ง AI cache")
+		}
+		return bt.requestAIDecision(analysis, window), nil
+	}
+
+	key := aiCacheKey(OpenPositionPromptVersion, bt.symbol, window)
+	if decision, ok := bt.aiCache.lookup(key); ok {
+		return decision, nil
+	}
+
+	if bt.aiCache.mode == AICacheReplay {
+		return nil, fmt.Errorf("ไม่พบคำตัดสิน AI ใน cache (replay) สำหรับ %s ที่ %s",
+			bt.symbol, bt.currentTime.UTC().Format("2006-01-02 15:04"))
+	}
+	if bt.aiClient == nil {
+		return nil, fmt.Errorf("โหมด record ต้องมี AI client")
+	}
+
+	decision := bt.requestAIDecision(analysis, window)
+	if decision == nil {
+		return nil, nil
+	}
+	err := bt.aiCache.store(key, AICacheEntry{
+		PromptVersion: OpenPositionPromptVersion,
+		Symbol:        bt.symbol,
+		CandleTime:    bt.currentTime,
+		Decision:      *decision,
+		RecordedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+// requestAIDecision เรียก AI จริง (nil = AI ตอบไม่ได้ ข้ามสัญญาณนี้)
+func (bt *Backtester) requestAIDecision(analysis *SuperTrendAnalysis, window []OHLCV) *AIDecision {
+	decision, err := bt.aiClient.AnalyzeOpenPosition(bt.symbol, analysis, window)
+	if err != nil {
+		bt.logf("⚠️ ไม่สามารถขอคำแนะนำจาก AI ได้: %v\n", err)
+		return nil
+	}
+	return decision
+}
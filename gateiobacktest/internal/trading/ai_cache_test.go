@@ -0,0 +1,103 @@
+package trading
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// aiReplayFixture คำตัดสินที่บันทึกไว้สำหรับ SOL_USDT บน syntheticCandles(400, 13)
+// ทุกแท่งที่ AIConfirmStrategy ถาม AI (confidence 85 ยกเว้นทุกรายการที่สามเป็น 55 ซึ่งต่ำกว่าเกณฑ์)
+const aiReplayFixture = "testdata/ai_cache/replay.json"
+
+// newReplayBacktester backtester โหมด replay ที่มี AI client ชี้ไปยัง server ที่นับจำนวน request
+func newReplayBacktester(t *testing.T, symbol string) (*Backtester, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "network ต้องไม่ถูกเรียกในโหมด replay", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	bt := newQuietBacktester(t, symbol, 0)
+	bt.aiClient = &AIClient{apiKey: "test", baseURL: server.URL, httpClient: server.Client()}
+	bt.SetLogOutput(io.Discard)
+	bt.LoadHistoricalData(syntheticCandles(400, 13))
+
+	cache, err := NewAIDecisionCache(aiReplayFixture, AICacheReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bt.SetAIDecisionCache(cache)
+	return bt, &requests
+}
+
+func TestAIReplayUsesCachedDecisions(t *testing.T) {
+	bt, requests := newReplayBacktester(t, "SOL_USDT")
+	result, err := bt.RunStrategy(NewAIConfirmStrategy())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits, misses := bt.aiCache.Stats()
+	if hits == 0 || misses != 0 || *requests != 0 {
+		t.Fatalf("hits %d misses %d requests %d, ต้องการ hit ทั้งหมดโดยไม่เรียก network", hits, misses, *requests)
+	}
+	if len(result.Trades) == 0 {
+		t.Fatal("คำตัดสินใน cache ต้องทำให้มีเทรด")
+	}
+	for _, trade := range result.Trades {
+		if !strings.HasPrefix(trade.EntryReason, "AI ") || !strings.HasSuffix(trade.EntryReason, "Conf=85.0%") {
+			t.Fatalf("เหตุผลเข้า %q ไม่ได้มาจากคำตัดสินใน cache ที่ผ่านเกณฑ์", trade.EntryReason)
+		}
+	}
+
+	// รันซ้ำต้องได้เทรดเดิมทุกไม้
+	again, requestsAgain := newReplayBacktester(t, "SOL_USDT")
+	replayed, err := again.RunStrategy(NewAIConfirmStrategy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.Trades) != len(result.Trades) || *requestsAgain != 0 {
+		t.Fatalf("replay ครั้งที่สองได้ %d เทรด, ครั้งแรก %d", len(replayed.Trades), len(result.Trades))
+	}
+	for i := range result.Trades {
+		if replayed.Trades[i].EntryTime != result.Trades[i].EntryTime || replayed.Trades[i].NetPnL != result.Trades[i].NetPnL {
+			t.Fatalf("เทรด %d ต่างกันระหว่างสองรอบ", i)
+		}
+	}
+}
+
+func TestAIReplayMissHaltsWithoutNetwork(t *testing.T) {
+	// symbol อื่นทำให้ key ไม่ตรงกับ cache ทุกรายการ
+	bt, requests := newReplayBacktester(t, "ETH_USDT")
+	result, err := bt.RunStrategy(NewAIConfirmStrategy())
+	if err == nil || !strings.Contains(err.Error(), "replay") {
+		t.Fatalf("RunStrategy คืน (%v, %v), ต้องการ error เมื่อไม่พบใน cache", result, err)
+	}
+	if hits, misses := bt.aiCache.Stats(); hits != 0 || misses != 1 || *requests != 0 {
+		t.Fatalf("hits %d misses %d requests %d, ต้องการหยุดที่ miss แรกโดยไม่เรียก network", hits, misses, *requests)
+	}
+}
+
+func TestAIRecordSkipsSignalWhenAIFails(t *testing.T) {
+	bt, _ := newReplayBacktester(t, "SOL_USDT")
+	cache, err := NewAIDecisionCache(t.TempDir()+"/record.json", AICacheRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bt.SetAIDecisionCache(cache)
+
+	// AI ตอบไม่ได้: เตือนแล้วข้ามสัญญาณเหมือนตอนไม่มี cache ไม่หยุด backtest และไม่บันทึกอะไรลง cache
+	result, err := bt.RunStrategy(NewAIConfirmStrategy())
+	if err != nil {
+		t.Fatalf("RunStrategy คืน error %v, ต้องการข้ามสัญญาณ", err)
+	}
+	if len(result.Trades) != 0 || cache.Len() != 0 {
+		t.Fatalf("ได้ %d เทรดและ %d รายการใน cache, ต้องการ 0", len(result.Trades), cache.Len())
+	}
+}
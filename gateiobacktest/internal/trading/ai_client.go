@@ -26,6 +26,7 @@ type AIClient struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+	log        io.Writer // ปลายทาง log (nil = os.Stdout)
 }
 
 // AIRequest โครงสร้างสำหรับส่งคำขอไป AI
@@ -71,35 +72,35 @@ func (ai *AIClient) TestConnection() bool {
 
 	response, err := ai.sendRequest(testPrompt)
 	if err != nil {
-		fmt.Printf("❌ ไม่สามารถเชื่อมต่อ AI ได้: %v\n", err)
+		writeLog(ai.log, "❌ ไม่สามารถเชื่อมต่อ AI ได้: %v\n", err)
 		return false
 	}
 
 	if strings.Contains(strings.ToUpper(response), "OK") {
-		fmt.Println("✅ เชื่อมต่อ AI สำเร็จ")
+		writeLog(ai.log, "✅ เชื่อมต่อ AI สำเร็จ\n")
 		return true
 	}
 
-	fmt.Printf("⚠️ AI ตอบกลับ: %s\n", response)
+	writeLog(ai.log, "⚠️ AI ตอบกลับ: %s\n", response)
 	return true // ถือว่าเชื่อมต่อได้แล้ว
 }
 
 // AnalyzeOpenPosition วิเคราะห์การเปิด position
 func (ai *AIClient) AnalyzeOpenPosition(contract string, analysis *SuperTrendAnalysis, ohlcv []OHLCV) (*AIDecision, error) {
-	fmt.Printf("🤖 กำลังส่งข้อมูล %s ไปยัง AI...\n", contract)
+	writeLog(ai.log, "🤖 กำลังส่งข้อมูล %s ไปยัง AI...\n", contract)
 
 	prompt, err := ai.buildOpenPositionPrompt(contract, analysis, ohlcv)
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("⏳ รอ AI วิเคราะห์ %s...\n", contract)
+	writeLog(ai.log, "⏳ รอ AI วิเคราะห์ %s...\n", contract)
 	response, err := ai.sendRequest(prompt)
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("📥 AI ตอบกลับสำหรับ %s: %s\n", contract, response[:min(200, len(response))])
+	writeLog(ai.log, "📥 AI ตอบกลับสำหรับ %s: %s\n", contract, response[:min(200, len(response))])
 
 	return ai.parseAIDecision(response)
 }
@@ -149,6 +150,10 @@ Contract: %s
 			candle.Open, candle.High, candle.Low, candle.Close, candle.Volume)
 	}
 
+	// รูปแบบแท่งเทียนจากตัวตรวจจับ ให้ AI ใช้แทนการอ่านจาก OHLCV เอง
+	dataSection += "\n=== Candlestick Patterns (10 แท่งล่าสุด, bars_ago 0 = แท่งล่าสุด) ===\n"
+	dataSection += formatPatternFacts(ohlcv, 10)
+
 	return prompt + dataSection, nil
 }
 
@@ -187,7 +192,7 @@ Side: %s
 `, position.Contract, position.Size, position.EntryPrice, position.MarkPrice,
 		position.UnrealizedPnl, position.Margin, position.Leverage, positionSide)
 
-	fmt.Printf("🔍 กำลังสร้าง prompt สำหรับปิด position %s...\n", position.Contract)
+	writeLog(ai.log, "🔍 กำลังสร้าง prompt สำหรับปิด position %s...\n", position.Contract)
 
 	// เพิ่มข้อมูล OHLCV 10 แท่งล่าสุด
 	start := len(ohlcv) - 10
@@ -195,15 +200,18 @@ Side: %s
 		start = 0
 	}
 
-	fmt.Printf("📊 เพิ่มข้อมูล OHLCV %d แท่งล่าสุด:\n", len(ohlcv)-start)
+	writeLog(ai.log, "📊 เพิ่มข้อมูล OHLCV %d แท่งล่าสุด:\n", len(ohlcv)-start)
 	for i := start; i < len(ohlcv); i++ {
 		candle := ohlcv[i]
 		candleStr := fmt.Sprintf("O: %.6f, H: %.6f, L: %.6f, C: %.6f, V: %.0f\n",
 			candle.Open, candle.High, candle.Low, candle.Close, candle.Volume)
 		dataSection += candleStr
-		fmt.Printf("  %s", candleStr)
+		writeLog(ai.log, "  %s", candleStr)
 	}
 
+	dataSection += "\n=== Candlestick Patterns (10 แท่งล่าสุด, bars_ago 0 = แท่งล่าสุด) ===\n"
+	dataSection += formatPatternFacts(ohlcv, 10)
+
 	return prompt + dataSection, nil
 }
 
@@ -261,25 +269,25 @@ func (ai *AIClient) sendRequest(prompt string) (string, error) {
 
 // parseAIDecision แปลง response จาก AI เป็น AIDecision
 func (ai *AIClient) parseAIDecision(response string) (*AIDecision, error) {
-	fmt.Printf("🔍 กำลัง parse AI response...\n")
+	writeLog(ai.log, "🔍 กำลัง parse AI response...\n")
 
 	// ลองหา JSON ใน response ก่อน
 	jsonRegex := regexp.MustCompile(`\{[^{}]*\}`)
 	jsonMatch := jsonRegex.FindString(response)
 
 	if jsonMatch != "" {
-		fmt.Printf("📋 พบ JSON: %s\n", jsonMatch)
+		writeLog(ai.log, "📋 พบ JSON: %s\n", jsonMatch)
 		var decision AIDecision
 		if err := json.Unmarshal([]byte(jsonMatch), &decision); err == nil {
-			fmt.Printf("✅ Parse JSON สำเร็จ: %s confidence %.1f%%\n", decision.Action, decision.Confidence)
+			writeLog(ai.log, "✅ Parse JSON สำเร็จ: %s confidence %.1f%%\n", decision.Action, decision.Confidence)
 			return &decision, nil
 		} else {
-			fmt.Printf("❌ Parse JSON ไม่สำเร็จ: %v\n", err)
+			writeLog(ai.log, "❌ Parse JSON ไม่สำเร็จ: %v\n", err)
 		}
 	}
 
 	// ถ้าไม่มี JSON ให้ parse แบบ text
-	fmt.Printf("📝 Parse แบบ text...\n")
+	writeLog(ai.log, "📝 Parse แบบ text...\n")
 	decision := &AIDecision{
 		Action:          "HOLD",
 		Confidence:      50.0,
@@ -323,7 +331,7 @@ func (ai *AIClient) parseAIDecision(response string) (*AIDecision, error) {
 		decision.RiskRewardRatio = 3.5 // ให้ผ่านเงื่อนไข
 	}
 
-	fmt.Printf("📊 Parse ผลลัพธ์: %s confidence %.1f%% RR %.1f\n",
+	writeLog(ai.log, "📊 Parse ผลลัพธ์: %s confidence %.1f%% RR %.1f\n",
 		decision.Action, decision.Confidence, decision.RiskRewardRatio)
 
 	return decision, nil
@@ -0,0 +1,76 @@
+package trading
+
+import "fmt"
+
+// RunAIBacktest รัน backtest โดยให้ AI ยืนยันสัญญาณ Pivot SuperTrend ก่อนเข้าเทรด
+func (bt *Backtester) RunAIBacktest() (*BacktestResult, error) {
+	return bt.RunStrategy(NewAIConfirmStrategy())
+}
+
+// AIConfirmStrategy กลยุทธ์ Pivot SuperTrend ที่ถาม AI เฉพาะแท่งที่มีสัญญาณแข็งแกร่ง
+// ใช้คู่กับ SetAIDecisionCache เพื่อให้รันซ้ำได้แบบออฟไลน์
+type AIConfirmStrategy struct {
+	MinConfidence float64 // confidence ขั้นต่ำของ AI (%)
+}
+
+// NewAIConfirmStrategy สร้างกลยุทธ์ AI Confirm
+func NewAIConfirmStrategy() *AIConfirmStrategy {
+	return &AIConfirmStrategy{MinConfidence: 70}
+}
+
+// Name ชื่อกลยุทธ์
+func (s *AIConfirmStrategy) Name() string {
+	return "Pivot SuperTrend + AI Confirm"
+}
+
+// WarmupBars เริ่มจากแท่งที่ 100
+func (s *AIConfirmStrategy) WarmupBars() int {
+	return 100
+}
+
+// Fees คิดค่าคอมมิชชั่นของ backtester ทั้งขาเข้าและขาออกตอนปิด
+func (s *AIConfirmStrategy) Fees(bt *Backtester) FeeModel {
+	return FeeModel{EntryRate: bt.commission, ExitRate: bt.commission}
+}
+
+// OnBar ไม่มี state ต่อแท่ง
+func (s *AIConfirmStrategy) OnBar(bt *Backtester) {}
+
+// ExitSignal ใช้ SL/TP และ trailing stop ของกลยุทธ์หลัก
+func (s *AIConfirmStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	return bt.checkPosition()
+}
+
+// EntrySignal เข้าเมื่อ AI เห็นด้วยกับทิศทางของสัญญาณและ confidence ถึงเกณฑ์
+func (s *AIConfirmStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	analysis := bt.analyzeMarket()
+	if analysis == nil || !bt.isStrongSignal(analysis) {
+		return nil
+	}
+	direction := bt.determineDirection(analysis)
+	if direction == "" {
+		return nil
+	}
+
+	decision, err := bt.getAIDecision(analysis)
+	if err != nil {
+		bt.halt(err)
+		return nil
+	}
+	if decision == nil || decision.Action != direction || decision.Confidence < s.MinConfidence {
+		return nil
+	}
+
+	stopLoss, takeProfit := bt.calculateRiskReward(direction, bt.currentPrice, analysis.ATR)
+	return &EntrySignal{
+		Side:       direction,
+		StopLoss:   stopLoss,
+		TakeProfit: takeProfit,
+		Reason:     fmt.Sprintf("AI %s: Conf=%.1f%%", direction, decision.Confidence),
+	}
+}
+
+// PositionSize ขนาด position ตามความเสี่ยง 2% ของเงินทุน
+func (s *AIConfirmStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return bt.riskBasedQuantity(signal)
+}
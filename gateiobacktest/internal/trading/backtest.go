@@ -2,28 +2,35 @@ package trading
 
 import (
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"sort"
 	"time"
 )
 
 // BacktestResult ผลการ backtest
 type BacktestResult struct {
-	Symbol         string          `json:"symbol"`
-	StartDate      time.Time       `json:"start_date"`
-	EndDate        time.Time       `json:"end_date"`
-	InitialCapital float64         `json:"initial_capital"`
-	FinalCapital   float64         `json:"final_capital"`
-	TotalReturn    float64         `json:"total_return"`
-	TotalReturnPct float64         `json:"total_return_pct"`
-	TotalTrades    int             `json:"total_trades"`
-	WinningTrades  int             `json:"winning_trades"`
-	LosingTrades   int             `json:"losing_trades"`
-	WinRate        float64         `json:"win_rate"`
-	MaxDrawdown    float64         `json:"max_drawdown"`
-	MaxDrawdownPct float64         `json:"max_drawdown_pct"`
-	Trades         []BacktestTrade `json:"trades"`
-	DailyReturns   []DailyReturn   `json:"daily_returns"`
+	Symbol         string             `json:"symbol"`
+	StartDate      time.Time          `json:"start_date"`
+	EndDate        time.Time          `json:"end_date"`
+	InitialCapital float64            `json:"initial_capital"`
+	FinalCapital   float64            `json:"final_capital"`
+	TotalReturn    float64            `json:"total_return"`
+	TotalReturnPct float64            `json:"total_return_pct"`
+	TotalTrades    int                `json:"total_trades"`
+	WinningTrades  int                `json:"winning_trades"`
+	LosingTrades   int                `json:"losing_trades"`
+	WinRate        float64            `json:"win_rate"`
+	MaxDrawdown    float64            `json:"max_drawdown"`
+	MaxDrawdownPct float64            `json:"max_drawdown_pct"`
+	TotalFunding   float64            `json:"total_funding"` // funding สุทธิที่จ่าย (ลบ = ได้รับ)
+	Metrics        PerformanceMetrics `json:"metrics"`
+	Trades         []BacktestTrade    `json:"trades"`
+	DailyReturns   []DailyReturn      `json:"daily_returns"` // equity ปิดวันตามปฏิทิน (UTC)
+	EquityCurve    []EquityPoint      `json:"equity_curve"`  // equity mark-to-market ทุกแท่ง
+	Orders         []Order            `json:"orders,omitempty"`
+	Config         *StrategyConfig    `json:"config"` // config ที่ใช้จริง สำหรับรันซ้ำและเปรียบเทียบผล
 }
 
 // BacktestTrade การเทรดใน backtest
@@ -39,19 +46,30 @@ type BacktestTrade struct {
 	PnL         float64       `json:"pnl"`
 	PnLPct      float64       `json:"pnl_pct"`
 	Commission  float64       `json:"commission"`
+	Funding     float64       `json:"funding"` // funding สุทธิที่จ่ายระหว่างถือ (ลบ = ได้รับ)
 	NetPnL      float64       `json:"net_pnl"`
 	Duration    time.Duration `json:"duration"`
 	EntryReason string        `json:"entry_reason"`
 	ExitReason  string        `json:"exit_reason"`
 	StopLoss    float64       `json:"stop_loss"`
 	TakeProfit  float64       `json:"take_profit"`
+	Legs        []TradeLeg    `json:"legs"`               // ไม้ย่อยเมื่อมีการ pyramid (nil = ไม้เดียว)
+	ExitLeg     int           `json:"exit_leg,omitempty"` // ลำดับการปิดของ position ที่ทยอยปิดหลายครั้ง (0 = ปิดครั้งเดียว)
+	MAEPct      float64       `json:"mae_pct"`            // ราคาวิ่งสวนทางมากที่สุดระหว่างถือ (% จากราคาเข้า)
+	MFEPct      float64       `json:"mfe_pct"`            // ราคาวิ่งตามทางมากที่สุดระหว่างถือ (% จากราคาเข้า)
+
+	// Futures
+	Leverage         float64    `json:"leverage"`
+	MarginMode       MarginMode `json:"margin_mode"`
+	Margin           float64    `json:"margin"`
+	LiquidationPrice float64    `json:"liquidation_price"`
 }
 
 // DailyReturn ผลตอบแทนรายวัน
 type DailyReturn struct {
 	Date   time.Time `json:"date"`
-	Return float64   `json:"return"`
-	Equity float64   `json:"equity"`
+	Return float64   `json:"return"` // ผลตอบแทนของวันนั้น (%) เทียบกับ equity ปิดวันก่อนหน้า (วันแรกเทียบกับเงินทุนเริ่มต้น)
+	Equity float64   `json:"equity"` // equity ปิดวัน
 }
 
 // Position สำหรับ backtest
@@ -64,6 +82,16 @@ type BacktestPosition struct {
 	StopLoss    float64   `json:"stop_loss"`
 	TakeProfit  float64   `json:"take_profit"`
 	EntryReason string    `json:"entry_reason"`
+	Funding     float64   `json:"funding"` // funding สะสมที่จ่ายแล้ว (ลบ = ได้รับ)
+	MAEPct      float64   `json:"mae_pct"`
+	MFEPct      float64   `json:"mfe_pct"`
+	Exits       int       `json:"exits"` // จำนวนครั้งที่ปิดบางส่วนไปแล้ว
+
+	// Futures (ว่างเมื่อไม่ได้เปิดการจำลอง margin)
+	Leverage         float64    `json:"leverage"`
+	MarginMode       MarginMode `json:"margin_mode"`
+	Margin           float64    `json:"margin"`
+	LiquidationPrice float64    `json:"liquidation_price"`
 }
 
 // Position management แบบใหม่
@@ -88,6 +116,23 @@ type Backtester struct {
 	endDate        time.Time
 	initialCapital float64
 	commission     float64 // อัตราค่าคอมมิชชั่น (0.001 = 0.1%)
+	riskPerTrade   float64 // สัดส่วนเงินทุนที่เสี่ยงต่อเทรดของ riskBasedQuantity (0.02 = 2%)
+	fees           FeeModel
+	strategyName   string          // ชื่อกลยุทธ์ที่รันล่าสุด
+	config         *StrategyConfig // config ที่ใช้ผ่าน ApplyConfig (nil = ค่าเริ่มต้น)
+	log            io.Writer       // ปลายทาง log ระหว่างรัน (nil = os.Stdout)
+
+	// การจำลองการ fill
+	intrabarFills   bool            // ตรวจ SL/TP จาก High/Low ของแท่ง
+	sameBarPriority SameBarPriority // กติกาเมื่อแท่งเดียวแตะทั้ง SL และ TP
+
+	// บัญชี futures (nil = ไม่จำลอง margin/liquidation)
+	futures *FuturesAccount
+	funding *FundingModel // nil = ไม่คิด funding
+
+	// entryLimit ปรับขนาด position ก่อนเปิดที่ราคา price (0 = ห้ามเปิด) เช่นข้อจำกัดของ portfolio
+	// ใช้ทั้งสัญญาณเข้าของกลยุทธ์และ order ที่เปิด position ใหม่
+	entryLimit func(bt *Backtester, signal *EntrySignal, quantity, price float64) float64
 
 	// ข้อมูลปัจจุบัน
 	currentTime    time.Time
@@ -98,18 +143,49 @@ type Backtester struct {
 	// ข้อมูลราคา
 	ohlcvData    []OHLCV
 	currentIndex int
+	windowed     bool // เทรดเฉพาะช่วง startDate-endDate (ข้อมูลก่อนหน้าใช้เป็น warmup)
+	barsTotal    int  // จำนวนแท่งที่ประมวลผล
+	barsInMarket int  // จำนวนแท่งที่ถือ position
 
 	// ผลลัพธ์
 	trades       []BacktestTrade
 	dailyReturns []DailyReturn
 	tradeID      int
 
+	// แท่งเทียน timeframe ใหญ่ที่ resample แล้ว (key = ความยาวแท่ง)
+	timeframes map[time.Duration]*timeframeFeed
+
+	// แผนออกแบบทยอยปิด (nil = ปิดทั้งก้อน) และสถานะของ position ปัจจุบัน
+	exitPlan  *ExitPlan
+	exitState *ExitPlanState
+
+	// order book สำหรับ limit/stop/trailing orders
+	orders  []*Order
+	orderID int
+
+	// haltErr ข้อผิดพลาดที่ทำให้ต้องหยุด backtest กลางทาง (เช่น AI cache miss ในโหมด replay)
+	haltErr error
+
+	// equity แบบ mark-to-market รายแท่ง
+	equityCurve    []EquityPoint
+	daily          *dailyEquity
+	peakEquity     float64
+	maxDrawdown    float64
+	maxDrawdownPct float64
+
 	// ตัววิเคราะห์
-	indicators *Indicators
-	aiClient   *AIClient
+	indicators     *Indicators
+	params         StrategyParams
+	incremental    bool                  // analyzeMarket ใช้ตัวชี้วัดแบบ streaming
+	stream         *PivotSuperTrendState // สถานะ streaming ของการรันปัจจุบัน (nil = เริ่มใหม่)
+	streamAnalysis *SuperTrendAnalysis
+	aiClient       *AIClient
+	aiCache        *AIDecisionCache
 
 	// ตัวจัดการ Position ใหม่
 	positionManager *PositionManager
+	pyramiding      bool                  // เปิดการเพิ่มไม้ตาม positionManager
+	legs            []*StructuredPosition // ไม้ย่อยของ position ปัจจุบัน
 }
 
 // NewBacktester สร้าง backtester ใหม่
@@ -133,9 +209,13 @@ func NewBacktester(symbol string, daysBack int, initialCapital float64, deepseek
 		initialCapital:  initialCapital,
 		currentCapital:  initialCapital,
 		commission:      0.001, // 0.1% commission
+		riskPerTrade:    0.02,
+		intrabarFills:   true,
+		sameBarPriority: StopLossFirst,
 		trades:          make([]BacktestTrade, 0),
 		dailyReturns:    make([]DailyReturn, 0),
 		indicators:      NewIndicators(),
+		params:          DefaultStrategyParams(),
 		aiClient:        aiClient,
 		positionManager: positionManager,
 	}, nil
@@ -156,13 +236,39 @@ func NewBacktesterSimple(symbol string, daysBack int, initialCapital float64) (*
 		initialCapital:  initialCapital,
 		currentCapital:  initialCapital,
 		commission:      0.0005, // 0.05% commission for futures
+		riskPerTrade:    0.02,
+		intrabarFills:   true,
+		sameBarPriority: StopLossFirst,
 		trades:          make([]BacktestTrade, 0),
 		dailyReturns:    make([]DailyReturn, 0),
 		indicators:      NewIndicators(),
+		params:          DefaultStrategyParams(),
 		positionManager: positionManager,
 	}, nil
 }
 
+// SetLogOutput กำหนดปลายทาง log ระหว่างรัน (รวม AI client ของ backtester) เช่น io.Discard
+// เมื่อรันหลายครั้งใน optimizer (nil = os.Stdout)
+func (bt *Backtester) SetLogOutput(w io.Writer) {
+	bt.log = w
+	if bt.aiClient != nil {
+		bt.aiClient.log = w
+	}
+}
+
+// logf เขียน log ของการรันไปยังปลายทางที่กำหนดด้วย SetLogOutput
+func (bt *Backtester) logf(format string, args ...any) {
+	writeLog(bt.log, format, args...)
+}
+
+// writeLog เขียน log ไปยัง w (nil = os.Stdout)
+func writeLog(w io.Writer, format string, args ...any) {
+	if w == nil {
+		w = os.Stdout
+	}
+	fmt.Fprintf(w, format, args...)
+}
+
 // สร้าง PositionManager ใหม่
 func NewPositionManager() *PositionManager {
 	return &PositionManager{
@@ -180,105 +286,89 @@ func (bt *Backtester) LoadHistoricalData(ohlcvData []OHLCV) {
 	})
 
 	bt.ohlcvData = ohlcvData
-	fmt.Printf("📊 โหลดข้อมูลราคา %s จำนวน %d แท่งเทียน\n", bt.symbol, len(ohlcvData))
-	fmt.Printf("📅 ช่วงเวลา: %s ถึง %s\n",
+	bt.logf("📊 โหลดข้อมูลราคา %s จำนวน %d แท่งเทียน\n", bt.symbol, len(ohlcvData))
+	bt.logf("📅 ช่วงเวลา: %s ถึง %s\n",
 		time.Unix(ohlcvData[0].Timestamp, 0).Format("2006-01-02 15:04:05"),
 		time.Unix(ohlcvData[len(ohlcvData)-1].Timestamp, 0).Format("2006-01-02 15:04:05"))
 }
 
-// LoadOHLCVData โหลดข้อมูล OHLCV
+// LoadOHLCVData โหลดข้อมูล OHLCV และเทรดเฉพาะแท่งในช่วง startDate ถึง endDate
+// แท่งก่อน startDate ใช้เป็นข้อมูลย้อนหลังสำหรับ indicators (warmup)
 func (bt *Backtester) LoadOHLCVData(data []OHLCV, startDate, endDate time.Time) {
 	bt.ohlcvData = data
 	bt.startDate = startDate
 	bt.endDate = endDate
+	bt.windowed = true
 	bt.currentCapital = bt.initialCapital
 	bt.currentIndex = 0
 }
 
-// RunBacktest รัน backtest
+// RunBacktest รัน backtest ด้วยกลยุทธ์ Pivot Point SuperTrend + EMA100
 func (bt *Backtester) RunBacktest() (*BacktestResult, error) {
-	fmt.Printf("🚀 เริ่มต้น Backtest สำหรับ %s\n", bt.symbol)
-	fmt.Printf("💰 เงินทุนเริ่มต้น: $%.2f\n", bt.initialCapital)
-	fmt.Printf("📅 ระยะเวลา: %d วัน (%s ถึง %s)\n",
-		int(bt.endDate.Sub(bt.startDate).Hours()/24),
-		bt.startDate.Format("2006-01-02"),
-		bt.endDate.Format("2006-01-02"))
+	return bt.RunStrategy(NewPivotSuperTrendStrategy())
+}
 
-	if len(bt.ohlcvData) == 0 {
-		return nil, fmt.Errorf("ไม่มีข้อมูลราคาสำหรับ backtest")
-	}
+// PivotSuperTrendStrategy กลยุทธ์หลัก Pivot Point SuperTrend + EMA100
+type PivotSuperTrendStrategy struct{}
 
-	maxDrawdown := 0.0
-	peak := bt.initialCapital
+// NewPivotSuperTrendStrategy สร้างกลยุทธ์ Pivot Point SuperTrend + EMA100
+func NewPivotSuperTrendStrategy() *PivotSuperTrendStrategy {
+	return &PivotSuperTrendStrategy{}
+}
 
-	// วนลูปผ่านข้อมูลราคาทั้งหมด
-	for bt.currentIndex = 50; bt.currentIndex < len(bt.ohlcvData); bt.currentIndex++ {
-		candle := bt.ohlcvData[bt.currentIndex]
-		bt.currentTime = time.Unix(candle.Timestamp, 0)
-		bt.currentPrice = candle.Close
+// Name ชื่อกลยุทธ์
+func (s *PivotSuperTrendStrategy) Name() string {
+	return "Pivot SuperTrend + EMA100 Backtest"
+}
 
-		// ตรวจสอบ position ที่เปิดอยู่
-		bt.checkPosition()
+// WarmupBars เริ่มจากแท่งที่ 50
+func (s *PivotSuperTrendStrategy) WarmupBars() int {
+	return 50
+}
 
-		// หาโอกาสเทรดใหม่ (ถ้าไม่มี position)
-		if bt.position == nil {
-			bt.lookForEntry()
-		}
+// Fees คิดค่าคอมมิชชั่นของ backtester ทั้งขาเข้าและขาออกตอนปิด
+func (s *PivotSuperTrendStrategy) Fees(bt *Backtester) FeeModel {
+	return FeeModel{EntryRate: bt.commission, ExitRate: bt.commission}
+}
 
-		// คำนวณ drawdown
-		if bt.currentCapital > peak {
-			peak = bt.currentCapital
-		}
-		drawdown := peak - bt.currentCapital
-		if drawdown > maxDrawdown {
-			maxDrawdown = drawdown
-		}
+// OnBar ไม่มี state ต่อแท่ง (วิเคราะห์ตลาดตอนตรวจสัญญาณ)
+func (s *PivotSuperTrendStrategy) OnBar(bt *Backtester) {}
 
-		// บันทึกผลตอบแทนรายวัน (ทุก 24 ชั่วโมง)
-		if bt.currentIndex%24 == 0 {
-			bt.dailyReturns = append(bt.dailyReturns, DailyReturn{
-				Date:   bt.currentTime,
-				Return: (bt.currentCapital - bt.initialCapital) / bt.initialCapital * 100,
-				Equity: bt.currentCapital,
-			})
-		}
-	}
-
-	// ปิด position ถ้ามี (ตอนจบ backtest)
-	if bt.position != nil {
-		bt.closePosition("END_OF_BACKTEST")
-	}
+// ExitSignal ตรวจสอบ SL/TP, trailing stop และ early exit
+func (s *PivotSuperTrendStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	return bt.checkPosition()
+}
 
-	// คำนวณสถิติ
-	result := bt.calculateResults(maxDrawdown)
+// EntrySignal หาสัญญาณเข้าแบบ strong signal และ simple fallback
+func (s *PivotSuperTrendStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	return bt.lookForEntry()
+}
 
-	return result, nil
+// PositionSize ขนาด position ตามความเสี่ยง 2% ของเงินทุน
+func (s *PivotSuperTrendStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return bt.riskBasedQuantity(signal)
 }
 
-// checkPosition ตรวจสอบ position ที่เปิดอยู่ - ปรับปรุงใหม่
-func (bt *Backtester) checkPosition() {
+// checkPosition ตรวจสอบ position ที่เปิดอยู่ - ปรับปรุงใหม่ (คืนเหตุผลการปิด, "" = ถือต่อ)
+func (bt *Backtester) checkPosition() string {
 	if bt.position == nil {
-		return
+		return ""
 	}
 
 	// ตรวจสอบ Stop Loss (แบบ hard stop)
 	if bt.position.Side == "LONG" && bt.currentPrice <= bt.position.StopLoss {
-		bt.closePosition("STOP_LOSS")
-		return
+		return "STOP_LOSS"
 	}
 	if bt.position.Side == "SHORT" && bt.currentPrice >= bt.position.StopLoss {
-		bt.closePosition("STOP_LOSS")
-		return
+		return "STOP_LOSS"
 	}
 
 	// ตรวจสอบ Take Profit (แบบ hard target)
 	if bt.position.Side == "LONG" && bt.currentPrice >= bt.position.TakeProfit {
-		bt.closePosition("TAKE_PROFIT")
-		return
+		return "TAKE_PROFIT"
 	}
 	if bt.position.Side == "SHORT" && bt.currentPrice <= bt.position.TakeProfit {
-		bt.closePosition("TAKE_PROFIT")
-		return
+		return "TAKE_PROFIT"
 	}
 
 	// ตรวจสอบ Trailing Stop (ใหม่)
@@ -286,26 +376,28 @@ func (bt *Backtester) checkPosition() {
 
 	// ตรวจสอบ early exit signals (ทุก 2 ชั่วโมง)
 	if bt.currentIndex%2 == 0 {
-		bt.checkEarlyExit()
+		return bt.checkEarlyExit()
 	}
+
+	return ""
 }
 
 // lookForEntry หาโอกาสเข้า position ใหม่ - กลยุทธ์ใหม่ + debug
-func (bt *Backtester) lookForEntry() {
+func (bt *Backtester) lookForEntry() *EntrySignal {
 	// ต้องมีข้อมูลย้อนหลังเพียงพอ
 	if bt.currentIndex < 100 {
-		return
+		return nil
 	}
 
 	// วิเคราะห์ทางเทคนิค
 	analysis := bt.analyzeMarket()
 	if analysis == nil {
-		return
+		return nil
 	}
 
 	// Debug: แสดงข้อมูลการวิเคราะห์
 	if bt.currentIndex%24 == 0 { // แสดงทุก 24 ชั่วโมง
-		fmt.Printf("🔍 Debug [%s]: Trend=%d, Signal=%s, Confidence=%.1f%%, RR=%.2f\n",
+		bt.logf("🔍 Debug [%s]: Trend=%d, Signal=%s, Confidence=%.1f%%, RR=%.2f\n",
 			bt.currentTime.Format("01-02 15:04"), analysis.Trend, analysis.Signal,
 			analysis.Confidence, analysis.RiskRewardRatio)
 	}
@@ -315,22 +407,39 @@ func (bt *Backtester) lookForEntry() {
 		direction := bt.determineDirection(analysis)
 		if direction != "" {
 			stopLoss, takeProfit := bt.calculateRiskReward(direction, bt.currentPrice, analysis.ATR)
-			fmt.Printf("📊 Strong signal detected: %s at $%.2f\n", direction, bt.currentPrice)
-			bt.openPosition(direction, stopLoss, takeProfit, bt.getSignalReason(analysis))
+			bt.logf("📊 Strong signal detected: %s at $%.2f\n", direction, bt.currentPrice)
+			return &EntrySignal{
+				Side:       direction,
+				StopLoss:   stopLoss,
+				TakeProfit: takeProfit,
+				Reason:     bt.getSignalReason(analysis),
+			}
 		}
 	} else {
 		// เพิ่มการตรวจสอบสัญญาณง่าย ๆ เป็น fallback
 		if bt.currentIndex%6 == 0 { // ทุก 6 ชั่วโมง
-			bt.checkSimpleSignals(analysis)
+			return bt.checkSimpleSignals(analysis)
 		}
 	}
+
+	return nil
 }
 
 // analyzeMarket วิเคราะห์ตลาด
 func (bt *Backtester) analyzeMarket() *SuperTrendAnalysis {
+	if bt.incremental {
+		return bt.incrementalAnalysis()
+	}
+
 	// เตรียมข้อมูล
+	// ใช้ max_lookback แท่ง หรือเท่ากับ EMA period ถ้ายาวกว่า
+	window := bt.params.MaxLookback
+	if bt.params.EMAPeriod > window {
+		window = bt.params.EMAPeriod
+	}
+
 	endIdx := bt.currentIndex + 1
-	startIdx := endIdx - 100
+	startIdx := endIdx - window
 	if startIdx < 0 {
 		startIdx = 0
 	}
@@ -344,38 +453,23 @@ func (bt *Backtester) analyzeMarket() *SuperTrendAnalysis {
 	return analysis
 }
 
-// getAIDecision ขอคำแนะนำจาก AI
-func (bt *Backtester) getAIDecision(analysis *SuperTrendAnalysis) *AIDecision {
-	startIndex := bt.currentIndex - 20
-	if startIndex < 0 {
-		startIndex = 0
-	}
-	decision, err := bt.aiClient.AnalyzeOpenPosition(bt.symbol, analysis, bt.ohlcvData[startIndex:bt.currentIndex+1])
-	if err != nil {
-		fmt.Printf("⚠️ ไม่สามารถขอคำแนะนำจาก AI ได้: %v\n", err)
-		return nil
-	}
-
-	return decision
-}
-
-// openPosition เปิด position ใหม่ - ปรับปรุงการจัดการความเสี่ยง
-func (bt *Backtester) openPosition(side string, stopLoss, takeProfit float64, reason string) {
-	// คำนวณ risk per trade (2% ของเงินทุน)
-	riskAmount := bt.currentCapital * 0.02
+// riskBasedQuantity คำนวณขนาด position ตามความเสี่ยง riskPerTrade ของเงินทุน (0 = ไม่เปิด)
+func (bt *Backtester) riskBasedQuantity(signal *EntrySignal) float64 {
+	// คำนวณ risk per trade (ค่าเริ่มต้น 2% ของเงินทุน)
+	riskAmount := bt.currentCapital * bt.riskPerTrade
 
 	// คำนวณระยะห่าง stop loss
 	var stopDistance float64
-	if side == "LONG" {
-		stopDistance = bt.currentPrice - stopLoss
+	if signal.Side == "LONG" {
+		stopDistance = bt.currentPrice - signal.StopLoss
 	} else {
-		stopDistance = stopLoss - bt.currentPrice
+		stopDistance = signal.StopLoss - bt.currentPrice
 	}
 
 	// ป้องกันการหาร 0
 	if stopDistance <= 0 {
-		fmt.Printf("⚠️ Stop distance ไม่ถูกต้อง: %.2f\n", stopDistance)
-		return
+		bt.logf("⚠️ Stop distance ไม่ถูกต้อง: %.2f\n", stopDistance)
+		return 0
 	}
 
 	// คำนวณขนาด position ตาม risk
@@ -391,68 +485,149 @@ func (bt *Backtester) openPosition(side string, stopLoss, takeProfit float64, re
 	// ตรวจสอบว่าเงินทุนเพียงพอ
 	requiredCapital := quantity * bt.currentPrice
 	if requiredCapital > bt.currentCapital*0.8 {
-		fmt.Printf("⚠️ เงินทุนไม่เพียงพอสำหรับการเทรด\n")
-		return
+		bt.logf("⚠️ เงินทุนไม่เพียงพอสำหรับการเทรด\n")
+		return 0
+	}
+
+	bt.logf("💰 ความเสี่ยง: $%.2f (%.2f%%)\n", riskAmount, 2.0)
+	return quantity
+}
+
+// applyEntryLimit ขนาด position หลังผ่าน entryLimit ที่ราคาเข้า price (0 = ห้ามเปิด)
+func (bt *Backtester) applyEntryLimit(signal *EntrySignal, quantity, price float64) float64 {
+	if bt.entryLimit != nil && quantity > 0 {
+		quantity = bt.entryLimit(bt, signal, quantity, price)
 	}
+	return quantity
+}
+
+// openPosition เปิด position ใหม่ตามสัญญาณและขนาดที่กลยุทธ์คำนวณ ที่ราคาปิดของแท่งปัจจุบัน
+func (bt *Backtester) openPosition(signal *EntrySignal, quantity float64) {
+	bt.openPositionAt(signal, quantity, bt.currentPrice)
+}
 
+// openPositionAt เปิด position ใหม่ที่ราคาที่กำหนด (เช่น ราคา fill ของ order ระหว่างแท่ง)
+func (bt *Backtester) openPositionAt(signal *EntrySignal, quantity, entryPrice float64) {
 	bt.position = &BacktestPosition{
 		Symbol:      bt.symbol,
-		Side:        side,
+		Side:        signal.Side,
 		EntryTime:   bt.currentTime,
-		EntryPrice:  bt.currentPrice,
+		EntryPrice:  entryPrice,
 		Quantity:    quantity,
-		StopLoss:    stopLoss,
-		TakeProfit:  takeProfit,
-		EntryReason: reason,
+		StopLoss:    signal.StopLoss,
+		TakeProfit:  signal.TakeProfit,
+		EntryReason: signal.Reason,
 	}
+	bt.applyMargin(bt.position, signal.Leverage)
+	quantity = bt.position.Quantity
+	bt.startLegs()
+	bt.startExitPlan()
 
-	fmt.Printf("📈 เปิด %s: ราคา $%.2f, ปริมาณ %.6f, SL: $%.2f, TP: $%.2f\n",
-		side, bt.currentPrice, quantity, stopLoss, takeProfit)
-	fmt.Printf("🎯 เหตุผล: %s\n", reason)
-	fmt.Printf("💰 ความเสี่ยง: $%.2f (%.2f%%)\n", riskAmount, 2.0)
+	// หักค่าธรรมเนียมขาเข้าทันที (ถ้าโมเดลค่าธรรมเนียมกำหนด)
+	if bt.fees.ChargeEntryOnOpen {
+		bt.currentCapital -= entryPrice * quantity * bt.fees.EntryRate
+	}
+
+	bt.logf("📈 เปิด %s: ราคา $%.2f, ปริมาณ %.6f, SL: $%.2f, TP: $%.2f\n",
+		signal.Side, entryPrice, quantity, signal.StopLoss, signal.TakeProfit)
+	bt.logf("🎯 เหตุผล: %s\n", signal.Reason)
 }
 
-// closePosition ปิด position
+// closePosition ปิด position ที่ราคาปิดของแท่งปัจจุบัน
 func (bt *Backtester) closePosition(reason string) {
+	bt.closePositionAt(bt.currentPrice, reason)
+}
+
+// closePositionAt ปิด position ทั้งหมดที่ราคาที่กำหนด
+func (bt *Backtester) closePositionAt(exitPrice float64, reason string) {
 	if bt.position == nil {
 		return
 	}
+	bt.closeQuantityAt(exitPrice, bt.position.Quantity, reason)
+}
+
+// closeQuantityAt ปิด position บางส่วน (หรือทั้งหมดถ้า quantity ไม่น้อยกว่าขนาด position) ที่ราคาที่กำหนด
+// แต่ละครั้งที่ปิดบันทึกเป็นหนึ่งเทรด โดยแบ่ง funding และ margin ตามสัดส่วนที่ปิด
+func (bt *Backtester) closeQuantityAt(exitPrice, quantity float64, reason string) {
+	pos := bt.position
+	if pos == nil || quantity <= 0 {
+		return
+	}
+
+	full := quantity >= pos.Quantity*(1-1e-9)
+	if full {
+		quantity = pos.Quantity
+	}
+	fraction := quantity / pos.Quantity
 
 	// คำนวณ PnL
 	var pnl float64
-	if bt.position.Side == "LONG" {
-		pnl = (bt.currentPrice - bt.position.EntryPrice) * bt.position.Quantity
+	if pos.Side == "LONG" {
+		pnl = (exitPrice - pos.EntryPrice) * quantity
 	} else {
-		pnl = (bt.position.EntryPrice - bt.currentPrice) * bt.position.Quantity
+		pnl = (pos.EntryPrice - exitPrice) * quantity
 	}
 
-	// คำนวณค่าคอมมิชชั่น
-	commission := (bt.position.EntryPrice + bt.currentPrice) * bt.position.Quantity * bt.commission
-	netPnL := pnl - commission
+	// ราคาที่ปิดได้ก็นับเป็นจุดที่ราคาผ่าน
+	bt.trackExcursion(exitPrice, exitPrice)
+
+	// คำนวณค่าคอมมิชชั่น (ขาเข้า + ขาออก)
+	entryCommission := pos.EntryPrice * quantity * bt.fees.EntryRate
+	exitCommission := exitPrice * quantity * bt.fees.ExitRate
+	commission := entryCommission + exitCommission
+
+	// ถูก liquidate: maintenance margin ที่เหลือถูกยึดเป็นค่า liquidation
+	if reason == LiquidationExit && bt.futures != nil {
+		commission += exitPrice * quantity * bt.futures.MaintenanceMarginRate
+	}
+	funding := pos.Funding * fraction
+	netPnL := pnl - commission - funding
 
-	// อัปเดตเงินทุน
-	bt.currentCapital += netPnL
+	// อัปเดตเงินทุน (funding ถูกหักไปแล้วตอนเก็บแต่ละรอบ และขาเข้าถูกหักไปแล้วตอนเปิดถ้า ChargeEntryOnOpen)
+	charged := commission
+	if bt.fees.ChargeEntryOnOpen {
+		charged -= entryCommission
+	}
+	bt.currentCapital += pnl - charged
+
+	var legs []TradeLeg
+	if full {
+		legs = bt.closeLegs(exitPrice)
+	}
 
 	// บันทึกการเทรด
 	bt.tradeID++
 	trade := BacktestTrade{
 		ID:          bt.tradeID,
-		Symbol:      bt.position.Symbol,
-		Side:        bt.position.Side,
-		EntryTime:   bt.position.EntryTime,
+		Symbol:      pos.Symbol,
+		Side:        pos.Side,
+		EntryTime:   pos.EntryTime,
 		ExitTime:    bt.currentTime,
-		EntryPrice:  bt.position.EntryPrice,
-		ExitPrice:   bt.currentPrice,
-		Quantity:    bt.position.Quantity,
+		EntryPrice:  pos.EntryPrice,
+		ExitPrice:   exitPrice,
+		Quantity:    quantity,
 		PnL:         pnl,
-		PnLPct:      pnl / (bt.position.EntryPrice * bt.position.Quantity) * 100,
+		PnLPct:      pnl / (pos.EntryPrice * quantity) * 100,
 		Commission:  commission,
+		Funding:     funding,
 		NetPnL:      netPnL,
-		Duration:    bt.currentTime.Sub(bt.position.EntryTime),
-		EntryReason: bt.position.EntryReason,
+		Duration:    bt.currentTime.Sub(pos.EntryTime),
+		EntryReason: pos.EntryReason,
 		ExitReason:  reason,
-		StopLoss:    bt.position.StopLoss,
-		TakeProfit:  bt.position.TakeProfit,
+		StopLoss:    pos.StopLoss,
+		TakeProfit:  pos.TakeProfit,
+		Legs:        legs,
+		MAEPct:      pos.MAEPct,
+		MFEPct:      pos.MFEPct,
+
+		Leverage:         pos.Leverage,
+		MarginMode:       pos.MarginMode,
+		Margin:           pos.Margin * fraction,
+		LiquidationPrice: pos.LiquidationPrice,
+	}
+	if !full || pos.Exits > 0 {
+		pos.Exits++
+		trade.ExitLeg = pos.Exits
 	}
 
 	bt.trades = append(bt.trades, trade)
@@ -462,13 +637,36 @@ func (bt *Backtester) closePosition(reason string) {
 		status = "📈"
 	}
 
-	fmt.Printf("%s ปิด %s: ราคา $%.2f, PnL: $%.2f (%.2f%%), เวลา: %v\n",
-		status, bt.position.Side, bt.currentPrice, netPnL, trade.PnLPct, trade.Duration)
-	fmt.Printf("🎯 เหตุผล: %s\n", reason)
-	fmt.Printf("💰 เงินทุนปัจจุบัน: $%.2f\n", bt.currentCapital)
+	if full {
+		bt.logf("%s ปิด %s: ราคา $%.2f, PnL: $%.2f (%.2f%%), เวลา: %v\n",
+			status, pos.Side, exitPrice, netPnL, trade.PnLPct, trade.Duration)
+	} else {
+		bt.logf("%s ปิดบางส่วน %s %.6f/%.6f: ราคา $%.2f, PnL: $%.2f (%.2f%%), เวลา: %v\n",
+			status, pos.Side, quantity, pos.Quantity, exitPrice, netPnL, trade.PnLPct, trade.Duration)
+	}
+	if trade.Funding != 0 {
+		bt.logf("💸 Funding: $%.2f\n", trade.Funding)
+	}
+	bt.logf("🎯 เหตุผล: %s\n", reason)
+	bt.logf("💰 เงินทุนปัจจุบัน: $%.2f\n", bt.currentCapital)
+
+	if full {
+		// ล้าง position
+		bt.position = nil
+		bt.exitState = nil
+		return
+	}
 
-	// ล้าง position
-	bt.position = nil
+	// ลดขนาด position ที่เหลือ (รวมถึงไม้ย่อยตามสัดส่วนเดียวกัน)
+	pos.Quantity -= quantity
+	pos.Funding -= funding
+	pos.Margin -= pos.Margin * fraction
+	for _, leg := range bt.legs {
+		leg.Quantity *= 1 - fraction
+	}
+	if bt.futures != nil && pos.Leverage > 0 {
+		pos.LiquidationPrice = bt.liquidationPrice(pos)
+	}
 }
 
 // checkExitSignal ตรวจสอบสัญญาณออกจาก AI
@@ -487,15 +685,27 @@ func (bt *Backtester) checkExitSignal() {
 	}
 }
 
+// exposurePct สัดส่วนแท่งที่ถือ position (%)
+func (bt *Backtester) exposurePct() float64 {
+	if bt.barsTotal == 0 {
+		return 0
+	}
+	return float64(bt.barsInMarket) / float64(bt.barsTotal) * 100
+}
+
 // calculateResults คำนวณผลลัพธ์สุดท้าย
-func (bt *Backtester) calculateResults(maxDrawdown float64) *BacktestResult {
+func (bt *Backtester) calculateResults() *BacktestResult {
 	totalReturn := bt.currentCapital - bt.initialCapital
 	totalReturnPct := totalReturn / bt.initialCapital * 100
 
 	winningTrades := 0
 	losingTrades := 0
+	totalFunding := 0.0
 
-	for _, trade := range bt.trades {
+	// สถิติที่นับจำนวนเทรดนับต่อ position (ขาที่ทยอยปิดของ position เดียวกันนับเป็นเทรดเดียว)
+	positions := PositionTrades(bt.trades)
+	for _, trade := range positions {
+		totalFunding += trade.Funding
 		if trade.NetPnL > 0 {
 			winningTrades++
 		} else {
@@ -504,26 +714,37 @@ func (bt *Backtester) calculateResults(maxDrawdown float64) *BacktestResult {
 	}
 
 	winRate := 0.0
-	if len(bt.trades) > 0 {
-		winRate = float64(winningTrades) / float64(len(bt.trades)) * 100
+	if len(positions) > 0 {
+		winRate = float64(winningTrades) / float64(len(positions)) * 100
+	}
+
+	// ช่วงเวลาจากแท่งแรกถึงแท่งสุดท้ายที่เทรดจริง (startDate/endDate ของ NewBacktesterSimple มาจาก time.Now)
+	startDate, endDate := bt.startDate, bt.endDate
+	if len(bt.equityCurve) > 0 {
+		startDate, endDate = bt.equityCurve[0].Time, bt.equityCurve[len(bt.equityCurve)-1].Time
 	}
 
 	return &BacktestResult{
 		Symbol:         bt.symbol,
-		StartDate:      bt.startDate,
-		EndDate:        bt.endDate,
+		StartDate:      startDate,
+		EndDate:        endDate,
 		InitialCapital: bt.initialCapital,
 		FinalCapital:   bt.currentCapital,
 		TotalReturn:    totalReturn,
 		TotalReturnPct: totalReturnPct,
-		TotalTrades:    len(bt.trades),
+		TotalTrades:    len(positions),
 		WinningTrades:  winningTrades,
 		LosingTrades:   losingTrades,
 		WinRate:        winRate,
-		MaxDrawdown:    maxDrawdown,
-		MaxDrawdownPct: maxDrawdown / bt.initialCapital * 100,
+		MaxDrawdown:    bt.maxDrawdown,
+		MaxDrawdownPct: bt.maxDrawdownPct,
+		TotalFunding:   totalFunding,
+		Metrics:        CalculateMetrics(positions, bt.dailyReturns, bt.maxDrawdownPct, bt.exposurePct()),
 		Trades:         bt.trades,
 		DailyReturns:   bt.dailyReturns,
+		EquityCurve:    bt.equityCurve,
+		Orders:         bt.orderHistory(),
+		Config:         bt.EffectiveConfig(),
 	}
 }
 
@@ -531,19 +752,19 @@ func (bt *Backtester) calculateResults(maxDrawdown float64) *BacktestResult {
 func (bt *Backtester) isStrongSignal(analysis *SuperTrendAnalysis) bool {
 	// ตรวจสอบ momentum และ trend alignment (ลดความเข้มงวด)
 	trendAligned := analysis.Trend != 0 && analysis.Signal != "NEUTRAL"
-	confidenceOK := analysis.Confidence >= 70.0 // ลดจาก 80 เป็น 70
+	confidenceOK := analysis.Confidence >= bt.params.MinConfidence
 
 	// ตรวจสอบการยืนยันจากหลายตัวชี้วัด (ผ่อนคลาย)
 	priceAligned := (analysis.Trend == 1 && analysis.CurrentPrice > analysis.SuperTrendValue) ||
 		(analysis.Trend == -1 && analysis.CurrentPrice < analysis.SuperTrendValue)
 
 	// ตรวจสอบ risk-reward ratio (ลดเงื่อนไข)
-	goodRiskReward := analysis.RiskRewardRatio >= 1.5 // ลดจาก 2.0 เป็น 1.5
+	goodRiskReward := analysis.RiskRewardRatio >= bt.params.MinRiskReward
 
 	// ตรวจสอบ volume confirmation (ผ่อนคลาย)
 	volumeConfirm := bt.hasVolumeConfirmation()
 
-	// ต้องผ่านอย่างน้อย 4 จาก 5 เงื่อนไข
+	// ต้องผ่านอย่างน้อย min_conditions จาก 5 เงื่อนไข
 	conditions := []bool{trendAligned, confidenceOK, priceAligned, goodRiskReward, volumeConfirm}
 	passedCount := 0
 	for _, condition := range conditions {
@@ -552,7 +773,7 @@ func (bt *Backtester) isStrongSignal(analysis *SuperTrendAnalysis) bool {
 		}
 	}
 
-	return passedCount >= 4
+	return passedCount >= bt.params.MinConditions
 }
 
 // determineDirection กำหนดทิศทางการเทรด
@@ -577,9 +798,9 @@ func (bt *Backtester) determineDirection(analysis *SuperTrendAnalysis) string {
 
 // calculateRiskReward คำนวณ Stop Loss และ Take Profit - ปรับให้เหมาะสม
 func (bt *Backtester) calculateRiskReward(direction string, currentPrice, atr float64) (stopLoss, takeProfit float64) {
-	// ใช้ ATR สำหรับการคำนวณ SL/TP (ปรับให้เหมาะสมกับ 1H timeframe)
-	atrMultiplier := 1.5   // ลดจาก 2.0 เป็น 1.5
-	riskRewardRatio := 2.5 // ลดจาก 3.0 เป็น 2.5
+	// ใช้ ATR สำหรับการคำนวณ SL/TP (ค่าจาก StrategyParams ปรับได้ด้วย optimizer)
+	atrMultiplier := bt.params.ATRMultiplier
+	riskRewardRatio := bt.params.RiskReward
 
 	if direction == "LONG" {
 		stopLoss = currentPrice - (atr * atrMultiplier)
@@ -676,35 +897,34 @@ func (bt *Backtester) updateTrailingStop() {
 	}
 }
 
-// checkEarlyExit ตรวจสอบสัญญาณออกก่อนกำหนด
-func (bt *Backtester) checkEarlyExit() {
+// checkEarlyExit ตรวจสอบสัญญาณออกก่อนกำหนด (คืนเหตุผลการปิด, "" = ถือต่อ)
+func (bt *Backtester) checkEarlyExit() string {
 	if bt.position == nil {
-		return
+		return ""
 	}
 
 	analysis := bt.analyzeMarket()
 	if analysis == nil {
-		return
+		return ""
 	}
 
 	// ตรวจสอบ momentum reversal
 	if bt.isMomentumReversal(analysis) {
-		bt.closePosition("MOMENTUM_REVERSAL")
-		return
+		return "MOMENTUM_REVERSAL"
 	}
 
 	// ตรวจสอบ overbought/oversold extreme
 	if bt.isExtremeCondition(analysis) {
-		bt.closePosition("EXTREME_CONDITION")
-		return
+		return "EXTREME_CONDITION"
 	}
 
 	// ตรวจสอบ time-based exit (ถือครองเกิน 8 ชั่วโมง)
 	holdingTime := bt.currentTime.Sub(bt.position.EntryTime)
 	if holdingTime > 8*time.Hour {
-		bt.closePosition("TIME_EXIT")
-		return
+		return "TIME_EXIT"
 	}
+
+	return ""
 }
 
 // isMomentumReversal ตรวจสอบการกลับทิศของ momentum
@@ -747,70 +967,77 @@ func (bt *Backtester) isExtremeCondition(analysis *SuperTrendAnalysis) bool {
 }
 
 // checkSimpleSignals ตรวจสอบสัญญาณง่าย ๆ เป็น fallback
-func (bt *Backtester) checkSimpleSignals(analysis *SuperTrendAnalysis) {
+func (bt *Backtester) checkSimpleSignals(analysis *SuperTrendAnalysis) *EntrySignal {
 	// สัญญาณง่าย: SuperTrend + EMA crossover
 	if analysis.Trend == 1 && analysis.CurrentPrice > analysis.SuperTrendValue &&
 		analysis.CurrentPrice > analysis.EMA100 {
 		stopLoss := analysis.SuperTrendValue
 		takeProfit := bt.currentPrice + (bt.currentPrice-stopLoss)*2.0
-		fmt.Printf("📈 Simple LONG signal: Price %.2f > SuperTrend %.2f > EMA100 %.2f\n",
+		bt.logf("📈 Simple LONG signal: Price %.2f > SuperTrend %.2f > EMA100 %.2f\n",
 			analysis.CurrentPrice, analysis.SuperTrendValue, analysis.EMA100)
-		bt.openPosition("LONG", stopLoss, takeProfit, "Simple SuperTrend + EMA100 LONG")
+		return &EntrySignal{Side: "LONG", StopLoss: stopLoss, TakeProfit: takeProfit, Reason: "Simple SuperTrend + EMA100 LONG"}
 	} else if analysis.Trend == -1 && analysis.CurrentPrice < analysis.SuperTrendValue &&
 		analysis.CurrentPrice < analysis.EMA100 {
 		stopLoss := analysis.SuperTrendValue
 		takeProfit := bt.currentPrice - (stopLoss-bt.currentPrice)*2.0
-		fmt.Printf("📉 Simple SHORT signal: Price %.2f < SuperTrend %.2f < EMA100 %.2f\n",
+		bt.logf("📉 Simple SHORT signal: Price %.2f < SuperTrend %.2f < EMA100 %.2f\n",
 			analysis.CurrentPrice, analysis.SuperTrendValue, analysis.EMA100)
-		bt.openPosition("SHORT", stopLoss, takeProfit, "Simple SuperTrend + EMA100 SHORT")
+		return &EntrySignal{Side: "SHORT", StopLoss: stopLoss, TakeProfit: takeProfit, Reason: "Simple SuperTrend + EMA100 SHORT"}
 	}
+
+	return nil
 }
 
 // RunTripleEMA1HStrategy - กลยุทธ์ Triple EMA สำหรับ 1H timeframe (144 candles)
 func (bt *Backtester) RunTripleEMA1HStrategy() *BacktestResult {
-	fmt.Printf("\n🚀 เริ่มต้น Triple EMA 1H Strategy %s\n", bt.symbol)
-	fmt.Printf("💰 เงินทุนเริ่มต้น: $%.2f\n", bt.initialCapital)
-	fmt.Printf("⏰ Time Frame: 1 ชั่วโมง (144 candles)\n")
-	fmt.Printf("🎯 กลยุทธ์: Triple EMA(9,21,50) + RSI + Volume + ATR\n")
-	fmt.Printf("📊 รองรับ: LONG & SHORT Positions (5x Leverage)\n")
-
 	// ตรวจสอบข้อมูลครบ 144 candles
 	if len(bt.ohlcvData) < 144 {
-		fmt.Printf("❌ ข้อมูลไม่ครบ 144 candles (มี %d candles)\n", len(bt.ohlcvData))
-		return bt.generateTripleEMA1HResults()
+		bt.logf("❌ ข้อมูลไม่ครบ 144 candles (มี %d candles)\n", len(bt.ohlcvData))
+		return bt.calculateResults()
 	}
 
-	// เริ่มจากแท่งที่ 50 เพื่อให้ indicators พร้อม
-	for bt.currentIndex = 50; bt.currentIndex < len(bt.ohlcvData); bt.currentIndex++ {
-		currentCandle := bt.ohlcvData[bt.currentIndex]
-		bt.currentTime = time.Unix(currentCandle.Timestamp, 0)
-		bt.currentPrice = currentCandle.Close
+	return bt.runStrategyResult(NewTripleEMA1HStrategy())
+}
 
-		// วิเคราะห์ตลาดด้วย Triple EMA 1H
-		analysis := bt.analyzeTripleEMA1H()
-		if analysis == nil {
-			continue
-		}
+// TripleEMA1HStrategy กลยุทธ์ Triple EMA(9,21,50) + RSI + Volume + ATR สำหรับ 1H (5x Leverage)
+type TripleEMA1HStrategy struct {
+	analysis *SuperTrendAnalysis // ผลวิเคราะห์ของแท่งปัจจุบัน
+}
 
-		// จัดการ position ที่มีอยู่
-		bt.checkTripleEMA1HPosition()
+// NewTripleEMA1HStrategy สร้างกลยุทธ์ Triple EMA 1H
+func NewTripleEMA1HStrategy() *TripleEMA1HStrategy {
+	return &TripleEMA1HStrategy{}
+}
 
-		// หาจุดเข้าใหม่
-		bt.lookForTripleEMA1HEntry(analysis)
+// Name ชื่อกลยุทธ์
+func (s *TripleEMA1HStrategy) Name() string {
+	return "Triple EMA 1H Strategy"
+}
 
-		// Debug: แสดงข้อมูลทุก 24 candles (1 วัน)
-		if bt.currentIndex%24 == 0 {
-			fmt.Printf("🔍 Debug [%d]: Price=%.2f, Signal=%s, Conf=%.1f%%, 1H\n",
-				bt.currentIndex, bt.currentPrice, analysis.Signal, analysis.Confidence)
-		}
-	}
+// EndOfDataReason เหตุผลปิดตอนข้อมูลหมดตาม runner เดิม
+func (s *TripleEMA1HStrategy) EndOfDataReason() string {
+	return "End of Backtest"
+}
 
-	// ปิด position สุดท้าย
-	if bt.position != nil {
-		bt.closeTripleEMA1HPosition("End of Backtest")
-	}
+// WarmupBars เริ่มจากแท่งที่ 50 เพื่อให้ indicators พร้อม
+func (s *TripleEMA1HStrategy) WarmupBars() int {
+	return 50
+}
 
-	return bt.generateTripleEMA1HResults()
+// Fees ค่าธรรมเนียม Future 0.05% ต่อขา หักขาเข้าตั้งแต่ตอนเปิด
+func (s *TripleEMA1HStrategy) Fees(bt *Backtester) FeeModel {
+	return FeeModel{EntryRate: 0.0005, ExitRate: 0.0005, ChargeEntryOnOpen: true}
+}
+
+// OnBar วิเคราะห์ตลาดด้วย Triple EMA 1H
+func (s *TripleEMA1HStrategy) OnBar(bt *Backtester) {
+	s.analysis = bt.analyzeTripleEMA1H()
+
+	// Debug: แสดงข้อมูลทุก 24 candles (1 วัน)
+	if s.analysis != nil && bt.currentIndex%24 == 0 {
+		bt.logf("🔍 Debug [%d]: Price=%.2f, Signal=%s, Conf=%.1f%%, 1H\n",
+			bt.currentIndex, bt.currentPrice, s.analysis.Signal, s.analysis.Confidence)
+	}
 }
 
 // analyzeTripleEMA1H วิเคราะห์ตลาดด้วย Triple EMA สำหรับ 1H
@@ -910,270 +1137,70 @@ func (bt *Backtester) analyzeTripleEMA1H() *SuperTrendAnalysis {
 	}
 }
 
-// lookForTripleEMA1HEntry หาจุดเข้าด้วย Triple EMA 1H
-func (bt *Backtester) lookForTripleEMA1HEntry(analysis *SuperTrendAnalysis) {
-	if bt.position != nil {
-		return
+// EntrySignal หาจุดเข้าด้วย Triple EMA 1H (ใช้ confidence threshold 75%)
+func (s *TripleEMA1HStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	analysis := s.analysis
+	if analysis == nil || analysis.Confidence < 75.0 {
+		return nil
 	}
-
-	// เข้า LONG position (ใช้ confidence threshold 75%)
-	if analysis.Signal == "LONG" && analysis.Confidence >= 75.0 {
-		bt.openTripleEMA1HPosition("LONG", analysis)
+	if analysis.Signal != "LONG" && analysis.Signal != "SHORT" {
+		return nil
 	}
 
-	// เข้า SHORT position
-	if analysis.Signal == "SHORT" && analysis.Confidence >= 75.0 {
-		bt.openTripleEMA1HPosition("SHORT", analysis)
-	}
-}
-
-// openTripleEMA1HPosition เปิด position ด้วย Triple EMA 1H
-func (bt *Backtester) openTripleEMA1HPosition(side string, analysis *SuperTrendAnalysis) {
-	// Future Trading Parameters
-	leverage := 5.0
-
-	var stopLoss, takeProfit float64
-
 	// คำนวณ Stop Loss และ Take Profit โดยใช้ ATR
 	atrMultiplier := 2.0 // ใช้ 2.0x ATR สำหรับ 1H timeframe
+	stopLoss, takeProfit := atrStops(analysis.Signal, bt.currentPrice, analysis.ATR, atrMultiplier, analysis.RiskRewardRatio)
 
-	if side == "LONG" {
-		stopLoss = bt.currentPrice - (analysis.ATR * atrMultiplier)
-		takeProfit = bt.currentPrice + (analysis.ATR * atrMultiplier * analysis.RiskRewardRatio)
-	} else {
-		stopLoss = bt.currentPrice + (analysis.ATR * atrMultiplier)
-		takeProfit = bt.currentPrice - (analysis.ATR * atrMultiplier * analysis.RiskRewardRatio)
+	return &EntrySignal{
+		Side:       analysis.Signal,
+		StopLoss:   stopLoss,
+		TakeProfit: takeProfit,
+		Leverage:   5.0,
+		Reason:     fmt.Sprintf("1H %s: Triple EMA, Conf=%.1f%%, Lev=%.1fx", analysis.Signal, analysis.Confidence, 5.0),
 	}
-
-	// Risk per trade 1.0%
-	riskAmount := bt.currentCapital * 0.01
-
-	var positionSize float64
-	if side == "LONG" {
-		positionSize = (riskAmount * leverage) / (bt.currentPrice - stopLoss)
-	} else {
-		positionSize = (riskAmount * leverage) / (stopLoss - bt.currentPrice)
-	}
-
-	// ป้องกันใช้เงินเกิน 15%
-	maxValue := bt.currentCapital * 0.15 * leverage
-	if positionSize*bt.currentPrice > maxValue {
-		positionSize = maxValue / bt.currentPrice
-	}
-
-	// คำนวณค่าธรรมเนียม Future
-	entryCommission := bt.currentPrice * positionSize * 0.0005
-
-	// สร้าง position
-	bt.position = &BacktestPosition{
-		Symbol:      bt.symbol,
-		Side:        side,
-		EntryTime:   bt.currentTime,
-		EntryPrice:  bt.currentPrice,
-		Quantity:    positionSize,
-		StopLoss:    stopLoss,
-		TakeProfit:  takeProfit,
-		EntryReason: fmt.Sprintf("1H %s: Triple EMA, Conf=%.1f%%, Lev=%.1fx", side, analysis.Confidence, leverage),
-	}
-
-	// หักค่าธรรมเนียม
-	bt.currentCapital -= entryCommission
-
-	// บันทึก trade
-	bt.recordTripleEMA1HTrade("OPEN", fmt.Sprintf("1H Entry: %s (Triple EMA)", side))
-
-	var slPct, tpPct float64
-	if side == "LONG" {
-		slPct = ((stopLoss - bt.currentPrice) / bt.currentPrice) * 100
-		tpPct = ((takeProfit - bt.currentPrice) / bt.currentPrice) * 100
-	} else {
-		slPct = ((bt.currentPrice - stopLoss) / bt.currentPrice) * 100
-		tpPct = ((bt.currentPrice - takeProfit) / bt.currentPrice) * 100
-	}
-
-	fmt.Printf("🎯 เปิด 1H Triple EMA %s: @ $%.2f, SL: $%.2f (%.2f%%), TP: $%.2f (%.2f%%), Size: %.6f, Fee: $%.2f\n",
-		side, bt.currentPrice, stopLoss, slPct, takeProfit, tpPct, positionSize, entryCommission)
 }
 
-// checkTripleEMA1HPosition ตรวจสอบ position สำหรับ 1H
-func (bt *Backtester) checkTripleEMA1HPosition() {
-	if bt.position == nil {
-		return
-	}
+// PositionSize Risk per trade 1.0% x 5x leverage และใช้เงินไม่เกิน 15%
+func (s *TripleEMA1HStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return bt.leveragedQuantity(signal, 0.01, 5.0, 0.15)
+}
 
-	shouldExit := false
+// ExitSignal ตรวจสอบ position สำหรับ 1H
+func (s *TripleEMA1HStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
 	exitReason := ""
 
-	if bt.position.Side == "LONG" {
+	if pos.Side == "LONG" {
 		// LONG Position checks
-		if bt.currentPrice <= bt.position.StopLoss {
-			shouldExit = true
+		if bt.currentPrice <= pos.StopLoss {
 			exitReason = "Stop Loss Hit"
 		}
-		if bt.currentPrice >= bt.position.TakeProfit {
-			shouldExit = true
+		if bt.currentPrice >= pos.TakeProfit {
 			exitReason = "Take Profit Hit"
 		}
 	} else {
 		// SHORT Position checks
-		if bt.currentPrice >= bt.position.StopLoss {
-			shouldExit = true
+		if bt.currentPrice >= pos.StopLoss {
 			exitReason = "Stop Loss Hit"
 		}
-		if bt.currentPrice <= bt.position.TakeProfit {
-			shouldExit = true
+		if bt.currentPrice <= pos.TakeProfit {
 			exitReason = "Take Profit Hit"
 		}
 	}
 
 	// Signal reversal exit
-	analysis := bt.analyzeTripleEMA1H()
-	if analysis != nil {
-		if bt.position.Side == "LONG" && analysis.Signal == "SHORT" && analysis.Confidence >= 80.0 {
-			shouldExit = true
+	if analysis := s.analysis; analysis != nil {
+		if pos.Side == "LONG" && analysis.Signal == "SHORT" && analysis.Confidence >= 80.0 {
 			exitReason = "Signal Reversal"
 		}
-		if bt.position.Side == "SHORT" && analysis.Signal == "LONG" && analysis.Confidence >= 80.0 {
-			shouldExit = true
+		if pos.Side == "SHORT" && analysis.Signal == "LONG" && analysis.Confidence >= 80.0 {
 			exitReason = "Signal Reversal"
 		}
 	}
 
 	// Time exit - 24 hours (1H × 24 = 1 วัน)
-	if bt.currentTime.Sub(bt.position.EntryTime).Hours() > 24 {
-		shouldExit = true
+	if bt.currentTime.Sub(pos.EntryTime).Hours() > 24 {
 		exitReason = "Time Exit (24h)"
 	}
 
-	if shouldExit {
-		bt.closeTripleEMA1HPosition(exitReason)
-	}
-}
-
-// closeTripleEMA1HPosition ปิด position สำหรับ 1H
-func (bt *Backtester) closeTripleEMA1HPosition(reason string) {
-	if bt.position == nil {
-		return
-	}
-
-	var pnl float64
-
-	if bt.position.Side == "LONG" {
-		pnl = (bt.currentPrice - bt.position.EntryPrice) * bt.position.Quantity
-	} else {
-		pnl = (bt.position.EntryPrice - bt.currentPrice) * bt.position.Quantity
-	}
-
-	// คำนวณค่าธรรมเนียม
-	entryCommission := bt.position.EntryPrice * bt.position.Quantity * 0.0005
-	exitCommission := bt.currentPrice * bt.position.Quantity * 0.0005
-	totalCommission := entryCommission + exitCommission
-
-	netPnL := pnl - totalCommission
-
-	// อัปเดตเงินทุน
-	bt.currentCapital += netPnL
-
-	// บันทึก trade
-	bt.recordTripleEMA1HTrade("CLOSE", reason)
-
-	// แสดงผล
-	pnlPct := (netPnL / (bt.position.EntryPrice * bt.position.Quantity)) * 100
-	fmt.Printf("🔹 ปิด 1H Triple EMA %s: PnL $%.2f (%.2f%%) - Commission: $%.2f - %s\n",
-		bt.position.Side, netPnL, pnlPct, totalCommission, reason)
-
-	// ลบ position
-	bt.position = nil
-}
-
-// recordTripleEMA1HTrade บันทึก trade สำหรับ 1H
-func (bt *Backtester) recordTripleEMA1HTrade(action, reason string) {
-	bt.tradeID++
-
-	var exitTime time.Time
-	var exitPrice, pnl, netPnL, totalCommission float64
-
-	if action == "CLOSE" && bt.position != nil {
-		exitTime = bt.currentTime
-		exitPrice = bt.currentPrice
-
-		if bt.position.Side == "LONG" {
-			pnl = (bt.currentPrice - bt.position.EntryPrice) * bt.position.Quantity
-		} else {
-			pnl = (bt.position.EntryPrice - bt.currentPrice) * bt.position.Quantity
-		}
-
-		entryCommission := bt.position.EntryPrice * bt.position.Quantity * 0.0005
-		exitCommission := bt.currentPrice * bt.position.Quantity * 0.0005
-		totalCommission = entryCommission + exitCommission
-
-		netPnL = pnl - totalCommission
-	}
-
-	trade := BacktestTrade{
-		ID:          bt.tradeID,
-		Symbol:      bt.symbol,
-		Side:        bt.position.Side,
-		EntryTime:   bt.position.EntryTime,
-		ExitTime:    exitTime,
-		EntryPrice:  bt.position.EntryPrice,
-		ExitPrice:   exitPrice,
-		Quantity:    bt.position.Quantity,
-		PnL:         pnl,
-		PnLPct:      (pnl / (bt.position.EntryPrice * bt.position.Quantity)) * 100,
-		Commission:  totalCommission,
-		NetPnL:      netPnL,
-		EntryReason: bt.position.EntryReason,
-		ExitReason:  reason,
-	}
-
-	if action == "CLOSE" {
-		trade.Duration = bt.currentTime.Sub(bt.position.EntryTime)
-	}
-
-	bt.trades = append(bt.trades, trade)
-}
-
-// generateTripleEMA1HResults สร้างผลลัพธ์สำหรับ 1H
-func (bt *Backtester) generateTripleEMA1HResults() *BacktestResult {
-	totalReturn := ((bt.currentCapital - bt.initialCapital) / bt.initialCapital) * 100
-
-	// นับ trades
-	var winTrades, lossTrades int
-	var totalPnL float64
-
-	for _, trade := range bt.trades {
-		if trade.NetPnL != 0 {
-			totalPnL += trade.NetPnL
-			if trade.NetPnL > 0 {
-				winTrades++
-			} else {
-				lossTrades++
-			}
-		}
-	}
-
-	totalTrades := winTrades + lossTrades
-	var winRate float64
-	if totalTrades > 0 {
-		winRate = (float64(winTrades) / float64(totalTrades)) * 100
-	}
-
-	return &BacktestResult{
-		Symbol:         bt.symbol,
-		StartDate:      bt.startDate,
-		EndDate:        bt.endDate,
-		InitialCapital: bt.initialCapital,
-		FinalCapital:   bt.currentCapital,
-		TotalReturn:    bt.currentCapital - bt.initialCapital,
-		TotalReturnPct: totalReturn,
-		TotalTrades:    totalTrades,
-		WinningTrades:  winTrades,
-		LosingTrades:   lossTrades,
-		WinRate:        winRate,
-		MaxDrawdown:    0.0,
-		MaxDrawdownPct: 0.0,
-		Trades:         bt.trades,
-		DailyReturns:   bt.dailyReturns,
-	}
+	return exitReason
 }
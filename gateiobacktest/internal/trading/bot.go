@@ -3,6 +3,7 @@ package trading
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -18,6 +19,12 @@ type TradingBot struct {
 	aiClient   *AIClient
 	indicators *Indicators
 	gateClient *GateClient
+	exits      exitPlanExecutor // คำสั่งปิดบางส่วน/ตรวจ SL ของแผนออก (gateClient ยกเว้นใน test)
+
+	// แผนออกแบบทยอยปิด (nil = ให้ AI ตัดสินปิดทั้งก้อนตามเดิม)
+	exitPlan    *ExitPlan
+	stopLossPct float64                   // ระยะ SL เริ่มต้นจากราคาเข้า (%) ใช้คำนวณ R
+	exitStates  map[string]*ExitPlanState // สถานะแผนออกของแต่ละ contract
 }
 
 // NewTradingBot สร้าง instance ใหม่
@@ -35,21 +42,29 @@ func NewTradingBot(apiKey, apiSecret, deepseekKey string) (*TradingBot, error) {
 		return nil, fmt.Errorf("ไม่สามารถสร้าง AI client ได้: %v", err)
 	}
 
-	// สร้าง indicators
-	indicators := NewIndicators()
-
 	// สร้าง gate client wrapper
 	gateClient := NewGateClient(client, ctx)
 
+	// สร้าง indicators (ดึงแท่งเทียนผ่าน gate client)
+	indicators := NewIndicators()
+	indicators.SetMarketDataSource(gateClient)
+
 	return &TradingBot{
 		client:     client,
 		ctx:        ctx,
 		aiClient:   aiClient,
 		indicators: indicators,
 		gateClient: gateClient,
+		exits:      gateClient,
 	}, nil
 }
 
+// exitPlanExecutor คำสั่งบน exchange ที่แผนออกของบอทใช้
+type exitPlanExecutor interface {
+	ReducePosition(contract string, size int64) (bool, error)
+	CheckStopLoss(contract string, stopPrice float64, isLong bool) (bool, error)
+}
+
 // NewBacktesterBot สร้าง bot สำหรับ backtesting โดยไม่ใช้ AI
 func NewBacktesterBot() (*TradingBot, error) {
 	return &TradingBot{
@@ -57,6 +72,23 @@ func NewBacktesterBot() (*TradingBot, error) {
 	}, nil
 }
 
+// SetExitPlan ใช้แผนออกแบบทยอยปิดกับ positions ที่เปิดอยู่ โดยคิด R จาก SL เริ่มต้นที่ stopLossPct% จากราคาเข้า
+// (nil = ปิดแผนออก)
+func (bot *TradingBot) SetExitPlan(plan *ExitPlan, stopLossPct float64) error {
+	if plan != nil {
+		if err := plan.Validate(); err != nil {
+			return err
+		}
+		if stopLossPct <= 0 {
+			return fmt.Errorf("stopLossPct ต้องมากกว่า 0")
+		}
+	}
+	bot.exitPlan = plan
+	bot.stopLossPct = stopLossPct
+	bot.exitStates = make(map[string]*ExitPlanState)
+	return nil
+}
+
 // TestConnections ทดสอบการเชื่อมต่อทั้งหมด
 func (bot *TradingBot) TestConnections() bool {
 	fmt.Println("🔍 ทดสอบการเชื่อมต่อ Gate.io...")
@@ -119,11 +151,101 @@ func (bot *TradingBot) manageExistingPositions() {
 
 	fmt.Printf("📊 พบ %d positions ที่เปิดอยู่\n", len(positions))
 
+	bot.forgetClosedPositions(positions)
+
 	for _, position := range positions {
+		if bot.manageExitPlan(position) {
+			continue
+		}
 		bot.analyzeExistingPosition(position)
 	}
 }
 
+// forgetClosedPositions ล้างสถานะแผนออกของ contract ที่ไม่มี position แล้ว
+func (bot *TradingBot) forgetClosedPositions(positions []*Position) {
+	open := make(map[string]bool, len(positions))
+	for _, position := range positions {
+		open[position.Contract] = true
+	}
+	for contract := range bot.exitStates {
+		if !open[contract] {
+			delete(bot.exitStates, contract)
+		}
+	}
+}
+
+// manageExitPlan ทยอยปิดและเลื่อน SL ตามแผนออกที่ราคา mark ปัจจุบัน (คืน true ถ้า position ถูกปิดหมดแล้ว)
+func (bot *TradingBot) manageExitPlan(position *Position) bool {
+	if bot.exitPlan == nil {
+		return false
+	}
+
+	contract := position.Contract
+	isLong := position.Size > 0
+	size := position.Size
+	if size < 0 {
+		size = -size
+	}
+
+	state := bot.exitStates[contract]
+	if state == nil || state.EntryPrice != position.EntryPrice {
+		side, stopLoss := "LONG", position.EntryPrice*(1-bot.stopLossPct/100)
+		if !isLong {
+			side, stopLoss = "SHORT", position.EntryPrice*(1+bot.stopLossPct/100)
+		}
+
+		var err error
+		state, err = NewExitPlanState(bot.exitPlan, side, position.EntryPrice, stopLoss, float64(size))
+		if err != nil {
+			fmt.Printf("⚠️ ไม่ใช้แผนออกกับ %s: %v\n", contract, err)
+			return false
+		}
+		bot.exitStates[contract] = state
+		fmt.Printf("🪜 เริ่มแผนออก %s %s: ราคาเข้า %.6f, SL %.6f\n", contract, side, state.EntryPrice, stopLoss)
+	}
+
+	price := position.MarkPrice
+	for _, action := range state.TakeProfits(price, price, price) {
+		contracts := int64(math.Round(action.Quantity))
+		if contracts > size {
+			contracts = size
+		}
+		if contracts <= 0 {
+			fmt.Printf("⚠️ %s %s: ขนาดไม่ถึง 1 contract - ข้าม\n", contract, action.Reason)
+			continue
+		}
+
+		fmt.Printf("🪜 %s %s: ปิด %d/%d contracts ที่ %.6f\n", contract, action.Reason, contracts, size, price)
+		success, err := bot.exits.ReducePosition(contract, contracts)
+		if err != nil || !success {
+			// ปิดไม่สำเร็จ: ลองขั้นนี้ใหม่ในรอบถัดไป
+			fmt.Printf("❌ ไม่สามารถปิดบางส่วน %s: %v\n", contract, err)
+			state.LevelsHit = action.Level - 1
+			break
+		}
+		size -= contracts
+	}
+	if size == 0 {
+		delete(bot.exitStates, contract)
+		return true
+	}
+
+	before := state.StopLoss
+	if stop := state.AdjustStop(price, price); stop != before {
+		fmt.Printf("🛡️ เลื่อน SL %s ตามแผนออก: %.6f → %.6f\n", contract, before, stop)
+	}
+
+	stopped, err := bot.exits.CheckStopLoss(contract, state.StopLoss, isLong)
+	if err != nil {
+		fmt.Printf("❌ ตรวจสอบ SL %s ไม่สำเร็จ: %v\n", contract, err)
+		return false
+	}
+	if stopped {
+		delete(bot.exitStates, contract)
+	}
+	return stopped
+}
+
 // analyzeExistingPosition วิเคราะห์ position ที่เปิดอยู่
 func (bot *TradingBot) analyzeExistingPosition(position *Position) {
 	contract := position.Contract
@@ -0,0 +1,353 @@
+package trading
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StrategyConfigVersion เวอร์ชันล่าสุดของรูปแบบไฟล์ config ที่รองรับ
+const StrategyConfigVersion = 1
+
+// configDateLayout รูปแบบวันที่ใน config (UTC)
+const configDateLayout = "2006-01-02"
+
+// StrategyConfig การตั้งค่ากลยุทธ์ที่โหลดจากไฟล์ YAML/JSON และถูกแนบไปกับ BacktestResult
+type StrategyConfig struct {
+	Version   int            `json:"version" yaml:"version"`
+	Strategy  string         `json:"strategy" yaml:"strategy"` // ชื่อใน registry เช่น "pivot-supertrend"
+	Symbols   []string       `json:"symbols" yaml:"symbols"`
+	Timeframe string         `json:"timeframe" yaml:"timeframe"`                       // เช่น "1h" (resample จากข้อมูล 15m)
+	StartDate string         `json:"start_date,omitempty" yaml:"start_date,omitempty"` // YYYY-MM-DD (ว่าง = ใช้ days)
+	EndDate   string         `json:"end_date,omitempty" yaml:"end_date,omitempty"`     // YYYY-MM-DD (ว่าง = ถึงข้อมูลล่าสุด)
+	Days      int            `json:"days,omitempty" yaml:"days,omitempty"`             // จำนวนวันย้อนหลังเมื่อไม่กำหนด start_date
+	Params    StrategyParams `json:"params,omitzero" yaml:"params,omitempty"`          // ว่างเมื่อกลยุทธ์ไม่ Tunable
+	Risk      RiskConfig     `json:"risk" yaml:"risk"`
+
+	// IncrementalIndicators ใช้ตัวชี้วัดแบบ streaming บนประวัติทั้งหมดแทนการคำนวณใหม่บนหน้าต่าง max_lookback แท่ง
+	IncrementalIndicators bool `json:"incremental_indicators,omitempty" yaml:"incremental_indicators,omitempty"`
+}
+
+// RiskConfig การตั้งค่าเงินทุน ค่าธรรมเนียม และการจำลองการ fill
+type RiskConfig struct {
+	Capital         float64         `json:"capital" yaml:"capital"`
+	Commission      float64         `json:"commission" yaml:"commission"`                                   // อัตราค่าคอมมิชชั่นต่อขา (0 = ค่าเริ่มต้นของ backtester)
+	RiskPerTrade    float64         `json:"risk_per_trade,omitempty" yaml:"risk_per_trade,omitempty"`       // สัดส่วนเงินทุนที่เสี่ยงต่อเทรด (0.02 = 2%, ใช้เฉพาะกลยุทธ์ RiskSized)
+	IntrabarFills   *bool           `json:"intrabar_fills,omitempty" yaml:"intrabar_fills,omitempty"`       // nil = ค่าเริ่มต้น (เปิด)
+	SameBarPriority SameBarPriority `json:"same_bar_priority,omitempty" yaml:"same_bar_priority,omitempty"` // ว่าง = STOP_LOSS_FIRST
+	Futures         *FuturesAccount `json:"futures,omitempty" yaml:"futures,omitempty"`                     // nil = ไม่จำลอง margin/liquidation
+	Funding         *FundingConfig  `json:"funding,omitempty" yaml:"funding,omitempty"`                     // nil = ไม่คิด funding
+	Pyramid         *PyramidConfig  `json:"pyramid,omitempty" yaml:"pyramid,omitempty"`                     // nil = ไม่เพิ่มไม้
+
+	// Fees ค่าธรรมเนียมที่กลยุทธ์ใช้จริงหลัง RunStrategy (บางกลยุทธ์ไม่ใช้ commission หรือคิดเฉพาะขาออก)
+	// บันทึกในผลลัพธ์เท่านั้น ApplyConfig ไม่อ่านค่านี้
+	Fees *FeeModel `json:"fees,omitempty" yaml:"fees,omitempty"`
+}
+
+// DefaultStrategyConfig ค่าเริ่มต้นเดียวกับ CLI: pivot-supertrend, SOL_USDT, 1h, 365 วัน, ทุน $1000
+func DefaultStrategyConfig() StrategyConfig {
+	return StrategyConfig{
+		Version:   StrategyConfigVersion,
+		Strategy:  "pivot-supertrend",
+		Symbols:   []string{"SOL_USDT"},
+		Timeframe: "1h",
+		Days:      365,
+		Params:    DefaultStrategyParams(),
+		Risk: RiskConfig{
+			Capital:      1000,
+			RiskPerTrade: 0.02,
+		},
+	}
+}
+
+// LoadStrategyConfig โหลด config จากไฟล์ .yaml/.yml หรือ .json
+// ค่าที่ไม่ได้ระบุในไฟล์ใช้ค่าจาก DefaultStrategyConfig และชื่อ field ที่ไม่รู้จักถือเป็นข้อผิดพลาด
+func LoadStrategyConfig(filename string) (StrategyConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return StrategyConfig{}, fmt.Errorf("ไม่สามารถอ่านไฟล์ config %s: %v", filename, err)
+	}
+
+	config := DefaultStrategyConfig()
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&config)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&config)
+	default:
+		return StrategyConfig{}, fmt.Errorf("ไม่รองรับไฟล์ config %s (ใช้ .yaml, .yml หรือ .json)", filename)
+	}
+	if err != nil {
+		return StrategyConfig{}, fmt.Errorf("ไม่สามารถอ่าน config %s: %v", filename, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return StrategyConfig{}, fmt.Errorf("config %s ไม่ถูกต้อง: %v", filename, err)
+	}
+	return config, nil
+}
+
+// Save บันทึก config เป็น YAML หรือ JSON ตามนามสกุลไฟล์
+func (c StrategyConfig) Save(filename string) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(c)
+	case ".json":
+		data, err = json.MarshalIndent(c, "", "  ")
+	default:
+		return fmt.Errorf("ไม่รองรับไฟล์ config %s (ใช้ .yaml, .yml หรือ .json)", filename)
+	}
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถแปลง config: %v", err)
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// Validate ตรวจสอบว่า config ใช้งานได้
+func (c StrategyConfig) Validate() error {
+	if c.Version < 1 || c.Version > StrategyConfigVersion {
+		return fmt.Errorf("ไม่รองรับ config version %d (รองรับ 1-%d)", c.Version, StrategyConfigVersion)
+	}
+	info, err := LookupStrategy(c.Strategy)
+	if err != nil {
+		return err
+	}
+	if len(c.Symbols) == 0 {
+		return fmt.Errorf("ต้องระบุ symbols อย่างน้อย 1 เหรียญ")
+	}
+	if c.Timeframe != "" {
+		if _, err := ParseInterval(c.Timeframe); err != nil {
+			return err
+		}
+	}
+	start, end, err := c.DateRange()
+	if err != nil {
+		return err
+	}
+	if start.IsZero() && c.Days < 1 {
+		return fmt.Errorf("ต้องระบุ start_date หรือ days")
+	}
+	if !end.IsZero() && !end.After(start) {
+		return fmt.Errorf("end_date (%s) ต้องอยู่หลัง start_date (%s)", c.EndDate, c.StartDate)
+	}
+	if info.Tunable {
+		if err := c.Params.Validate(); err != nil {
+			return err
+		}
+	}
+
+	risk := c.Risk
+	if risk.Capital <= 0 {
+		return fmt.Errorf("capital ต้องมากกว่า 0")
+	}
+	if risk.Commission < 0 {
+		return fmt.Errorf("commission ต้องไม่ติดลบ")
+	}
+	if info.RiskSized && (risk.RiskPerTrade <= 0 || risk.RiskPerTrade > 1) {
+		return fmt.Errorf("risk_per_trade ต้องอยู่ระหว่าง 0-1")
+	}
+	switch risk.SameBarPriority {
+	case "", StopLossFirst, TakeProfitFirst, NearestToOpen:
+	default:
+		return fmt.Errorf("ไม่รู้จัก same_bar_priority: %s", risk.SameBarPriority)
+	}
+	if risk.Futures != nil {
+		switch risk.Futures.MarginMode {
+		case "", IsolatedMargin, CrossMargin:
+		default:
+			return fmt.Errorf("ไม่รู้จัก margin_mode: %s", risk.Futures.MarginMode)
+		}
+	}
+	if risk.Funding != nil {
+		if err := risk.Funding.Validate(); err != nil {
+			return err
+		}
+	}
+	if risk.Pyramid != nil {
+		if err := risk.Pyramid.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DateRange ช่วงวันที่ของ config (zero = ไม่ได้กำหนด) โดย end_date นับรวมทั้งวัน
+func (c StrategyConfig) DateRange() (start, end time.Time, err error) {
+	if c.StartDate != "" {
+		if start, err = time.Parse(configDateLayout, c.StartDate); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("start_date ไม่ถูกต้อง (ใช้ YYYY-MM-DD): %s", c.StartDate)
+		}
+	}
+	if c.EndDate != "" {
+		if end, err = time.Parse(configDateLayout, c.EndDate); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("end_date ไม่ถูกต้อง (ใช้ YYYY-MM-DD): %s", c.EndDate)
+		}
+		end = end.Add(24*time.Hour - time.Second)
+	}
+	return start, end, nil
+}
+
+// configWarmupDays จำนวนวันก่อน start_date ที่โหลดไว้เป็น warmup ของ indicators
+const configWarmupDays = 30
+
+// LoadCandles โหลดแท่งเทียนของ symbol ตาม timeframe และช่วงวันที่ของ config
+func (c StrategyConfig) LoadCandles(symbol string) ([]OHLCV, error) {
+	start, _, err := c.DateRange()
+	if err != nil {
+		return nil, err
+	}
+	if start.IsZero() {
+		return LoadCandles(symbol, c.Days, c.Timeframe)
+	}
+	return LoadCandlesSince(symbol, start.AddDate(0, 0, -configWarmupDays), c.Timeframe)
+}
+
+// ApplyConfig ใช้พารามิเตอร์ ความเสี่ยง และช่วงวันที่จาก config กับ backtester
+// (เรียกก่อน RunStrategy; เงินทุนเริ่มต้นถูกตั้งใหม่ตาม config)
+// params ใช้เฉพาะกลยุทธ์ Tunable และ risk_per_trade เฉพาะกลยุทธ์ RiskSized ส่วนกลยุทธ์อื่นไม่อ่านค่าเหล่านี้
+func (bt *Backtester) ApplyConfig(config StrategyConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	info, _ := LookupStrategy(config.Strategy)
+	if info.Tunable {
+		if err := bt.SetStrategyParams(config.Params); err != nil {
+			return err
+		}
+	} else {
+		config.Params = StrategyParams{}
+	}
+
+	risk := config.Risk
+	bt.initialCapital = risk.Capital
+	bt.currentCapital = risk.Capital
+	if risk.Commission > 0 {
+		bt.commission = risk.Commission
+	}
+	if info.RiskSized {
+		bt.riskPerTrade = risk.RiskPerTrade
+	} else {
+		config.Risk.RiskPerTrade = 0
+	}
+	if risk.IntrabarFills != nil {
+		bt.SetIntrabarFills(*risk.IntrabarFills)
+	}
+	if risk.SameBarPriority != "" {
+		bt.SetSameBarPriority(risk.SameBarPriority)
+	}
+	if risk.Futures != nil {
+		bt.SetFuturesAccount(*risk.Futures)
+	}
+	if risk.Funding != nil {
+		model, err := risk.Funding.Model(bt.symbol)
+		if err != nil {
+			return err
+		}
+		bt.SetFundingModel(model)
+		if model.Source != "" {
+			bt.logf("📊 โหลด funding rate %d รายการจากไฟล์ %s\n", len(model.Series), model.Source)
+		}
+	}
+	if p := risk.Pyramid; p != nil {
+		bt.SetPositionManager(NewPositionManagerWithLimits(p.MaxLegs, p.MinProfitPct, p.TotalRisk))
+		bt.SetPyramiding(true)
+	}
+
+	start, end, _ := config.DateRange()
+	if !start.IsZero() {
+		if end.IsZero() {
+			end = time.Now()
+		}
+		bt.startDate, bt.endDate = start, end
+		bt.windowed = true
+	}
+	bt.SetIncrementalIndicators(config.IncrementalIndicators)
+
+	bt.config = &config
+	return nil
+}
+
+// EffectiveConfig config ที่ backtester ใช้จริง (ค่าจาก ApplyConfig หรือค่าเริ่มต้น
+// แทนที่ด้วยสถานะปัจจุบันของ backtester) สำหรับแนบไปกับผลลัพธ์
+func (bt *Backtester) EffectiveConfig() *StrategyConfig {
+	config := DefaultStrategyConfig()
+	config.Strategy = ""
+	if bt.config != nil {
+		config = *bt.config
+	}
+
+	if config.Strategy == "" {
+		config.Strategy = bt.strategyName
+	}
+	config.Symbols = []string{bt.symbol}
+	if config.Timeframe == "" && len(bt.ohlcvData) > 1 {
+		config.Timeframe = formatInterval(bt.baseDuration())
+	}
+	if bt.windowed {
+		config.StartDate = bt.startDate.UTC().Format(configDateLayout)
+		config.EndDate = bt.endDate.UTC().Format(configDateLayout)
+		config.Days = 0
+	}
+
+	config.IncrementalIndicators = bt.incremental
+	intrabarFills := bt.intrabarFills
+	config.Risk = RiskConfig{
+		Capital:         bt.initialCapital,
+		Commission:      bt.commission,
+		IntrabarFills:   &intrabarFills,
+		SameBarPriority: bt.sameBarPriority,
+	}
+	// บันทึก params/risk_per_trade เฉพาะเมื่อกลยุทธ์อ่านค่าเหล่านี้ (กลยุทธ์นอก registry ถือว่าอ่านทั้งคู่)
+	info, err := LookupStrategy(config.Strategy)
+	if err != nil || info.Tunable {
+		config.Params = bt.params
+	} else {
+		config.Params = StrategyParams{}
+	}
+	if err != nil || info.RiskSized {
+		config.Risk.RiskPerTrade = bt.riskPerTrade
+	}
+	if bt.futures != nil {
+		account := *bt.futures
+		config.Risk.Futures = &account
+	}
+	if bt.funding != nil {
+		config.Risk.Funding = bt.funding.config()
+	}
+	if bt.pyramiding {
+		config.Risk.Pyramid = bt.positionManager.config()
+	}
+	// หลัง RunStrategy ค่าธรรมเนียมที่คิดจริงมาจาก Strategy.Fees ซึ่งอาจไม่ใช่ commission
+	// (commission ยังบันทึกค่าที่ตั้งไว้เพื่อให้รันซ้ำจาก config นี้ได้ผลเดิม)
+	if bt.strategyName != "" {
+		fees := bt.fees
+		config.Risk.Fees = &fees
+	}
+	return &config
+}
+
+// formatInterval แปลงความยาวแท่งเป็นรูปแบบเดียวกับ ParseInterval เช่น 1h, 15m, 1d
+func formatInterval(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return ""
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
@@ -0,0 +1,75 @@
+package trading
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyConfigSkipsSettingsStrategyDoesNotRead(t *testing.T) {
+	for _, tc := range []struct {
+		strategy          string
+		tunable, riskSize bool
+		fees              FeeModel // ค่าธรรมเนียมที่กลยุทธ์คิดจริงเมื่อ commission = 0.002
+	}{
+		{"pivot-supertrend", true, true, FeeModel{EntryRate: 0.002, ExitRate: 0.002}},
+		{"aggressive", true, false, FeeModel{ExitRate: 0.002}},
+		{"triple-ema-1h", false, false, FeeModel{EntryRate: 0.0005, ExitRate: 0.0005, ChargeEntryOnOpen: true}},
+	} {
+		t.Run(tc.strategy, func(t *testing.T) {
+			config := DefaultStrategyConfig()
+			config.Strategy = tc.strategy
+			config.Timeframe = "15m"
+			config.Params.ATRFactor = 2
+			config.Risk.RiskPerTrade = 0.05
+			config.Risk.Commission = 0.002
+
+			bt := newQuietBacktester(t, "SOL_USDT", 0)
+			if err := bt.ApplyConfig(config); err != nil {
+				t.Fatal(err)
+			}
+			if got := bt.StrategyParams().ATRFactor == 2; got != tc.tunable {
+				t.Fatalf("ใช้ params กับ backtester = %v, ต้องการ %v", got, tc.tunable)
+			}
+			if got := bt.riskPerTrade == 0.05; got != tc.riskSize {
+				t.Fatalf("ใช้ risk_per_trade กับ backtester = %v, ต้องการ %v", got, tc.riskSize)
+			}
+
+			bt.LoadHistoricalData(syntheticCandles(300, 3))
+			info, _ := LookupStrategy(tc.strategy)
+			result, err := bt.RunStrategy(info.New())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// config ที่แนบกับผลลัพธ์มีเฉพาะค่าที่กลยุทธ์อ่านจริง
+			recorded := result.Config
+			wantParams, wantRisk := StrategyParams{}, 0.0
+			if tc.tunable {
+				wantParams = config.Params
+			}
+			if tc.riskSize {
+				wantRisk = 0.05
+			}
+			if recorded.Params != wantParams || recorded.Risk.RiskPerTrade != wantRisk {
+				t.Fatalf("result.Config params %+v risk_per_trade %v, ต้องการ %+v และ %v",
+					recorded.Params, recorded.Risk.RiskPerTrade, wantParams, wantRisk)
+			}
+			// ค่าธรรมเนียมที่คิดจริงมาจาก Strategy.Fees ส่วน commission คงค่าที่ตั้งไว้สำหรับรันซ้ำ
+			if recorded.Risk.Fees == nil || *recorded.Risk.Fees != tc.fees || recorded.Risk.Commission != 0.002 {
+				t.Fatalf("result.Config fees %+v commission %v, ต้องการ %+v และ 0.002", recorded.Risk.Fees, recorded.Risk.Commission, tc.fees)
+			}
+
+			// config ที่บันทึกไว้ต้องโหลดกลับมาใช้ได้
+			filename := filepath.Join(t.TempDir(), "config.yaml")
+			if err := recorded.Save(filename); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := LoadStrategyConfig(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loaded.Risk.Fees == nil || *loaded.Risk.Fees != tc.fees {
+				t.Fatalf("โหลด fees กลับมาได้ %+v, ต้องการ %+v", loaded.Risk.Fees, tc.fees)
+			}
+		})
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -241,3 +244,124 @@ func (df *DataFetcher) GetSymbolInfo(data []OHLCV) {
 		startTime.Format("2006-01-02"),
 		endTime.Format("2006-01-02"))
 }
+
+// LoadCandlesFile โหลดแท่งเทียนจากไฟล์ JSON (รูปแบบเดียวกับไฟล์ที่ DataFetcher บันทึก)
+func LoadCandlesFile(filename string) ([]OHLCV, error) {
+	data, err := (&DataFetcher{}).loadFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถโหลดไฟล์ %s: %v", filename, err)
+	}
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Timestamp < data[j].Timestamp
+	})
+	return data, nil
+}
+
+// LoadCandles โหลดแท่งเทียน 15m ย้อนหลัง days วันแล้ว resample เป็น interval (ว่าง = 15m)
+// ใช้ไฟล์ data_<symbol>_15m_<N>d.json ที่มี N ≥ days ถ้ามี (ตัดเหลือ days วันล่าสุด) ไม่เช่นนั้นดึงจาก API
+func LoadCandles(symbol string, days int, interval string) ([]OHLCV, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("จำนวนวันต้องมากกว่า 0: %d", days)
+	}
+
+	var data []OHLCV
+	if filename := cachedDataFile(symbol, days); filename != "" {
+		loaded, err := LoadCandlesFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		data = loaded
+	} else {
+		fetched, err := NewDataFetcher(symbol, days, "15m").FetchOrLoadData()
+		if err != nil {
+			return nil, err
+		}
+		data = fetched
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาของ %s", symbol)
+	}
+
+	// ตัดเหลือ days วันล่าสุด
+	from := data[len(data)-1].Timestamp - int64(days)*24*3600
+	first := sort.Search(len(data), func(i int) bool {
+		return data[i].Timestamp > from
+	})
+	data = data[first:]
+
+	if interval == "" || interval == "15m" {
+		return data, nil
+	}
+	return Resample(data, interval)
+}
+
+// LoadCandlesSince โหลดแท่งเทียน 15m ตั้งแต่ from แล้ว resample เป็น interval (ว่าง = 15m)
+// ใช้ไฟล์ data_<symbol>_15m_<N>d.json ที่สั้นที่สุดซึ่งครอบคลุม from ถ้ามี ไม่เช่นนั้นดึงจาก API
+func LoadCandlesSince(symbol string, from time.Time, interval string) ([]OHLCV, error) {
+	var data []OHLCV
+	for _, file := range cachedDataFiles(symbol) {
+		loaded, err := LoadCandlesFile(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if len(loaded) > 0 && loaded[0].Timestamp <= from.Unix() {
+			data = loaded
+			break
+		}
+	}
+	if data == nil {
+		days := int(time.Since(from).Hours()/24) + 1
+		fetched, err := NewDataFetcher(symbol, days, "15m").FetchOrLoadData()
+		if err != nil {
+			return nil, err
+		}
+		data = fetched
+	}
+
+	first := sort.Search(len(data), func(i int) bool {
+		return data[i].Timestamp >= from.Unix()
+	})
+	data = data[first:]
+	if len(data) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาของ %s ตั้งแต่ %s", symbol, from.Format("2006-01-02"))
+	}
+
+	if interval == "" || interval == "15m" {
+		return data, nil
+	}
+	return Resample(data, interval)
+}
+
+// cachedFile ไฟล์ข้อมูล 15m ที่บันทึกไว้และจำนวนวันที่ครอบคลุมตามชื่อไฟล์
+type cachedFile struct {
+	name string
+	days int
+}
+
+// cachedDataFiles ไฟล์ข้อมูล 15m ของเหรียญเรียงจากช่วงสั้นไปยาว
+func cachedDataFiles(symbol string) []cachedFile {
+	matches, _ := filepath.Glob(fmt.Sprintf("data_%s_15m_*d.json", symbol))
+
+	var files []cachedFile
+	for _, name := range matches {
+		var n int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(name, fmt.Sprintf("data_%s_15m_", symbol)), "%dd.json", &n); err != nil {
+			continue
+		}
+		files = append(files, cachedFile{name: name, days: n})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].days < files[j].days
+	})
+	return files
+}
+
+// cachedDataFile ไฟล์ข้อมูล 15m ของเหรียญที่ครอบคลุมอย่างน้อย days วัน (เลือกไฟล์ที่สั้นที่สุด, "" = ไม่พบ)
+func cachedDataFile(symbol string, days int) string {
+	for _, file := range cachedDataFiles(symbol) {
+		if file.days >= days {
+			return file.name
+		}
+	}
+	return ""
+}
@@ -2,46 +2,53 @@ package trading
 
 import (
 	"fmt"
-	"time"
 )
 
 // AggressiveStrategy - เมธอดใหม่สำหรับกลยุทธ์ที่เน้นกำไรสูง
 func (bt *Backtester) RunAggressiveBacktest() *BacktestResult {
-	fmt.Printf("\n🚀 เริ่มต้น Aggressive Profit Strategy %s\n", bt.symbol)
-	fmt.Printf("💰 เงินทุนเริ่มต้น: $%.2f\n", bt.initialCapital)
-	fmt.Printf("🎯 เป้าหมาย: เพิ่มกำไรให้สูงกว่าเดิม\n")
-
-	for bt.currentIndex = 100; bt.currentIndex < len(bt.ohlcvData); bt.currentIndex++ {
-		currentCandle := bt.ohlcvData[bt.currentIndex]
-		bt.currentTime = time.Unix(currentCandle.Timestamp, 0)
-		bt.currentPrice = currentCandle.Close
-
-		// วิเคราะห์ตลาด
-		analysis := bt.analyzeMarket()
-		if analysis == nil {
-			continue
-		}
+	return bt.runStrategyResult(NewAggressiveStrategy())
+}
 
-		// จัดการ position ที่มีอยู่ด้วยกลยุทธ์ aggressive
-		bt.checkAggressivePosition()
+// AggressiveStrategy กลยุทธ์เน้นกำไรสูง: LONG ตาม SuperTrend + Volume + Momentum, R:R 1:4
+type AggressiveStrategy struct {
+	analysis *SuperTrendAnalysis // ผลวิเคราะห์ของแท่งปัจจุบัน
+}
 
-		// หาจุดเข้าใหม่ด้วยกลยุทธ์ที่เน้นกำไร
-		bt.lookForAggressiveEntry(analysis)
-	}
+// NewAggressiveStrategy สร้างกลยุทธ์ Aggressive Profit
+func NewAggressiveStrategy() *AggressiveStrategy {
+	return &AggressiveStrategy{}
+}
 
-	// ปิด position สุดท้าย
-	if bt.position != nil {
-		bt.closeAggressivePosition("End of Backtest")
-	}
+// Name ชื่อกลยุทธ์
+func (s *AggressiveStrategy) Name() string {
+	return "Aggressive Profit Strategy"
+}
 
-	return bt.generateAggressiveResults()
+// EndOfDataReason เหตุผลปิดตอนข้อมูลหมดตาม runner เดิม
+func (s *AggressiveStrategy) EndOfDataReason() string {
+	return "End of Backtest"
 }
 
-// lookForAggressiveEntry หาจุดเข้าที่ให้กำไรสูง
-func (bt *Backtester) lookForAggressiveEntry(analysis *SuperTrendAnalysis) {
-	// ไม่เข้าถ้ามี position อยู่แล้ว
-	if bt.position != nil {
-		return
+// WarmupBars เริ่มจากแท่งที่ 100
+func (s *AggressiveStrategy) WarmupBars() int {
+	return 100
+}
+
+// Fees คิดค่าคอมมิชชั่นเฉพาะขาออก
+func (s *AggressiveStrategy) Fees(bt *Backtester) FeeModel {
+	return FeeModel{ExitRate: bt.commission}
+}
+
+// OnBar วิเคราะห์ตลาด
+func (s *AggressiveStrategy) OnBar(bt *Backtester) {
+	s.analysis = bt.analyzeMarket()
+}
+
+// EntrySignal หาจุดเข้าที่ให้กำไรสูง
+func (s *AggressiveStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	analysis := s.analysis
+	if analysis == nil {
+		return nil
 	}
 
 	currentCandle := bt.ohlcvData[bt.currentIndex]
@@ -53,8 +60,22 @@ func (bt *Backtester) lookForAggressiveEntry(analysis *SuperTrendAnalysis) {
 		bt.isVolumeStrong(currentCandle) && // Volume แข็งแกร่ง
 		bt.isPriceMomentumStrong() { // Momentum แข็งแกร่ง
 
-		bt.openAggressivePosition("LONG", analysis)
+		// Stop Loss ใกล้ SuperTrend มากขึ้น (เสี่ยงสูงแต่ Risk:Reward ดีขึ้น)
+		stopLoss := analysis.SuperTrendValue * 0.997 // ใกล้ SuperTrend 0.3%
+
+		// Take Profit ที่ 1:4 Risk:Reward
+		riskDistance := bt.currentPrice - stopLoss
+		takeProfit := bt.currentPrice + (riskDistance * 4.0) // 1:4 R:R
+
+		return &EntrySignal{
+			Side:       "LONG",
+			StopLoss:   stopLoss,
+			TakeProfit: takeProfit,
+			Reason:     fmt.Sprintf("Aggressive %s: Conf=%.1f%%, R:R=1:4", "LONG", analysis.Confidence),
+		}
 	}
+
+	return nil
 }
 
 // isVolumeStrong ตรวจสอบ Volume แข็งแกร่ง
@@ -91,16 +112,9 @@ func (bt *Backtester) isPriceMomentumStrong() bool {
 	return upCount >= 3
 }
 
-// openAggressivePosition เปิด position ที่เน้นกำไร
-func (bt *Backtester) openAggressivePosition(side string, analysis *SuperTrendAnalysis) {
-	// Stop Loss ใกล้ SuperTrend มากขึ้น (เสี่ยงสูงแต่ Risk:Reward ดีขึ้น)
-	stopLoss := analysis.SuperTrendValue * 0.997 // ใกล้ SuperTrend 0.3%
-
-	// Take Profit ที่ 1:4 Risk:Reward
-	riskDistance := bt.currentPrice - stopLoss
-	takeProfit := bt.currentPrice + (riskDistance * 4.0) // 1:4 R:R
-
-	// เพิ่มขนาด position เป็น 3% (จากเดิม 2%)
+// PositionSize ขนาด position เสี่ยง 3% ของเงินทุน (จากเดิม 2%) และใช้เงินไม่เกิน 95%
+func (s *AggressiveStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	riskDistance := bt.currentPrice - signal.StopLoss
 	riskAmount := bt.currentCapital * 0.03
 	positionSize := riskAmount / riskDistance
 
@@ -110,112 +124,41 @@ func (bt *Backtester) openAggressivePosition(side string, analysis *SuperTrendAn
 		positionSize = maxValue / bt.currentPrice
 	}
 
-	// สร้าง position
-	bt.position = &BacktestPosition{
-		Symbol:      bt.symbol,
-		Side:        side,
-		EntryTime:   bt.currentTime,
-		EntryPrice:  bt.currentPrice,
-		Quantity:    positionSize,
-		StopLoss:    stopLoss,
-		TakeProfit:  takeProfit,
-		EntryReason: fmt.Sprintf("Aggressive %s: Conf=%.1f%%, R:R=1:4", side, analysis.Confidence),
-	}
-
-	// บันทึก trade
-	bt.recordAggressiveTrade("OPEN", fmt.Sprintf("Aggressive Entry: %s (R:R 1:4)", side))
-
-	fmt.Printf("📈 เปิด Aggressive Position: %s @ $%.2f, SL: $%.2f (%.2f%%), TP: $%.2f (%.2f%%), Size: %.6f\n",
-		side, bt.currentPrice, stopLoss,
-		((stopLoss-bt.currentPrice)/bt.currentPrice)*100,
-		takeProfit,
-		((takeProfit-bt.currentPrice)/bt.currentPrice)*100,
-		positionSize)
-}
-
-// recordAggressiveTrade บันทึก trade
-func (bt *Backtester) recordAggressiveTrade(action, reason string) {
-	bt.tradeID++
-
-	var exitTime time.Time
-	var exitPrice, pnl, netPnL float64
-
-	if action == "CLOSE" && bt.position != nil {
-		exitTime = bt.currentTime
-		exitPrice = bt.currentPrice
-		pnl = (bt.currentPrice - bt.position.EntryPrice) * bt.position.Quantity
-		commission := bt.currentPrice * bt.position.Quantity * bt.commission
-		netPnL = pnl - commission
-	}
-
-	trade := BacktestTrade{
-		ID:          bt.tradeID,
-		Symbol:      bt.symbol,
-		Side:        bt.position.Side,
-		EntryTime:   bt.position.EntryTime,
-		ExitTime:    exitTime,
-		EntryPrice:  bt.position.EntryPrice,
-		ExitPrice:   exitPrice,
-		Quantity:    bt.position.Quantity,
-		PnL:         pnl,
-		PnLPct:      (pnl / (bt.position.EntryPrice * bt.position.Quantity)) * 100,
-		Commission:  bt.currentPrice * bt.position.Quantity * bt.commission,
-		NetPnL:      netPnL,
-		EntryReason: bt.position.EntryReason,
-		ExitReason:  reason,
-	}
-
-	if action == "CLOSE" {
-		trade.Duration = bt.currentTime.Sub(bt.position.EntryTime)
-	}
-
-	bt.trades = append(bt.trades, trade)
+	return positionSize
 }
 
-// checkAggressivePosition ตรวจสอบ position ด้วยกลยุทธ์เน้นกำไร
-func (bt *Backtester) checkAggressivePosition() {
-	if bt.position == nil {
-		return
-	}
-
+// ExitSignal ตรวจสอบ position ด้วยกลยุทธ์เน้นกำไร
+func (s *AggressiveStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
 	// อัปเดต trailing stop ที่เข้มงวดขึ้น
 	bt.updateAggressiveTrailingStop()
 
 	// เงื่อนไขออก
-	shouldExit := false
 	exitReason := ""
 
 	// Stop Loss
-	if bt.currentPrice <= bt.position.StopLoss {
-		shouldExit = true
+	if bt.currentPrice <= pos.StopLoss {
 		exitReason = "Stop Loss"
 	}
 
 	// Take Profit
-	if bt.currentPrice >= bt.position.TakeProfit {
-		shouldExit = true
+	if bt.currentPrice >= pos.TakeProfit {
 		exitReason = "Take Profit"
 	}
 
 	// ออกเมื่อมีกำไร 3% และ trend เริ่มอ่อนแอ
-	currentPnL := ((bt.currentPrice - bt.position.EntryPrice) / bt.position.EntryPrice) * 100
+	currentPnL := ((bt.currentPrice - pos.EntryPrice) / pos.EntryPrice) * 100
 	if currentPnL >= 3.0 {
-		analysis := bt.analyzeMarket()
-		if analysis != nil && analysis.Trend != 1 {
-			shouldExit = true
+		if s.analysis != nil && s.analysis.Trend != 1 {
 			exitReason = "Profit Protection (3%+ with trend weakness)"
 		}
 	}
 
 	// Time exit (ถือไม่เกิน 18 ชั่วโมง)
-	if bt.currentTime.Sub(bt.position.EntryTime).Hours() > 18 {
-		shouldExit = true
+	if bt.currentTime.Sub(pos.EntryTime).Hours() > 18 {
 		exitReason = "Time Exit (18h)"
 	}
 
-	if shouldExit {
-		bt.closeAggressivePosition(exitReason)
-	}
+	return exitReason
 }
 
 // updateAggressiveTrailingStop อัปเดต trailing stop แบบ aggressive
@@ -231,76 +174,3 @@ func (bt *Backtester) updateAggressiveTrailingStop() {
 		bt.position.StopLoss = newTrailingStop
 	}
 }
-
-// closeAggressivePosition ปิด position
-func (bt *Backtester) closeAggressivePosition(reason string) {
-	if bt.position == nil {
-		return
-	}
-
-	// คำนวณ PnL
-	pnl := (bt.currentPrice - bt.position.EntryPrice) * bt.position.Quantity
-	commission := bt.currentPrice * bt.position.Quantity * bt.commission
-	netPnL := pnl - commission
-
-	// อัปเดตเงินทุน
-	bt.currentCapital += netPnL
-
-	// บันทึก trade
-	bt.recordAggressiveTrade("CLOSE", reason)
-
-	// แสดงผล
-	pnlPct := (netPnL / (bt.position.EntryPrice * bt.position.Quantity)) * 100
-	fmt.Printf("📉 ปิด Position: PnL $%.2f (%.2f%%) - %s\n", netPnL, pnlPct, reason)
-
-	// ลบ position
-	bt.position = nil
-}
-
-// generateAggressiveResults สร้างผลลัพธ์สำหรับ aggressive strategy
-func (bt *Backtester) generateAggressiveResults() *BacktestResult {
-	// คำนวณสถิติ
-	totalReturn := ((bt.currentCapital - bt.initialCapital) / bt.initialCapital) * 100
-
-	// นับ trades
-	var winTrades, lossTrades int
-	var totalPnL float64
-
-	for _, trade := range bt.trades {
-		if trade.NetPnL != 0 { // Trade ที่ปิดแล้ว
-			totalPnL += trade.NetPnL
-			if trade.NetPnL > 0 {
-				winTrades++
-			} else {
-				lossTrades++
-			}
-		}
-	}
-
-	totalTrades := winTrades + lossTrades
-	var winRate float64
-	if totalTrades > 0 {
-		winRate = (float64(winTrades) / float64(totalTrades)) * 100
-	}
-
-	// คำนวณ max drawdown (simplified)
-	maxDrawdown := 0.0 // ใช้ค่า 0 ก่อน
-
-	return &BacktestResult{
-		Symbol:         bt.symbol,
-		StartDate:      bt.startDate,
-		EndDate:        bt.endDate,
-		InitialCapital: bt.initialCapital,
-		FinalCapital:   bt.currentCapital,
-		TotalReturn:    totalReturn,
-		TotalReturnPct: totalReturn,
-		TotalTrades:    totalTrades,
-		WinningTrades:  winTrades,
-		LosingTrades:   lossTrades,
-		WinRate:        winRate,
-		MaxDrawdown:    maxDrawdown,
-		MaxDrawdownPct: maxDrawdown,
-		Trades:         bt.trades,
-		DailyReturns:   bt.dailyReturns,
-	}
-}
@@ -0,0 +1,102 @@
+package trading
+
+import "time"
+
+// EquityPoint มูลค่าพอร์ต (mark-to-market) ณ เวลาหนึ่ง
+type EquityPoint struct {
+	Time          time.Time `json:"time"`
+	Equity        float64   `json:"equity"`
+	OpenPositions int       `json:"open_positions"`
+}
+
+// dailyEquity รวม equity รายแท่งเป็นรายวันตามปฏิทิน (UTC) จาก timestamp ของแท่งเทียน
+// โดยใช้ equity ของแท่งสุดท้ายของแต่ละวัน
+type dailyEquity struct {
+	initialCapital float64
+	day            time.Time
+	last           float64
+	started        bool
+	days           []DailyReturn
+}
+
+// add บันทึก equity ของแท่ง ถ้าขึ้นวันใหม่จะปิดวันก่อนหน้า
+func (d *dailyEquity) add(t time.Time, equity float64) {
+	day := t.UTC().Truncate(24 * time.Hour)
+	if d.started && !day.Equal(d.day) {
+		d.flush()
+	}
+	d.day = day
+	d.last = equity
+	d.started = true
+}
+
+// flush ปิดวันปัจจุบัน
+func (d *dailyEquity) flush() {
+	if !d.started {
+		return
+	}
+	d.days = appendDailyReturn(d.days, d.day, d.last, d.initialCapital)
+	d.started = false
+}
+
+// appendDailyReturn ต่อ equity ปิดวันท้าย days โดยคิดผลตอบแทนเทียบกับวันก่อนหน้า (วันแรกเทียบกับ base)
+func appendDailyReturn(days []DailyReturn, date time.Time, equity, base float64) []DailyReturn {
+	if n := len(days); n > 0 {
+		base = days[n-1].Equity
+	}
+	var ret float64
+	if base > 0 {
+		ret = (equity - base) / base * 100
+	}
+	return append(days, DailyReturn{Date: date, Return: ret, Equity: equity})
+}
+
+// markToMarket equity รวมกำไร/ขาดทุนที่ยังไม่ปิดของ position ที่ราคาปิดแท่งปัจจุบัน
+func (bt *Backtester) markToMarket() float64 {
+	return bt.currentCapital + bt.UnrealizedPnL()
+}
+
+// resetEquity เริ่มติดตาม equity ใหม่สำหรับการรันหนึ่งครั้ง
+func (bt *Backtester) resetEquity() {
+	bt.equityCurve = nil
+	bt.daily = &dailyEquity{initialCapital: bt.initialCapital}
+	bt.peakEquity = bt.currentCapital
+	bt.maxDrawdown = 0
+	bt.maxDrawdownPct = 0
+}
+
+// recordEquity บันทึก equity แบบ mark-to-market ของแท่งปัจจุบัน และวัด drawdown บนเส้นนี้
+func (bt *Backtester) recordEquity() {
+	equity := bt.markToMarket()
+
+	open := 0
+	if bt.position != nil {
+		open = 1
+	}
+	bt.equityCurve = append(bt.equityCurve, EquityPoint{Time: bt.currentTime, Equity: equity, OpenPositions: open})
+	bt.daily.add(bt.currentTime, equity)
+
+	if equity > bt.peakEquity {
+		bt.peakEquity = equity
+	}
+	if drawdown := bt.peakEquity - equity; drawdown > bt.maxDrawdown {
+		bt.maxDrawdown = drawdown
+	}
+	if bt.peakEquity > 0 {
+		if pct := (bt.peakEquity - equity) / bt.peakEquity * 100; pct > bt.maxDrawdownPct {
+			bt.maxDrawdownPct = pct
+		}
+	}
+}
+
+// finishEquity บันทึก equity หลังปิด position ตอนจบ backtest และปิดวันสุดท้าย
+func (bt *Backtester) finishEquity() {
+	if len(bt.equityCurve) > 0 {
+		last := &bt.equityCurve[len(bt.equityCurve)-1]
+		last.Equity = bt.currentCapital
+		last.OpenPositions = 0
+		bt.daily.last = bt.currentCapital
+	}
+	bt.daily.flush()
+	bt.dailyReturns = bt.daily.days
+}
@@ -0,0 +1,52 @@
+package trading
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyReturnsBucketByUTCDayAcrossTimeframes(t *testing.T) {
+	// วันตามปฏิทินต้องเป็น UTC ไม่ใช่เวลาท้องถิ่นของเครื่อง (UTC+7 จะย้ายแท่ง 17:00-23:59 ไปวันถัดไป)
+	local := time.Local
+	time.Local = time.FixedZone("ICT", 7*3600)
+	defer func() { time.Local = local }()
+
+	quarter := quarterHours(4 * 24 * 3) // 2023-11-14 22:00 ถึง 2023-11-17 21:45 UTC
+	hour, err := Resample(quarter, "1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// เข้า LONG 1 หน่วยที่ราคาปิดของแท่งสุดท้ายของวันแรก (23:45 บน 15m, 23:00 บน 1h) แล้วถือจนจบข้อมูล
+	run := func(data []OHLCV, entry int) *BacktestResult {
+		return runScripted(t, data, &scriptedStrategy{entries: map[int]*EntrySignal{entry: {Side: "LONG", Reason: "test"}}, quantity: 1}, nil)
+	}
+	fine, coarse := run(quarter, 7), run(hour, 1)
+
+	if len(fine.DailyReturns) != 4 || len(coarse.DailyReturns) != 4 {
+		t.Fatalf("ได้ %d วัน (15m) และ %d วัน (1h), ต้องการ 4 วัน (14-17 พ.ย.)", len(fine.DailyReturns), len(coarse.DailyReturns))
+	}
+	for i := range fine.DailyReturns {
+		a, b := fine.DailyReturns[i], coarse.DailyReturns[i]
+		want := time.Date(2023, 11, 14+i, 0, 0, 0, 0, time.UTC)
+		if !a.Date.Equal(want) || a.Date.Location() != time.UTC || !b.Date.Equal(want) {
+			t.Fatalf("วัน %d: 15m %v 1h %v, ต้องการ %v", i, a.Date, b.Date, want)
+		}
+		// equity ปิดวันคือราคาปิดของแท่งสุดท้ายที่เริ่มในวันนั้น ซึ่งเท่ากันทั้งสอง timeframe
+		if !approx(a.Equity, b.Equity) || !approx(a.Return, b.Return) {
+			t.Fatalf("วัน %s: 15m equity %v (%.4f%%), 1h equity %v (%.4f%%)", want.Format("2006-01-02"), a.Equity, a.Return, b.Equity, b.Return)
+		}
+	}
+
+	// equity ปิดวันตรงกับจุดสุดท้ายของวันนั้นบน equity curve รายแท่ง
+	for _, result := range []*BacktestResult{fine, coarse} {
+		closes := make(map[time.Time]float64)
+		for _, point := range result.EquityCurve {
+			closes[point.Time.UTC().Truncate(24*time.Hour)] = point.Equity
+		}
+		for _, day := range result.DailyReturns {
+			if !approx(day.Equity, closes[day.Date]) {
+				t.Fatalf("วัน %s: equity %v, จุดสุดท้ายของวันบน curve %v", day.Date.Format("2006-01-02"), day.Equity, closes[day.Date])
+			}
+		}
+	}
+}
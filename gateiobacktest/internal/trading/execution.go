@@ -0,0 +1,96 @@
+package trading
+
+import "math"
+
+// SameBarPriority กติกาเลือกว่า SL หรือ TP ถูก fill ก่อนเมื่อแท่งเดียวกันแตะทั้งสองระดับ
+type SameBarPriority string
+
+const (
+	// StopLossFirst สมมติว่าโดน Stop Loss ก่อนเสมอ (แบบ conservative - ค่าเริ่มต้น)
+	StopLossFirst SameBarPriority = "STOP_LOSS_FIRST"
+	// TakeProfitFirst สมมติว่าโดน Take Profit ก่อนเสมอ (แบบ optimistic)
+	TakeProfitFirst SameBarPriority = "TAKE_PROFIT_FIRST"
+	// NearestToOpen ระดับที่อยู่ใกล้ราคาเปิดของแท่งถูก fill ก่อน
+	NearestToOpen SameBarPriority = "NEAREST_TO_OPEN"
+)
+
+// SetIntrabarFills เปิด/ปิดการ fill SL/TP ระหว่างแท่งจาก High/Low (ปิด = ดูเฉพาะราคาปิด)
+func (bt *Backtester) SetIntrabarFills(enabled bool) {
+	bt.intrabarFills = enabled
+}
+
+// SetSameBarPriority กำหนดกติกาเมื่อ SL และ TP ถูกแตะในแท่งเดียวกัน
+func (bt *Backtester) SetSameBarPriority(priority SameBarPriority) {
+	bt.sameBarPriority = priority
+}
+
+// intrabarExit ตรวจว่าแท่งเทียนแตะ SL/TP ของ position หรือไม่
+// คืนราคา fill (ราคา trigger หรือราคาเปิดถ้า gap ข้ามระดับ) และเหตุผลการปิด
+func (bt *Backtester) intrabarExit(candle OHLCV) (price float64, reason string, hit bool) {
+	pos := bt.position
+	if pos == nil {
+		return 0, "", false
+	}
+
+	long := pos.Side == "LONG"
+
+	// Gap ตอนเปิดแท่ง: ราคาเปิดข้าม SL/TP ไปแล้ว → fill ที่ราคาเปิด
+	if pos.StopLoss > 0 && ((long && candle.Open <= pos.StopLoss) || (!long && candle.Open >= pos.StopLoss)) {
+		return candle.Open, "STOP_LOSS", true
+	}
+	if pos.TakeProfit > 0 && ((long && candle.Open >= pos.TakeProfit) || (!long && candle.Open <= pos.TakeProfit)) {
+		return candle.Open, "TAKE_PROFIT", true
+	}
+
+	var stopHit, targetHit bool
+	if long {
+		stopHit = pos.StopLoss > 0 && candle.Low <= pos.StopLoss
+		targetHit = pos.TakeProfit > 0 && candle.High >= pos.TakeProfit
+	} else {
+		stopHit = pos.StopLoss > 0 && candle.High >= pos.StopLoss
+		targetHit = pos.TakeProfit > 0 && candle.Low <= pos.TakeProfit
+	}
+
+	switch {
+	case stopHit && targetHit:
+		if bt.stopFillsFirst(candle.Open, pos) {
+			return pos.StopLoss, "STOP_LOSS", true
+		}
+		return pos.TakeProfit, "TAKE_PROFIT", true
+	case stopHit:
+		return pos.StopLoss, "STOP_LOSS", true
+	case targetHit:
+		return pos.TakeProfit, "TAKE_PROFIT", true
+	}
+
+	return 0, "", false
+}
+
+// stopFillsFirst ตัดสินตาม SameBarPriority ว่า SL ถูก fill ก่อน TP หรือไม่
+func (bt *Backtester) stopFillsFirst(open float64, pos *BacktestPosition) bool {
+	switch bt.sameBarPriority {
+	case TakeProfitFirst:
+		return false
+	case NearestToOpen:
+		return math.Abs(open-pos.StopLoss) <= math.Abs(pos.TakeProfit-open)
+	default:
+		return true
+	}
+}
+
+// checkIntrabarFill ปิด position ถ้าแท่งปัจจุบันแตะ SL/TP (คืน true ถ้าปิดแล้ว)
+func (bt *Backtester) checkIntrabarFill(candle OHLCV) bool {
+	if !bt.intrabarFills || bt.position == nil {
+		return false
+	}
+
+	price, reason, hit := bt.intrabarExit(candle)
+	if !hit {
+		return false
+	}
+
+	bt.logf("⚡ Intrabar %s: O=%.2f H=%.2f L=%.2f → fill $%.2f\n",
+		reason, candle.Open, candle.High, candle.Low, price)
+	bt.closePositionAt(price, reason)
+	return true
+}
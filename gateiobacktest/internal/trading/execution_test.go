@@ -0,0 +1,74 @@
+package trading
+
+import "testing"
+
+func TestIntrabarExitSameBarPriority(t *testing.T) {
+	// LONG: SL 95 / TP 110, SHORT: SL 105 / TP 90 ทั้งคู่เข้าที่ 100
+	long := &BacktestPosition{Side: "LONG", EntryPrice: 100, StopLoss: 95, TakeProfit: 110}
+	short := &BacktestPosition{Side: "SHORT", EntryPrice: 100, StopLoss: 105, TakeProfit: 90}
+
+	tests := []struct {
+		name     string
+		pos      *BacktestPosition
+		priority SameBarPriority
+		bar      OHLCV
+		price    float64
+		reason   string
+	}{
+		// แท่งเดียวแตะทั้งสองระดับ: NearestToOpen เลือกตามระยะจากราคาเปิดถึง SL และ TP
+		{"long both stop first", long, StopLossFirst, candle(97, 111, 94, 100), 95, "STOP_LOSS"},
+		{"long both take profit first", long, TakeProfitFirst, candle(97, 111, 94, 100), 110, "TAKE_PROFIT"},
+		{"long both nearest stop", long, NearestToOpen, candle(97, 111, 94, 100), 95, "STOP_LOSS"},
+		{"long both nearest target", long, NearestToOpen, candle(108, 111, 94, 100), 110, "TAKE_PROFIT"},
+		{"long both default", long, "", candle(108, 111, 94, 100), 95, "STOP_LOSS"},
+		{"short both stop first", short, StopLossFirst, candle(97, 106, 89, 100), 105, "STOP_LOSS"},
+		{"short both take profit first", short, TakeProfitFirst, candle(103, 106, 89, 100), 90, "TAKE_PROFIT"},
+		{"short both nearest stop", short, NearestToOpen, candle(103, 106, 89, 100), 105, "STOP_LOSS"},
+		{"short both nearest target", short, NearestToOpen, candle(93, 106, 89, 100), 90, "TAKE_PROFIT"},
+
+		// แตะระดับเดียว: fill ที่ราคา trigger ไม่ว่ากติกาใด
+		{"long take profit only", long, StopLossFirst, candle(100, 112, 99, 111), 110, "TAKE_PROFIT"},
+		{"long stop only", long, TakeProfitFirst, candle(100, 101, 93, 94), 95, "STOP_LOSS"},
+		{"short take profit only", short, StopLossFirst, candle(100, 101, 88, 89), 90, "TAKE_PROFIT"},
+		{"short stop only", short, TakeProfitFirst, candle(100, 107, 99, 106), 105, "STOP_LOSS"},
+
+		// Gap: ราคาเปิดข้ามระดับไปแล้ว → fill ที่ราคาเปิด (แย่กว่า SL หรือดีกว่า TP)
+		{"long gap below stop", long, TakeProfitFirst, candle(92, 112, 90, 111), 92, "STOP_LOSS"},
+		{"long gap above target", long, StopLossFirst, candle(115, 116, 94, 96), 115, "TAKE_PROFIT"},
+		{"short gap above stop", short, TakeProfitFirst, candle(108, 109, 88, 89), 108, "STOP_LOSS"},
+		{"short gap below target", short, StopLossFirst, candle(85, 106, 84, 104), 85, "TAKE_PROFIT"},
+
+		// อยู่ระหว่าง SL และ TP ทั้งแท่ง
+		{"long inside", long, StopLossFirst, candle(100, 109, 96, 105), 0, ""},
+		{"short inside", short, StopLossFirst, candle(100, 104, 91, 95), 0, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bt := &Backtester{position: tc.pos, sameBarPriority: tc.priority}
+			price, reason, hit := bt.intrabarExit(tc.bar)
+			if hit != (tc.reason != "") || reason != tc.reason || price != tc.price {
+				t.Fatalf("ได้ %v %q (hit=%v), ต้องการ %v %q", price, reason, hit, tc.price, tc.reason)
+			}
+		})
+	}
+}
+
+func TestIntrabarFillClosesAtTrigger(t *testing.T) {
+	// เข้า SHORT ที่ 100 (SL 105, TP 90) แท่งถัดไปแตะ TP ระหว่างแท่งแต่ปิดที่ 99
+	data := hourly(candle(100, 100, 100, 100), candle(100, 101, 89, 99), candle(99, 99, 99, 99))
+	strategy := &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "SHORT", StopLoss: 105, TakeProfit: 90, Reason: "test"}},
+		quantity: 1,
+	}
+
+	result := runScripted(t, data, strategy, nil)
+	if len(result.Trades) != 1 || result.Trades[0].ExitReason != "TAKE_PROFIT" || result.Trades[0].ExitPrice != 90 {
+		t.Fatalf("เทรด %+v, ต้องการปิด TAKE_PROFIT ที่ 90", result.Trades)
+	}
+
+	// ปิด intrabar fills: ดูเฉพาะราคาปิดซึ่งไม่ถึง TP จึงถือจนข้อมูลหมด
+	result = runScripted(t, data, strategy, func(bt *Backtester) { bt.SetIntrabarFills(false) })
+	if len(result.Trades) != 1 || result.Trades[0].ExitReason != "END_OF_BACKTEST" || result.Trades[0].ExitPrice != 99 {
+		t.Fatalf("เทรด %+v, ต้องการปิด END_OF_BACKTEST ที่ 99", result.Trades)
+	}
+}
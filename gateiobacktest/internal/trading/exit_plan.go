@@ -0,0 +1,318 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+)
+
+// TakeProfitLevel ขั้นของ take-profit แบบทยอยปิด
+type TakeProfitLevel struct {
+	R       float64 `json:"r"`       // ระยะจากราคาเข้าเป็นจำนวนเท่าของความเสี่ยงเริ่มต้น (|ราคาเข้า - SL|)
+	Percent float64 `json:"percent"` // % ของขนาด position ตอนเปิดที่ปิดที่ขั้นนี้
+}
+
+// ExitPlan แผนออกแบบทยอยปิด (scale-out) ใช้ร่วมกันทั้ง backtester และ bot จริง
+// ส่วนที่เหลือหลังขั้นสุดท้ายออกที่ SL/TP ของ position โดย SL ถูกเลื่อนตาม TrailPercent (ถ้ากำหนด)
+type ExitPlan struct {
+	Levels             []TakeProfitLevel `json:"levels"`
+	BreakEvenAfter     int               `json:"break_even_after"`      // เลื่อน SL ไปจุดคุ้มทุนหลัง TP ขั้นที่เท่านี้ (0 = ไม่เลื่อน)
+	BreakEvenOffsetPct float64           `json:"break_even_offset_pct"` // ระยะเผื่อจากราคาเข้าฝั่งกำไร (%) เช่น ครอบค่าธรรมเนียม
+	TrailPercent       float64           `json:"trail_percent"`         // trail ส่วนที่เหลือหลัง TP ขั้นสุดท้ายห่างจากราคาดีที่สุด (%) (0 = ไม่ trail)
+}
+
+// DefaultExitPlan ปิด 33% ที่ 1R, 33% ที่ 2R, เลื่อน SL ไปจุดคุ้มทุนหลังขั้นแรก และ trail ส่วนที่เหลือ 1%
+func DefaultExitPlan() *ExitPlan {
+	return &ExitPlan{
+		Levels: []TakeProfitLevel{
+			{R: 1, Percent: 33},
+			{R: 2, Percent: 33},
+		},
+		BreakEvenAfter: 1,
+		TrailPercent:   1,
+	}
+}
+
+// Validate ตรวจว่าขั้น TP เรียงจากใกล้ไปไกล และสัดส่วนรวมไม่เกิน 100%
+func (p *ExitPlan) Validate() error {
+	var total, lastR float64
+	for i, level := range p.Levels {
+		if level.R <= lastR {
+			return fmt.Errorf("TP ขั้นที่ %d ต้องมี R มากกว่า 0 และมากกว่าขั้นก่อนหน้า", i+1)
+		}
+		if level.Percent <= 0 {
+			return fmt.Errorf("TP ขั้นที่ %d ต้องมีสัดส่วนมากกว่า 0%%", i+1)
+		}
+		lastR = level.R
+		total += level.Percent
+	}
+	if total > 100+1e-9 {
+		return fmt.Errorf("สัดส่วน TP รวม %.2f%% เกิน 100%%", total)
+	}
+	if p.BreakEvenAfter < 0 || p.BreakEvenAfter > len(p.Levels) {
+		return fmt.Errorf("BreakEvenAfter ต้องอยู่ระหว่าง 0 ถึง %d", len(p.Levels))
+	}
+	if p.BreakEvenOffsetPct < 0 || p.TrailPercent < 0 {
+		return fmt.Errorf("BreakEvenOffsetPct และ TrailPercent ต้องไม่ติดลบ")
+	}
+	return nil
+}
+
+// ExitAction การปิดบางส่วนหนึ่งขั้นตามแผน
+type ExitAction struct {
+	Level    int     // ขั้นที่ (เริ่มที่ 1)
+	Price    float64 // ราคา fill
+	Quantity float64
+	Reason   string
+}
+
+// ExitPlanState สถานะของแผนออกสำหรับ position หนึ่ง
+type ExitPlanState struct {
+	Plan       *ExitPlan
+	Side       string // "LONG" or "SHORT"
+	EntryPrice float64
+	Risk       float64 // ความเสี่ยงเริ่มต้นต่อหน่วย (|ราคาเข้า - SL|)
+	Quantity   float64 // ขนาด position ตอนเปิด
+	LevelsHit  int     // จำนวนขั้น TP ที่ปิดไปแล้ว
+	StopLoss   float64 // SL ปัจจุบันตามแผน (คุ้มทุน/trail)
+	BestPrice  float64 // ราคาที่ดีที่สุดตั้งแต่เปิด position
+}
+
+// NewExitPlanState เริ่มแผนออกของ position ใหม่ (SL ต้องอยู่ฝั่งขาดทุนของราคาเข้า)
+func NewExitPlanState(plan *ExitPlan, side string, entryPrice, stopLoss, quantity float64) (*ExitPlanState, error) {
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+
+	risk := entryPrice - stopLoss
+	if side == "SHORT" {
+		risk = -risk
+	}
+	if stopLoss <= 0 || risk <= 0 {
+		return nil, fmt.Errorf("SL %.4f ไม่อยู่ฝั่งขาดทุนของราคาเข้า %.4f (%s)", stopLoss, entryPrice, side)
+	}
+
+	return &ExitPlanState{
+		Plan:       plan,
+		Side:       side,
+		EntryPrice: entryPrice,
+		Risk:       risk,
+		Quantity:   quantity,
+		StopLoss:   stopLoss,
+		BestPrice:  entryPrice,
+	}, nil
+}
+
+// LevelPrice ราคาของ TP ขั้นที่ i (เริ่มที่ 0)
+func (s *ExitPlanState) LevelPrice(i int) float64 {
+	distance := s.Plan.Levels[i].R * s.Risk
+	if s.Side == "SHORT" {
+		return s.EntryPrice - distance
+	}
+	return s.EntryPrice + distance
+}
+
+// NextLevelPrice ราคาของ TP ขั้นถัดไป (false = ปิดครบทุกขั้นแล้ว)
+func (s *ExitPlanState) NextLevelPrice() (float64, bool) {
+	if s.LevelsHit >= len(s.Plan.Levels) {
+		return 0, false
+	}
+	return s.LevelPrice(s.LevelsHit), true
+}
+
+// TakeProfits ขั้น TP ที่ราคาแตะในช่วง open/high/low (gap ข้ามระดับ fill ที่ราคาเปิด)
+func (s *ExitPlanState) TakeProfits(open, high, low float64) []ExitAction {
+	long := s.Side == "LONG"
+
+	var actions []ExitAction
+	for s.LevelsHit < len(s.Plan.Levels) {
+		level := s.Plan.Levels[s.LevelsHit]
+		price := s.LevelPrice(s.LevelsHit)
+		if (long && high < price) || (!long && low > price) {
+			break
+		}
+		if (long && open > price) || (!long && open < price) {
+			price = open
+		}
+
+		s.LevelsHit++
+		actions = append(actions, ExitAction{
+			Level:    s.LevelsHit,
+			Price:    price,
+			Quantity: s.Quantity * level.Percent / 100,
+			Reason:   fmt.Sprintf("TP%d_%.1fR", s.LevelsHit, level.R),
+		})
+	}
+	return actions
+}
+
+// AdjustStop เลื่อน SL ตามแผน (คุ้มทุนหลังขั้นที่กำหนด และ trail หลังขั้นสุดท้าย) จากราคาสูงสุด/ต่ำสุดที่ผ่านมา
+// SL เลื่อนเข้าหากำไรเท่านั้น คืน SL ใหม่
+func (s *ExitPlanState) AdjustStop(high, low float64) float64 {
+	long := s.Side == "LONG"
+	if long {
+		s.BestPrice = math.Max(s.BestPrice, high)
+	} else {
+		s.BestPrice = math.Min(s.BestPrice, low)
+	}
+
+	tighten := func(stop float64) {
+		if (long && stop > s.StopLoss) || (!long && stop < s.StopLoss) {
+			s.StopLoss = stop
+		}
+	}
+
+	if s.Plan.BreakEvenAfter > 0 && s.LevelsHit >= s.Plan.BreakEvenAfter {
+		if long {
+			tighten(s.EntryPrice * (1 + s.Plan.BreakEvenOffsetPct/100))
+		} else {
+			tighten(s.EntryPrice * (1 - s.Plan.BreakEvenOffsetPct/100))
+		}
+	}
+
+	if s.Plan.TrailPercent > 0 && s.LevelsHit >= len(s.Plan.Levels) {
+		if long {
+			tighten(s.BestPrice * (1 - s.Plan.TrailPercent/100))
+		} else {
+			tighten(s.BestPrice * (1 + s.Plan.TrailPercent/100))
+		}
+	}
+
+	return s.StopLoss
+}
+
+// SetExitPlan ใช้แผนออกแบบทยอยปิดกับทุก position ที่เปิดหลังจากนี้ (nil = ปิดทั้งก้อนตามเดิม)
+func (bt *Backtester) SetExitPlan(plan *ExitPlan) error {
+	if plan != nil {
+		if err := plan.Validate(); err != nil {
+			return err
+		}
+	}
+	bt.exitPlan = plan
+	return nil
+}
+
+// startExitPlan เริ่มแผนออกของ position ที่เพิ่งเปิด
+func (bt *Backtester) startExitPlan() {
+	bt.exitState = nil
+	if bt.exitPlan == nil {
+		return
+	}
+
+	pos := bt.position
+	state, err := NewExitPlanState(bt.exitPlan, pos.Side, pos.EntryPrice, pos.StopLoss, pos.Quantity)
+	if err != nil {
+		bt.logf("⚠️ ไม่ใช้แผนออกกับ position นี้: %v\n", err)
+		return
+	}
+	bt.exitState = state
+}
+
+// scaleOut ปิดบางส่วนตามขั้น TP ที่แท่งปัจจุบันแตะ (ก่อนตรวจ SL/TP ของทั้ง position)
+// ถ้าแท่งเดียวกันแตะ SL ด้วย ใช้กติกา SameBarPriority ตัดสินว่าโดนอะไรก่อน
+func (bt *Backtester) scaleOut(candle OHLCV) {
+	state := bt.exitState
+	pos := bt.position
+	if state == nil || pos == nil {
+		return
+	}
+	next, ok := state.NextLevelPrice()
+	if !ok {
+		return
+	}
+
+	open, high, low := candle.Open, candle.High, candle.Low
+	if !bt.intrabarFills {
+		open, high, low = candle.Close, candle.Close, candle.Close
+	}
+
+	long := pos.Side == "LONG"
+	stopHit := pos.StopLoss > 0 && ((long && low <= pos.StopLoss) || (!long && high >= pos.StopLoss))
+	if stopHit && bt.stopFillsFirst(open, &BacktestPosition{StopLoss: pos.StopLoss, TakeProfit: next}) {
+		return
+	}
+
+	for _, action := range state.TakeProfits(open, high, low) {
+		if bt.position == nil {
+			return
+		}
+		bt.logf("🪜 %s: ปิด %.6f ที่ $%.2f\n", action.Reason, action.Quantity, action.Price)
+		bt.closeQuantityAt(action.Price, action.Quantity, action.Reason)
+	}
+}
+
+// trailExitPlan เลื่อน SL ของ position ตามแผน (มีผลตั้งแต่แท่งถัดไป)
+func (bt *Backtester) trailExitPlan(candle OHLCV) {
+	if bt.exitState == nil || bt.position == nil {
+		return
+	}
+
+	high, low := candle.High, candle.Low
+	if !bt.intrabarFills {
+		high, low = candle.Close, candle.Close
+	}
+
+	before := bt.position.StopLoss
+	bt.exitState.StopLoss = before
+	if stop := bt.exitState.AdjustStop(high, low); stop != before {
+		bt.position.StopLoss = stop
+		bt.logf("🛡️ เลื่อน SL ตามแผนออก: $%.2f → $%.2f\n", before, stop)
+	}
+}
+
+// PositionTrades รวมเทรดที่ทยอยปิดของ position เดียวกัน (Symbol, Side, EntryTime และ ExitLeg ต่อเนื่อง)
+// เป็นรายการเดียว สำหรับสถิติที่นับจำนวนเทรด เช่น win rate, streak และ Monte Carlo
+// (result.Trades ยังเก็บแยกทุกขาการปิด) รายการเรียงตามการปิดครั้งสุดท้ายของแต่ละ position
+func PositionTrades(trades []BacktestTrade) []BacktestTrade {
+	positions := make([]BacktestTrade, 0, len(trades))
+	open := make(map[string]int) // position ที่ยังทยอยปิดไม่ครบ → index ใน positions
+	var order []int              // index ใน positions ตามเวลาปิดครั้งสุดท้าย
+
+	for _, trade := range trades {
+		key := fmt.Sprintf("%s|%s|%d", trade.Symbol, trade.Side, trade.EntryTime.UnixNano())
+		i, ok := open[key]
+		if trade.ExitLeg <= 1 || !ok {
+			positions = append(positions, trade)
+			i = len(positions) - 1
+			positions[i].ExitLeg = 0
+			if trade.ExitLeg >= 1 {
+				open[key] = i
+			}
+			order = append(order, i)
+			continue
+		}
+
+		merged := &positions[i]
+		quantity := merged.Quantity + trade.Quantity
+		merged.ExitPrice = (merged.ExitPrice*merged.Quantity + trade.ExitPrice*trade.Quantity) / quantity
+		merged.Quantity = quantity
+		merged.PnL += trade.PnL
+		merged.PnLPct = merged.PnL / (merged.EntryPrice * quantity) * 100
+		merged.Commission += trade.Commission
+		merged.Funding += trade.Funding
+		merged.NetPnL += trade.NetPnL
+		merged.Margin += trade.Margin
+		merged.ExitTime = trade.ExitTime
+		merged.Duration = trade.Duration
+		merged.ExitReason = trade.ExitReason
+		merged.MAEPct = math.Max(merged.MAEPct, trade.MAEPct)
+		merged.MFEPct = math.Max(merged.MFEPct, trade.MFEPct)
+		if trade.Legs != nil {
+			merged.Legs = trade.Legs
+		}
+
+		// ย้าย position ไปตามเวลาปิดครั้งสุดท้าย
+		for k, idx := range order {
+			if idx == i {
+				order = append(order[:k], order[k+1:]...)
+				break
+			}
+		}
+		order = append(order, i)
+	}
+
+	out := make([]BacktestTrade, len(order))
+	for k, idx := range order {
+		out[k] = positions[idx]
+	}
+	return out
+}
@@ -0,0 +1,247 @@
+package trading
+
+import (
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+// hourly ใส่ timestamp ห่างกัน 1 ชั่วโมงให้แท่งเทียนที่เขียนเอง
+func hourly(candles ...OHLCV) []OHLCV {
+	for i := range candles {
+		candles[i].Timestamp = 1_700_000_000 + int64(i)*3600
+	}
+	return candles
+}
+
+// scriptedStrategy เข้าตามสัญญาณที่กำหนดไว้ล่วงหน้าต่อ index ของแท่ง และไม่มีสัญญาณออกเอง
+type scriptedStrategy struct {
+	entries  map[int]*EntrySignal
+	quantity float64
+	fees     FeeModel
+	exits    map[int]string
+}
+
+func (s *scriptedStrategy) Name() string                 { return "scripted" }
+func (s *scriptedStrategy) WarmupBars() int              { return 0 }
+func (s *scriptedStrategy) Fees(bt *Backtester) FeeModel { return s.fees }
+func (s *scriptedStrategy) OnBar(bt *Backtester)         {}
+
+func (s *scriptedStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	return s.exits[bt.currentIndex]
+}
+
+func (s *scriptedStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	return s.entries[bt.currentIndex]
+}
+
+func (s *scriptedStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return s.quantity
+}
+
+// newQuietBacktester backtester ทุน $1000 ที่ไม่เขียน log ระหว่างรัน
+func newQuietBacktester(tb testing.TB, symbol string, daysBack int) *Backtester {
+	tb.Helper()
+	bt, err := NewBacktesterSimple(symbol, daysBack, 1000)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	bt.SetLogOutput(io.Discard)
+	return bt
+}
+
+// runScripted รัน backtest บนแท่งเทียนที่เขียนเอง
+func runScripted(t *testing.T, data []OHLCV, strategy Strategy, setup func(bt *Backtester)) *BacktestResult {
+	t.Helper()
+	bt := newQuietBacktester(t, "TEST_USDT", 0)
+	bt.LoadHistoricalData(data)
+	if setup != nil {
+		setup(bt)
+	}
+	result, err := bt.RunStrategy(strategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestExitPlanLadderBreakEvenAndTrail(t *testing.T) {
+	// เข้า LONG 100 (SL 98 → 1R = 2): TP1 102, TP2 104, เลื่อน SL ไปคุ้มทุนหลัง TP1 และ trail 1% หลัง TP2
+	data := hourly(
+		candle(100, 100, 100, 100),
+		candle(100, 102.5, 99.5, 102), // TP1 ที่ 102 → SL คุ้มทุน 100
+		candle(102, 104.5, 101, 104),  // TP2 ที่ 104 → trail จาก 104.5 = 103.455
+		candle(104, 104.2, 103, 103),  // ส่วนที่เหลือโดน trailing stop 103.455
+		candle(103, 103, 103, 103),
+	)
+	strategy := &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "LONG", StopLoss: 98, Reason: "test"}},
+		quantity: 3,
+	}
+	result := runScripted(t, data, strategy, func(bt *Backtester) {
+		if err := bt.SetExitPlan(DefaultExitPlan()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	want := []struct {
+		price, quantity float64
+		reason          string
+		leg             int
+	}{
+		{102, 0.99, "TP1_1.0R", 1},
+		{104, 0.99, "TP2_2.0R", 2},
+		{104.5 * 0.99, 1.02, "STOP_LOSS", 3},
+	}
+	if len(result.Trades) != len(want) {
+		t.Fatalf("ได้ %d ขาการปิด, ต้องการ %d: %+v", len(result.Trades), len(want), result.Trades)
+	}
+	for i, w := range want {
+		trade := result.Trades[i]
+		if !approx(trade.ExitPrice, w.price) || !approx(trade.Quantity, w.quantity) || trade.ExitReason != w.reason || trade.ExitLeg != w.leg {
+			t.Errorf("ขา %d: ราคา %v ปริมาณ %v %s leg %d, ต้องการ %v %v %s leg %d",
+				i+1, trade.ExitPrice, trade.Quantity, trade.ExitReason, trade.ExitLeg, w.price, w.quantity, w.reason, w.leg)
+		}
+	}
+
+	// สถิติที่นับจำนวนเทรดนับต่อ position
+	if result.TotalTrades != 1 || result.WinningTrades != 1 || result.WinRate != 100 {
+		t.Fatalf("trades %d ชนะ %d win rate %v, ต้องการ 1 position ที่ชนะ", result.TotalTrades, result.WinningTrades, result.WinRate)
+	}
+	netPnL := 0.0
+	for _, trade := range result.Trades {
+		netPnL += trade.NetPnL
+	}
+	if !approx(result.Metrics.Expectancy, netPnL) || result.Metrics.MaxConsecutiveWins != 1 {
+		t.Fatalf("expectancy %v streak %d, ต้องการ %v และ 1", result.Metrics.Expectancy, result.Metrics.MaxConsecutiveWins, netPnL)
+	}
+}
+
+func TestExitPlanBreakEvenStop(t *testing.T) {
+	// หลัง TP1 ราคากลับมาที่ราคาเข้า: ส่วนที่เหลือออกที่คุ้มทุน ไม่ใช่ SL เดิม 98
+	data := hourly(
+		candle(100, 100, 100, 100),
+		candle(100, 102.5, 100.5, 102),
+		candle(101, 101, 97, 97),
+		candle(97, 97, 97, 97),
+	)
+	strategy := &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "LONG", StopLoss: 98, Reason: "test"}},
+		quantity: 3,
+	}
+	result := runScripted(t, data, strategy, func(bt *Backtester) {
+		if err := bt.SetExitPlan(DefaultExitPlan()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if len(result.Trades) != 2 {
+		t.Fatalf("ได้ %d ขาการปิด, ต้องการ 2: %+v", len(result.Trades), result.Trades)
+	}
+	if rest := result.Trades[1]; rest.ExitPrice != 100 || rest.ExitReason != "STOP_LOSS" {
+		t.Fatalf("ส่วนที่เหลือออกที่ %v (%s), ต้องการ 100 คุ้มทุน", rest.ExitPrice, rest.ExitReason)
+	}
+	if result.TotalTrades != 1 {
+		t.Fatalf("TotalTrades %d, ต้องการ 1", result.TotalTrades)
+	}
+}
+
+func TestPositionTradesGroupsLegsPerPosition(t *testing.T) {
+	entryA, entryB := time.Unix(1_700_000_000, 0), time.Unix(1_700_003_600, 0)
+	legs := []BacktestTrade{
+		{Symbol: "A", Side: "LONG", EntryTime: entryA, EntryPrice: 100, ExitLeg: 1, Quantity: 1, ExitPrice: 110, PnL: 10, NetPnL: 9},
+		// B ปิดทั้งก้อนระหว่างขาของ A (portfolio เรียงเทรดตามเวลาปิด)
+		{Symbol: "B", Side: "LONG", EntryTime: entryB, EntryPrice: 100, Quantity: 2, ExitPrice: 90, PnL: -20, NetPnL: -21},
+		{Symbol: "A", Side: "LONG", EntryTime: entryA, EntryPrice: 100, ExitLeg: 2, Quantity: 3, ExitPrice: 96, PnL: -12, NetPnL: -13, ExitReason: "Stop Loss Hit"},
+		// position ใหม่ของ A ที่ entry เดียวกันไม่ได้ (ExitLeg เริ่มที่ 1 ใหม่) ต้องแยกจากก้อนก่อน
+		{Symbol: "A", Side: "LONG", EntryTime: entryA, EntryPrice: 100, ExitLeg: 1, Quantity: 1, ExitPrice: 101, PnL: 1, NetPnL: 1},
+	}
+
+	positions := PositionTrades(legs)
+	if len(positions) != 3 {
+		t.Fatalf("ได้ %d positions, ต้องการ 3", len(positions))
+	}
+	if positions[0].Symbol != "B" {
+		t.Fatalf("position ต้องเรียงตามการปิดครั้งสุดท้าย (B ก่อน A)")
+	}
+	merged := positions[1]
+	if merged.Quantity != 4 || merged.NetPnL != -4 || merged.PnL != -2 || merged.ExitLeg != 0 ||
+		!approx(merged.ExitPrice, (110+96*3)/4.0) || merged.ExitReason != "Stop Loss Hit" {
+		t.Fatalf("position A ที่รวมแล้วไม่ถูกต้อง: %+v", merged)
+	}
+}
+
+func TestExitPlanStateGapFillsAtOpen(t *testing.T) {
+	state, err := NewExitPlanState(DefaultExitPlan(), "SHORT", 100, 102, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// gap ลงข้ามทั้งสองขั้น (98, 96) → fill ที่ราคาเปิด 95
+	actions := state.TakeProfits(95, 95.5, 94)
+	if len(actions) != 2 || actions[0].Price != 95 || actions[1].Price != 95 || !approx(actions[0].Quantity, 3.3) {
+		t.Fatalf("actions %+v", actions)
+	}
+	if stop := state.AdjustStop(95.5, 94); !approx(stop, 94*1.01) {
+		t.Fatalf("trailing SL %v, ต้องการ %v", stop, 94*1.01)
+	}
+	// SL ไม่ถอยกลับเมื่อราคาวิ่งสวน
+	if stop := state.AdjustStop(99, 97); !approx(stop, 94*1.01) {
+		t.Fatalf("SL ถอยกลับเป็น %v", stop)
+	}
+}
+
+// fakeExits ตัวจำลอง exchange สำหรับแผนออกของบอท
+type fakeExits struct {
+	failReduce int // จำนวนครั้งแรกที่ ReducePosition ล้มเหลว
+	reduced    []int64
+	stops      []float64
+}
+
+func (f *fakeExits) ReducePosition(contract string, size int64) (bool, error) {
+	if f.failReduce > 0 {
+		f.failReduce--
+		return false, errors.New("exchange error")
+	}
+	f.reduced = append(f.reduced, size)
+	return true, nil
+}
+
+func (f *fakeExits) CheckStopLoss(contract string, stopPrice float64, isLong bool) (bool, error) {
+	f.stops = append(f.stops, stopPrice)
+	return false, nil
+}
+
+func TestManageExitPlanRetriesFailedReduce(t *testing.T) {
+	exits := &fakeExits{failReduce: 1}
+	bot := &TradingBot{exits: exits}
+	if err := bot.SetExitPlan(DefaultExitPlan(), 2); err != nil {
+		t.Fatal(err)
+	}
+	position := &Position{Contract: "SOL_USDT", Size: 100, EntryPrice: 100, MarkPrice: 104.5}
+
+	// รอบแรก: ปิด TP1 ไม่สำเร็จ → ยังไม่นับขั้นใด และ SL ยังไม่เลื่อน
+	if bot.manageExitPlan(position) {
+		t.Fatal("position ยังไม่ถูกปิดหมด")
+	}
+	state := bot.exitStates["SOL_USDT"]
+	if state.LevelsHit != 0 || len(exits.reduced) != 0 || exits.stops[0] != 98 {
+		t.Fatalf("หลังล้มเหลว: LevelsHit %d reduced %v SL %v", state.LevelsHit, exits.reduced, exits.stops)
+	}
+
+	// รอบถัดไป: ลองใหม่และปิดทั้งสองขั้น แล้ว trail ส่วนที่เหลือ 1% จาก 104.5
+	if bot.manageExitPlan(position) {
+		t.Fatal("position ยังไม่ถูกปิดหมด")
+	}
+	if state.LevelsHit != 2 || len(exits.reduced) != 2 || exits.reduced[0] != 33 || exits.reduced[1] != 33 {
+		t.Fatalf("หลังลองใหม่: LevelsHit %d reduced %v", state.LevelsHit, exits.reduced)
+	}
+	if stop := exits.stops[1]; !approx(stop, 104.5*0.99) {
+		t.Fatalf("SL %v, ต้องการ trail %v", stop, 104.5*0.99)
+	}
+}
@@ -0,0 +1,197 @@
+package trading
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultFundingInterval รอบการเก็บ funding ของ USDT perpetual (Gate.io/Binance)
+const DefaultFundingInterval = 8 * time.Hour
+
+// FundingRate อัตรา funding ณ เวลาหนึ่ง
+type FundingRate struct {
+	Timestamp int64   `json:"timestamp"` // unix วินาที
+	Rate      float64 `json:"rate"`      // 0.0001 = 0.01% ต่อรอบ
+}
+
+// FundingModel แหล่งอัตรา funding สำหรับ backtest
+//
+// ถ้ามี Series จะใช้เวลาและอัตราตามข้อมูลย้อนหลัง ไม่เช่นนั้นเก็บทุก Interval
+// (ตรงกับ 00:00/08:00/16:00 UTC) ด้วยอัตราของเหรียญใน SymbolRates หรือ ConstantRate
+type FundingModel struct {
+	Interval     time.Duration      `json:"interval"`
+	ConstantRate float64            `json:"constant_rate"`
+	SymbolRates  map[string]float64 `json:"symbol_rates"`
+	Series       []FundingRate      `json:"series"`
+	Source       string             `json:"source,omitempty"` // ไฟล์ที่โหลด Series มา (ว่าง = กำหนดในโค้ด)
+}
+
+// FundingConfig การตั้งค่า funding ใน config (risk.funding) ถ้าระบุ file จะใช้อัตราย้อนหลังแทน rate
+type FundingConfig struct {
+	Rate        float64            `json:"rate" yaml:"rate"`                                     // อัตราคงที่ต่อรอบ (0.0001 = 0.01%)
+	SymbolRates map[string]float64 `json:"symbol_rates,omitempty" yaml:"symbol_rates,omitempty"` // อัตราเฉพาะเหรียญแทน rate
+	Interval    string             `json:"interval,omitempty" yaml:"interval,omitempty"`         // รอบการเก็บ เช่น 8h (ว่าง = 8h)
+	File        string             `json:"file,omitempty" yaml:"file,omitempty"`                 // ไฟล์ของ LoadFundingRates ({symbol} = ชื่อเหรียญ)
+}
+
+// Validate ตรวจสอบว่า config ของ funding ใช้งานได้
+func (c FundingConfig) Validate() error {
+	if c.Interval != "" {
+		if _, err := ParseInterval(c.Interval); err != nil {
+			return fmt.Errorf("funding interval ไม่ถูกต้อง: %v", err)
+		}
+	}
+	for symbol, rate := range c.SymbolRates {
+		if math.Abs(rate) >= 1 {
+			return fmt.Errorf("funding rate ของ %s ต้องอยู่ระหว่าง -1 ถึง 1", symbol)
+		}
+	}
+	if math.Abs(c.Rate) >= 1 {
+		return fmt.Errorf("funding rate ต้องอยู่ระหว่าง -1 ถึง 1")
+	}
+	return nil
+}
+
+// Model สร้าง FundingModel ของ symbol ตาม config
+func (c FundingConfig) Model(symbol string) (*FundingModel, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var model *FundingModel
+	if c.File != "" {
+		filename := strings.ReplaceAll(c.File, "{symbol}", symbol)
+		series, err := LoadFundingRates(filename)
+		if err != nil {
+			return nil, err
+		}
+		model = NewSeriesFundingModel(series)
+		model.Source = filename
+	} else {
+		model = NewConstantFundingModel(c.Rate)
+		for s, rate := range c.SymbolRates {
+			model.SymbolRates[s] = rate
+		}
+	}
+	if c.Interval != "" {
+		model.Interval, _ = ParseInterval(c.Interval)
+	}
+	return model, nil
+}
+
+// config การตั้งค่าที่สร้างโมเดลนี้ได้ สำหรับบันทึกใน config ของผลลัพธ์
+// (Series ที่ไม่ได้โหลดจากไฟล์บันทึกได้เพียงว่ามีการคิด funding)
+func (m *FundingModel) config() *FundingConfig {
+	config := &FundingConfig{File: m.Source}
+	if m.Interval != DefaultFundingInterval && m.Interval > 0 {
+		config.Interval = formatInterval(m.Interval)
+	}
+	if len(m.Series) == 0 {
+		config.Rate = m.ConstantRate
+		if len(m.SymbolRates) > 0 {
+			config.SymbolRates = m.SymbolRates
+		}
+	}
+	return config
+}
+
+// NewConstantFundingModel โมเดล funding อัตราคงที่ทุก 8 ชั่วโมง
+func NewConstantFundingModel(rate float64) *FundingModel {
+	return &FundingModel{
+		Interval:     DefaultFundingInterval,
+		ConstantRate: rate,
+		SymbolRates:  make(map[string]float64),
+	}
+}
+
+// LoadFundingRates โหลดอัตรา funding ย้อนหลังจากไฟล์ JSON ([{"timestamp":..., "rate":...}])
+func LoadFundingRates(filename string) ([]FundingRate, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถอ่านไฟล์ funding rate ได้: %v", err)
+	}
+
+	var rates []FundingRate
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("ไม่สามารถแปลงไฟล์ funding rate ได้: %v", err)
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Timestamp < rates[j].Timestamp
+	})
+	return rates, nil
+}
+
+// NewSeriesFundingModel โมเดล funding จากข้อมูลย้อนหลัง
+func NewSeriesFundingModel(series []FundingRate) *FundingModel {
+	return &FundingModel{
+		Interval: DefaultFundingInterval,
+		Series:   series,
+	}
+}
+
+// SetFundingModel เปิดการคิด funding ให้ position ที่ถือข้ามรอบ funding (nil = ปิด)
+func (bt *Backtester) SetFundingModel(model *FundingModel) {
+	bt.funding = model
+}
+
+// events รายการ funding ที่เกิดในช่วงเวลา (from, to]
+func (m *FundingModel) events(symbol string, from, to int64) []FundingRate {
+	if len(m.Series) > 0 {
+		start := sort.Search(len(m.Series), func(i int) bool {
+			return m.Series[i].Timestamp > from
+		})
+		end := start
+		for end < len(m.Series) && m.Series[end].Timestamp <= to {
+			end++
+		}
+		return m.Series[start:end]
+	}
+
+	interval := int64(m.Interval / time.Second)
+	if interval <= 0 {
+		interval = int64(DefaultFundingInterval / time.Second)
+	}
+
+	rate := m.ConstantRate
+	if symbolRate, ok := m.SymbolRates[symbol]; ok {
+		rate = symbolRate
+	}
+
+	var events []FundingRate
+	for ts := (from/interval + 1) * interval; ts <= to; ts += interval {
+		events = append(events, FundingRate{Timestamp: ts, Rate: rate})
+	}
+	return events
+}
+
+// settleFunding เก็บ/จ่าย funding ของ position ที่ถือข้ามรอบ funding ระหว่างแท่งก่อนหน้ากับแท่งปัจจุบัน
+// Rate บวก: LONG จ่าย SHORT รับ, Rate ลบ: กลับกัน (คิดจาก notional ที่ราคาเปิดแท่ง)
+func (bt *Backtester) settleFunding(candle OHLCV) {
+	if bt.funding == nil || bt.position == nil || bt.currentIndex == 0 {
+		return
+	}
+
+	prev := bt.ohlcvData[bt.currentIndex-1].Timestamp
+	for _, event := range bt.funding.events(bt.symbol, prev, candle.Timestamp) {
+		payment := candle.Open * bt.position.Quantity * event.Rate
+		if bt.position.Side == "SHORT" {
+			payment = -payment
+		}
+
+		bt.position.Funding += payment
+		bt.currentCapital -= payment
+
+		// isolated: funding หัก/เพิ่มจาก margin ของ position ราคา liquidation จึงเลื่อนตาม
+		// (cross คำนวณใหม่จากเงินทุนทุกแท่งใน checkLiquidation อยู่แล้ว)
+		if bt.futures != nil && bt.position.MarginMode == IsolatedMargin {
+			bt.position.Margin = math.Max(0, bt.position.Margin-payment)
+			bt.position.LiquidationPrice = bt.liquidationPrice(bt.position)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package trading
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fundingCandles แท่งราคาคงที่ 100 เริ่มเที่ยงคืน UTC ห่างกัน step (payment ต่อรอบ = 100 x quantity x rate)
+func fundingCandles(n int, step time.Duration) []OHLCV {
+	const start = 1_699_920_000 // 2023-11-14 00:00 UTC
+	data := make([]OHLCV, n)
+	for i := range data {
+		data[i] = OHLCV{Timestamp: start + int64(i)*int64(step/time.Second), Open: 100, High: 100, Low: 100, Close: 100, Volume: 1000}
+	}
+	return data
+}
+
+func TestFundingSettlesOncePerBoundary(t *testing.T) {
+	const rate = 0.0001
+	for _, tc := range []struct {
+		interval string
+		bars     int
+	}{
+		{"15m", 4 * 24 * 3},
+		{"1h", 24 * 3},
+		{"4h", 6 * 3},
+		{"1d", 3}, // แท่งเดียวข้าม 3 รอบ
+	} {
+		t.Run(tc.interval, func(t *testing.T) {
+			step, _ := ParseInterval(tc.interval)
+			data := fundingCandles(tc.bars, step)
+			result := runScripted(t, data, &holdStrategy{sizePct: 1, hold: holdForever}, func(bt *Backtester) {
+				bt.SetFundingModel(NewConstantFundingModel(rate))
+			})
+			if len(result.Trades) != 1 {
+				t.Fatalf("ได้ %d เทรด, ต้องการ 1", len(result.Trades))
+			}
+			trade := result.Trades[0]
+
+			// รอบ 00:00/08:00/16:00 UTC หลังแท่งที่เข้าจนถึงแท่งสุดท้าย นับรอบละครั้ง
+			from, to := trade.EntryTime.Unix(), data[len(data)-1].Timestamp
+			boundaries := 0
+			for ts := data[0].Timestamp; ts <= to; ts += 8 * 3600 {
+				if ts > from {
+					boundaries++
+				}
+			}
+			want := float64(boundaries) * 100 * trade.Quantity * rate
+			if boundaries == 0 || !approx(trade.Funding, want) || !approx(result.TotalFunding, want) {
+				t.Fatalf("funding %v (รวม %v), ต้องการ %d รอบ = %v", trade.Funding, result.TotalFunding, boundaries, want)
+			}
+			if !approx(result.FinalCapital, 1000-want) {
+				t.Fatalf("เงินทุนสุดท้าย %v, ต้องการ %v", result.FinalCapital, 1000-want)
+			}
+		})
+	}
+}
+
+func TestFundingSplitOnPartialClose(t *testing.T) {
+	// LONG 2 @ 100 ที่แท่ง 00:00, จ่าย 0.02 ที่ 08:00, ปิดครึ่งที่ 10:00, จ่าย 0.01 ที่ 16:00, ปิดส่วนที่เหลือตอนจบข้อมูล
+	data := fundingCandles(20, time.Hour)
+	strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+		0: func(bt *Backtester) {
+			mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: MarketOrder, Quantity: 2})
+		},
+		10: func(bt *Backtester) {
+			mustPlace(t, bt, OrderRequest{Side: SellOrder, Type: MarketOrder, Quantity: 1, ReduceOnly: true})
+		},
+	}}
+	result := runScripted(t, data, strategy, func(bt *Backtester) {
+		bt.SetFundingModel(NewConstantFundingModel(0.0001))
+	})
+
+	if len(result.Trades) != 2 {
+		t.Fatalf("ได้ %d เทรด, ต้องการ 2", len(result.Trades))
+	}
+	// ส่วนที่ปิดก่อนรับ funding ไปครึ่งหนึ่งของที่จ่ายก่อนปิด ส่วนที่เหลือรับทั้งส่วนที่ค้างและรอบ 16:00
+	for i, want := range []float64{0.01, 0.02} {
+		if trade := result.Trades[i]; !approx(trade.Funding, want) || !approx(trade.NetPnL, -want) {
+			t.Fatalf("เทรด %d: funding %v net %v, ต้องการ funding %v", i+1, trade.Funding, trade.NetPnL, want)
+		}
+	}
+	if !approx(result.TotalFunding, 0.03) || !approx(result.FinalCapital, 1000-0.03) {
+		t.Fatalf("funding รวม %v เงินทุนสุดท้าย %v, ต้องการ 0.03 และ %v", result.TotalFunding, result.FinalCapital, 1000-0.03)
+	}
+}
+
+func TestFundingFromConfig(t *testing.T) {
+	data := fundingCandles(24*3, time.Hour)
+	series := []FundingRate{
+		{Timestamp: data[8].Timestamp, Rate: 0.0002},
+		{Timestamp: data[16].Timestamp, Rate: -0.0001},
+		{Timestamp: data[24].Timestamp, Rate: 0.0003},
+	}
+	raw, err := json.Marshal(series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "funding_TEST_USDT.json"), raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		funding *FundingConfig
+		rates   float64 // ผลรวมอัตราทุกรอบที่ถือข้าม
+	}{
+		{"rate", &FundingConfig{Rate: 0.0001}, 8 * 0.0001},
+		{"symbol_rates", &FundingConfig{Rate: 0.0001, SymbolRates: map[string]float64{"TEST_USDT": -0.0002}}, 8 * -0.0002},
+		{"interval", &FundingConfig{Rate: 0.0001, Interval: "4h"}, 17 * 0.0001},
+		{"file", &FundingConfig{File: filepath.Join(dir, "funding_{symbol}.json")}, 0.0002 - 0.0001 + 0.0003},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultStrategyConfig()
+			config.Strategy = "triple-ema-1h"
+			config.Symbols = []string{"TEST_USDT"}
+			config.Timeframe = ""
+			config.Risk.Funding = tc.funding
+
+			bt := newQuietBacktester(t, "TEST_USDT", 0)
+			bt.LoadHistoricalData(data)
+			if err := bt.ApplyConfig(config); err != nil {
+				t.Fatal(err)
+			}
+			result, err := bt.RunStrategy(&holdStrategy{sizePct: 1, hold: holdForever})
+			if err != nil {
+				t.Fatal(err)
+			}
+			trade := result.Trades[0]
+			if want := 100 * trade.Quantity * tc.rates; !approx(result.TotalFunding, want) {
+				t.Fatalf("funding รวม %v, ต้องการ %v", result.TotalFunding, want)
+			}
+
+			// config ที่แนบกับผลลัพธ์บันทึก funding ที่ใช้ (ไฟล์บันทึกเป็นชื่อของเหรียญนี้)
+			recorded := result.Config.Risk.Funding
+			want := *tc.funding
+			want.File = filepath.Join(dir, "funding_TEST_USDT.json")
+			if tc.funding.File == "" {
+				want.File = ""
+			} else {
+				want.Rate = 0
+			}
+			if recorded == nil || recorded.Rate != want.Rate || recorded.Interval != want.Interval || recorded.File != want.File ||
+				len(recorded.SymbolRates) != len(want.SymbolRates) {
+				t.Fatalf("result.Config.Risk.Funding %+v, ต้องการ %+v", recorded, want)
+			}
+		})
+	}
+
+	config := DefaultStrategyConfig()
+	config.Risk.Funding = &FundingConfig{Rate: 0.0001, Interval: "8x"}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Validate ต้องไม่ผ่านเมื่อ funding interval ไม่ถูกต้อง")
+	}
+}
+
+func TestFundingMovesIsolatedLiquidation(t *testing.T) {
+	// isolated 5x: position 40 ที่ 100 (notional 4000) margin 800, funding 5% ก่อนแท่งที่สอง = 200
+	// LONG จ่าย → margin 600 liquidation ≈ 85.43 (เดิม ≈ 80.40) ซึ่งแท่งที่สองแตะ
+	// SHORT รับ → margin 1000 liquidation ≈ 124.38 (เดิม ≈ 119.40) ซึ่งแท่งที่สองไม่แตะ
+	for _, tc := range []struct {
+		side   string
+		bar    OHLCV
+		margin float64
+		liq    float64
+		reason string
+	}{
+		{"LONG", candle(100, 100.5, 85, 90), 600, (4000 - 600) / (40 * 0.995), LiquidationExit},
+		{"SHORT", candle(100, 120, 100, 110), 1000, (1000 + 4000) / (40 * 1.005), "END_OF_BACKTEST"},
+	} {
+		t.Run(tc.side, func(t *testing.T) {
+			data := hourly(candle(100, 100, 100, 100), candle(100, 100.5, 99.5, 100), tc.bar, candle(100, 100, 100, 100))
+			strategy := &scriptedStrategy{
+				entries:  map[int]*EntrySignal{0: {Side: tc.side, Reason: "test"}},
+				quantity: 40,
+			}
+			result := runScripted(t, data, strategy, func(bt *Backtester) {
+				bt.SetFuturesAccount(FuturesAccount{MarginMode: IsolatedMargin, Leverage: 5, MaintenanceMarginRate: 0.005})
+				bt.SetFundingModel(NewSeriesFundingModel([]FundingRate{{Timestamp: data[1].Timestamp, Rate: 0.05}}))
+			})
+
+			trade := result.Trades[0]
+			if !approx(trade.Margin, tc.margin) || !approx(trade.LiquidationPrice, tc.liq) || trade.ExitReason != tc.reason {
+				t.Fatalf("margin %v liquidation %v ออก %s, ต้องการ %v %v %s",
+					trade.Margin, trade.LiquidationPrice, trade.ExitReason, tc.margin, tc.liq, tc.reason)
+			}
+			if tc.reason == LiquidationExit && !approx(trade.ExitPrice, tc.liq) {
+				t.Fatalf("liquidate ที่ %v, ต้องการ %v", trade.ExitPrice, tc.liq)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/antihax/optional"
 	"github.com/gateio/gateapi-go/v5"
+
+	"gateio-trading-bot/internal/indicators"
 )
 
 // GateClient wrapper สำหรับ Gate.io API
@@ -74,15 +76,35 @@ func (gc *GateClient) GetFuturesContracts() ([]string, error) {
 	return usdtContracts, nil
 }
 
-// GetOHLCV ดึงข้อมูล OHLCV (ใช้ 1h timeframe เสมอ)
+// gateCandleIntervals interval ที่ Gate.io futures candlesticks API รองรับ
+// (ParseInterval รับรูปแบบทั่วไปเช่น 2h หรือ 1w ซึ่ง Gate.io ไม่รองรับ)
+var gateCandleIntervals = []string{"10s", "1m", "5m", "15m", "30m", "1h", "4h", "8h", "1d", "7d"}
+
+// ValidateGateInterval ตรวจว่า interval เป็นค่าที่ Gate.io futures candlesticks API รองรับ
+func ValidateGateInterval(interval string) error {
+	for _, supported := range gateCandleIntervals {
+		if interval == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("interval %q ไม่รองรับบน Gate.io (รองรับ %s)", interval, strings.Join(gateCandleIntervals, ", "))
+}
+
+// GetOHLCV ดึงข้อมูล OHLCV ตาม interval แบบ Gate.io (ว่าง = 1h)
 func (gc *GateClient) GetOHLCV(contract, interval string, limit int) ([]OHLCV, error) {
+	if interval == "" {
+		interval = "1h"
+	}
+	if err := ValidateGateInterval(interval); err != nil {
+		return nil, err
+	}
+
 	futuresApi := gc.client.FuturesApi
 
-	fmt.Printf("📊 ดึงข้อมูล OHLCV %s (1h timeframe, %d candles)\n", contract, limit)
+	fmt.Printf("📊 ดึงข้อมูล OHLCV %s (%s timeframe, %d candles)\n", contract, interval, limit)
 
-	// บังคับใช้ 1h timeframe เสมอ (ignore interval parameter)
 	candles, _, err := futuresApi.ListFuturesCandlesticks(gc.ctx, "usdt", contract, &gateapi.ListFuturesCandlesticksOpts{
-		Interval: optional.NewString("1h"),             // บังคับ 1h สำหรับการวิเคราะห์ทั้งหมด
+		Interval: optional.NewString(interval),
 		Limit:    optional.NewInt32(int32(limit + 20)), // ขอข้อมูลเยอะหน่อยเผื่อตัด
 	})
 	if err != nil {
@@ -430,6 +452,45 @@ func (gc *GateClient) ClosePosition(contract string) (bool, error) {
 	return createdOrder.Status == "finished", nil
 }
 
+// ReducePosition ปิด position บางส่วนด้วย market order แบบ reduce-only (size = จำนวน contract ที่ปิด)
+func (gc *GateClient) ReducePosition(contract string, size int64) (bool, error) {
+	futuresApi := gc.client.FuturesApi
+
+	// ดึงข้อมูล position ปัจจุบัน
+	position, _, err := futuresApi.GetPosition(gc.ctx, "usdt", contract)
+	if err != nil {
+		return false, err
+	}
+
+	if position.Size == 0 || size <= 0 {
+		return true, nil // ไม่มีอะไรต้องปิด
+	}
+
+	// ปิดไม่เกินขนาด position (ฝั่งตรงข้าม)
+	if size > position.Size && size > -position.Size {
+		return gc.ClosePosition(contract)
+	}
+	if position.Size > 0 {
+		size = -size
+	}
+
+	reduceOrder := gateapi.FuturesOrder{
+		Contract:   contract,
+		Size:       size,
+		Price:      "0",   // market order
+		Tif:        "ioc", // immediate or cancel
+		Text:       "t-bot-scale-out",
+		ReduceOnly: true,
+	}
+
+	createdOrder, _, err := futuresApi.CreateFuturesOrder(gc.ctx, "usdt", reduceOrder)
+	if err != nil {
+		return false, err
+	}
+
+	return createdOrder.Status == "finished", nil
+}
+
 // SetLeverageAndMarginMode ตั้งค่า leverage และ margin mode สำหรับ contract
 func (gc *GateClient) SetLeverageAndMarginMode(contract string, leverage float64) error {
 	futuresApi := gc.client.FuturesApi
@@ -490,21 +551,9 @@ func (gc *GateClient) SetLeverageAndMarginMode(contract string, leverage float64
 	return nil
 }
 
-// CalculateEMA คำนวณ Exponential Moving Average
+// CalculateEMA คำนวณ Exponential Moving Average ของแท่งล่าสุด (ข้อมูลไม่พอ = 0)
 func (gc *GateClient) CalculateEMA(ohlcv []OHLCV, period int) float64 {
-	if len(ohlcv) < period {
-		return 0
-	}
-
-	// คำนวณ EMA โดยใช้ Close price
-	k := 2.0 / float64(period+1) // multiplier
-	ema := ohlcv[0].Close        // เริ่มต้นด้วยราคาปิดแรก
-
-	for i := 1; i < len(ohlcv); i++ {
-		ema = ohlcv[i].Close*k + ema*(1-k)
-	}
-
-	return ema
+	return lastOr(indicators.EMA(closePrices(ohlcv), period), 0)
 }
 
 // CreateStopLossOrder สร้าง stop loss order แบบ limit order ที่ราคา 5%
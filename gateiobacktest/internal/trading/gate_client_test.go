@@ -0,0 +1,22 @@
+package trading
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetOHLCVRejectsUnsupportedIntervals(t *testing.T) {
+	for _, interval := range gateCandleIntervals {
+		if err := ValidateGateInterval(interval); err != nil {
+			t.Errorf("%s: %v", interval, err)
+		}
+	}
+
+	// 2h, 1w และ 3m แปลงได้ด้วย ParseInterval แต่ Gate.io ไม่มีแท่งเทียนขนาดนี้: ต้องคืน error ก่อนเรียก API
+	gc := &GateClient{}
+	for _, interval := range []string{"2h", "1w", "3m", "1H", "1x"} {
+		if _, err := gc.GetOHLCV("SOL_USDT", interval, 10); err == nil || !strings.Contains(err.Error(), "ไม่รองรับบน Gate.io") {
+			t.Errorf("%s: %v, ต้องการ error interval ไม่รองรับ", interval, err)
+		}
+	}
+}
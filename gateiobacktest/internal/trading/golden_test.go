@@ -0,0 +1,191 @@
+package trading
+
+import (
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "เขียน testdata/golden ใหม่จากผลปัจจุบัน")
+
+const (
+	// goldenData ข้อมูลราคาที่มากับ repo (ใช้ goldenBars แท่งแรก)
+	goldenData = "../../data_SOL_USDT_15m_365d.json"
+	goldenBars = 3000
+
+	// goldenFile เทรดของ runner ทั้งสี่ตัวตามพฤติกรรมปัจจุบัน (รวมการเปลี่ยนแปลงที่ตั้งใจหลัง refactor
+	// เช่น fill SL/TP ระหว่างแท่ง และนิยาม Pivot SuperTrend ใหม่) เมื่อตั้งใจเปลี่ยนพฤติกรรมให้สร้างใหม่ด้วย
+	// go test ./internal/trading -run Golden -update แล้วตรวจ diff ก่อน commit
+	goldenFile = "testdata/golden/sol_15m_trades.json"
+
+	// baselineGoldenFile ผลของ runner เดียวกันจาก commit baseline ก่อน refactor เป็น Strategy (ห้ามสร้างใหม่)
+	// baseline บันทึกการเปิด position เป็นเทรดที่ไม่มีเวลาปิดด้วย ซึ่ง refactor ตัดออก นอกนั้น commit refactor
+	// ได้เทรดตรงกันทุกไม้ ยกเว้นชื่อเหตุผลปิดตอนข้อมูลหมดที่ถูกแก้กลับแล้ว
+	baselineGoldenFile = "testdata/golden/baseline_sol_15m_trades.json"
+)
+
+// goldenTrade field ของเทรดที่ตรึงไว้
+type goldenTrade struct {
+	Side        string  `json:"side"`
+	EntryTime   int64   `json:"entry_time"`
+	ExitTime    int64   `json:"exit_time"`
+	EntryPrice  float64 `json:"entry_price"`
+	ExitPrice   float64 `json:"exit_price"`
+	Quantity    float64 `json:"quantity"`
+	PnL         float64 `json:"pnl"`
+	Commission  float64 `json:"commission"`
+	NetPnL      float64 `json:"net_pnl"`
+	EntryReason string  `json:"entry_reason"`
+	ExitReason  string  `json:"exit_reason"`
+}
+
+type goldenRun struct {
+	FinalCapital float64       `json:"final_capital"`
+	Trades       []goldenTrade `json:"trades"`
+}
+
+// goldenRunners runner เดิมที่ถูกย้ายมาใช้ Strategy เดียวกัน
+var goldenRunners = map[string]func(bt *Backtester) *BacktestResult{
+	"RunBacktest": func(bt *Backtester) *BacktestResult {
+		result, _ := bt.RunBacktest()
+		return result
+	},
+	"RunTripleEMA1HStrategy": (*Backtester).RunTripleEMA1HStrategy,
+	"RunNew15mStrategy":      (*Backtester).RunNew15mStrategy,
+	"RunAggressiveBacktest":  (*Backtester).RunAggressiveBacktest,
+}
+
+func loadGoldenData(t *testing.T) []OHLCV {
+	t.Helper()
+	raw, err := os.ReadFile(goldenData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []OHLCV
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatal(err)
+	}
+	return data[:goldenBars]
+}
+
+func TestRunnersMatchGoldenTrades(t *testing.T) {
+	data := loadGoldenData(t)
+	got := make(map[string]goldenRun)
+	for name, run := range goldenRunners {
+		bt := newQuietBacktester(t, "SOL_USDT", 30)
+		bt.LoadHistoricalData(append([]OHLCV(nil), data...))
+		result := run(bt)
+
+		g := goldenRun{FinalCapital: result.FinalCapital}
+		for _, trade := range result.Trades {
+			g.Trades = append(g.Trades, goldenTrade{
+				Side: trade.Side, EntryTime: trade.EntryTime.Unix(), ExitTime: trade.ExitTime.Unix(),
+				EntryPrice: trade.EntryPrice, ExitPrice: trade.ExitPrice, Quantity: trade.Quantity,
+				PnL: trade.PnL, Commission: trade.Commission, NetPnL: trade.NetPnL,
+				EntryReason: trade.EntryReason, ExitReason: trade.ExitReason,
+			})
+		}
+		got[name] = g
+	}
+
+	if *updateGolden {
+		raw, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenFile, append(raw, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	raw, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want map[string]goldenRun
+	if err := json.Unmarshal(raw, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	for name := range goldenRunners {
+		g, w := got[name], want[name]
+		if len(g.Trades) != len(w.Trades) {
+			t.Errorf("%s: %d เทรด, golden %d", name, len(g.Trades), len(w.Trades))
+			continue
+		}
+		for i := range w.Trades {
+			if !sameGoldenTrade(g.Trades[i], w.Trades[i]) {
+				t.Errorf("%s: เทรด %d ต่างจาก golden\n  ได้     %+v\n  ต้องการ %+v", name, i, g.Trades[i], w.Trades[i])
+				break
+			}
+		}
+		if !goldenClose(g.FinalCapital, w.FinalCapital) {
+			t.Errorf("%s: เงินทุนสุดท้าย %v, golden %v", name, g.FinalCapital, w.FinalCapital)
+		}
+	}
+}
+
+func sameGoldenTrade(a, b goldenTrade) bool {
+	return a.Side == b.Side && a.EntryTime == b.EntryTime && a.ExitTime == b.ExitTime &&
+		a.EntryReason == b.EntryReason && a.ExitReason == b.ExitReason &&
+		goldenClose(a.EntryPrice, b.EntryPrice) && goldenClose(a.ExitPrice, b.ExitPrice) &&
+		goldenClose(a.Quantity, b.Quantity) && goldenClose(a.PnL, b.PnL) &&
+		goldenClose(a.Commission, b.Commission) && goldenClose(a.NetPnL, b.NetPnL)
+}
+
+func goldenClose(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestRunnersKeepBaselineEndOfDataLabel(t *testing.T) {
+	raw, err := os.ReadFile(baselineGoldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var baseline map[string]goldenRun
+	if err := json.Unmarshal(raw, &baseline); err != nil {
+		t.Fatal(err)
+	}
+
+	// ทุก runner ใน baseline ถือ position ค้างจนข้อมูลหมด: เทรดสุดท้ายบอกชื่อเหตุผลปิดเดิม
+	strategies := map[string]Strategy{
+		"RunBacktest":            NewPivotSuperTrendStrategy(),
+		"RunTripleEMA1HStrategy": NewTripleEMA1HStrategy(),
+		"RunNew15mStrategy":      NewTripleEMA15mStrategy(),
+		"RunAggressiveBacktest":  NewAggressiveStrategy(),
+	}
+	last := loadGoldenData(t)[goldenBars-1].Timestamp
+	for name, strategy := range strategies {
+		trades := baseline[name].Trades
+		if len(trades) == 0 || trades[len(trades)-1].ExitTime != last {
+			t.Fatalf("%s: baseline ไม่มีเทรดที่ปิดตอนข้อมูลหมด", name)
+		}
+		if want, got := trades[len(trades)-1].ExitReason, endOfDataReason(strategy); got != want {
+			t.Errorf("%s: ปิดตอนข้อมูลหมดด้วย %q, baseline ใช้ %q", name, got, want)
+		}
+	}
+}
+
+func TestChargeEntryOnOpenChargesEntryFeeOnce(t *testing.T) {
+	// Triple EMA 1H และ 15m หักค่าธรรมเนียมขาเข้าตอนเปิด: ตอนปิดต้องหักเฉพาะขาออก แต่ Commission ของเทรดรวมทั้งสองขา
+	data := hourly(candle(100, 100, 100, 100), candle(110, 110, 110, 110))
+	strategy := &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "LONG", Reason: "test"}},
+		quantity: 1,
+		fees:     FeeModel{EntryRate: 0.0005, ExitRate: 0.0005, ChargeEntryOnOpen: true},
+		exits:    map[int]string{1: "EXIT"},
+	}
+	result := runScripted(t, data, strategy, nil)
+
+	entryFee, exitFee := 100*0.0005, 110*0.0005
+	trade := result.Trades[0]
+	if !approx(trade.Commission, entryFee+exitFee) || !approx(trade.NetPnL, 10-entryFee-exitFee) {
+		t.Fatalf("commission %v net %v, ต้องการ %v และ %v", trade.Commission, trade.NetPnL, entryFee+exitFee, 10-entryFee-exitFee)
+	}
+	if want := 1000 + trade.NetPnL; !approx(result.FinalCapital, want) {
+		t.Fatalf("เงินทุนสุดท้าย %v, ต้องการ %v (ทุนเริ่มต้น + NetPnL)", result.FinalCapital, want)
+	}
+}
@@ -0,0 +1,69 @@
+package trading
+
+import "fmt"
+
+// HTFTrendFilter ครอบกลยุทธ์เดิมด้วย trend filter จาก timeframe ใหญ่:
+// เข้า LONG เฉพาะเมื่อราคาปิดของแท่งใหญ่ล่าสุดอยู่เหนือ EMA และ SHORT เมื่ออยู่ใต้ EMA
+// ใช้เฉพาะแท่งใหญ่ที่ปิดแล้ว และไม่เข้าเทรดจนกว่าจะมีแท่งใหญ่ครบ EMAPeriod
+type HTFTrendFilter struct {
+	Strategy
+	Interval  string // timeframe ของ filter เช่น "1d"
+	EMAPeriod int
+
+	trend int // 1 = ขาขึ้น, -1 = ขาลง, 0 = ยังไม่พร้อม
+}
+
+// NewHTFTrendFilter สร้าง filter ครอบกลยุทธ์ (เช่น NewHTFTrendFilter(NewPivotSuperTrendStrategy(), "1d", 200))
+func NewHTFTrendFilter(strategy Strategy, interval string, emaPeriod int) *HTFTrendFilter {
+	return &HTFTrendFilter{Strategy: strategy, Interval: interval, EMAPeriod: emaPeriod}
+}
+
+// Name ชื่อกลยุทธ์พร้อม filter
+func (f *HTFTrendFilter) Name() string {
+	return fmt.Sprintf("%s + %s EMA%d Filter", f.Strategy.Name(), f.Interval, f.EMAPeriod)
+}
+
+// OnBar อัปเดต trend ของ timeframe ใหญ่ แล้วส่งต่อให้กลยุทธ์เดิม
+func (f *HTFTrendFilter) OnBar(bt *Backtester) {
+	f.trend = 0
+	bars, err := bt.HigherTimeframe(f.Interval)
+	if err != nil {
+		bt.halt(err)
+	} else if len(bars) >= f.EMAPeriod {
+		ema := bt.indicators.calculateEMA(bars, f.EMAPeriod)
+		last := len(bars) - 1
+		if bars[last].Close > ema[last] {
+			f.trend = 1
+		} else if bars[last].Close < ema[last] {
+			f.trend = -1
+		}
+	}
+
+	f.Strategy.OnBar(bt)
+}
+
+// EntrySignal ส่งต่อสัญญาณของกลยุทธ์เดิมเฉพาะที่ตรงกับ trend ของ timeframe ใหญ่
+func (f *HTFTrendFilter) EntrySignal(bt *Backtester) *EntrySignal {
+	signal := f.Strategy.EntrySignal(bt)
+	if signal == nil {
+		return nil
+	}
+	if (signal.Side == "LONG" && f.trend != 1) || (signal.Side == "SHORT" && f.trend != -1) {
+		return nil
+	}
+
+	signal.Reason += fmt.Sprintf(" | %s EMA%d %s", f.Interval, f.EMAPeriod, signal.Side)
+	return signal
+}
+
+// IndicatorValues ค่า indicators ของกลยุทธ์เดิม (ถ้ามี) และ trend ของ timeframe ใหญ่
+func (f *HTFTrendFilter) IndicatorValues(bt *Backtester) map[string]float64 {
+	values := map[string]float64{}
+	if reporter, ok := f.Strategy.(IndicatorReporter); ok {
+		for name, value := range reporter.IndicatorValues(bt) {
+			values[name] = value
+		}
+	}
+	values["htf_trend"] = float64(f.trend)
+	return values
+}
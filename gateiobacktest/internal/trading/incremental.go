@@ -0,0 +1,449 @@
+package trading
+
+import "math"
+
+// ตัวชี้วัดแบบ streaming: อัปเดตทีละแท่งด้วยเวลาคงที่ต่อแท่ง (ไม่ขึ้นกับความยาวข้อมูล)
+// และให้ค่าเท่ากับ implementation แบบ batch ที่คำนวณจากข้อมูลทั้งหมดจนถึงแท่งนั้นทุกบิต
+// ค่าก่อนที่ตัวชี้วัดจะพร้อมเป็น 0 เหมือน batch
+
+// ring บัฟเฟอร์วงกลมเก็บค่าล่าสุด size ตัว อ้างอิงด้วย index สัมบูรณ์ของแท่ง
+type ring[T any] struct {
+	values []T
+	count  int // จำนวนค่าที่ push ทั้งหมด
+}
+
+func newRing[T any](size int) *ring[T] {
+	return &ring[T]{values: make([]T, size)}
+}
+
+func (r *ring[T]) push(value T) {
+	r.values[r.count%len(r.values)] = value
+	r.count++
+}
+
+// at ค่าที่ index สัมบูรณ์ i (ต้องอยู่ใน size ค่าล่าสุด)
+func (r *ring[T]) at(i int) T {
+	return r.values[i%len(r.values)]
+}
+
+// trueRange True Range ของแท่งเทียนเทียบกับราคาปิดก่อนหน้า
+func trueRange(candle OHLCV, prevClose float64) float64 {
+	return math.Max(candle.High-candle.Low,
+		math.Max(math.Abs(candle.High-prevClose), math.Abs(candle.Low-prevClose)))
+}
+
+// EMAState EMA แบบ streaming เริ่มจาก SMA ของ period ค่าแรก (เท่ากับ indicators.EMA)
+type EMAState struct {
+	period     int
+	multiplier float64
+	count      int
+	sum        float64
+	value      float64
+}
+
+// NewEMAState สร้าง EMA แบบ streaming
+func NewEMAState(period int) *EMAState {
+	return &EMAState{period: period, multiplier: 2.0 / float64(period+1)}
+}
+
+// Update เพิ่มค่าใหม่และคืน EMA ล่าสุด
+func (s *EMAState) Update(value float64) float64 {
+	s.count++
+	switch {
+	case s.count < s.period:
+		s.sum += value
+	case s.count == s.period:
+		s.sum += value
+		s.value = s.sum / float64(s.period)
+	default:
+		s.value = (value * s.multiplier) + (s.value * (1 - s.multiplier))
+	}
+	return s.value
+}
+
+// Value EMA ล่าสุด (0 = ยังไม่พร้อม)
+func (s *EMAState) Value() float64 {
+	return s.value
+}
+
+// ATRState ATR แบบ streaming เท่ากับ indicators.ATR (Wilder) หรือ SMA ของ indicators.TrueRange
+type ATRState struct {
+	period    int
+	wilder    bool
+	alpha     float64
+	count     int
+	prevClose float64
+	tr        *ring[float64]
+	sum       float64
+	value     float64
+}
+
+// NewATRState สร้าง ATR แบบ streaming (wilder = true ใช้ Wilder smoothing แบบ ta.atr ของ Pine)
+func NewATRState(period int, wilder bool) *ATRState {
+	return &ATRState{period: period, wilder: wilder, alpha: 1 / float64(period), tr: newRing[float64](period + 1)}
+}
+
+// Update เพิ่มแท่งใหม่และคืน ATR ล่าสุด
+func (s *ATRState) Update(candle OHLCV) float64 {
+	i := s.count
+	s.count++
+
+	tr := candle.High - candle.Low
+	if i > 0 {
+		tr = trueRange(candle, s.prevClose)
+	}
+	s.prevClose = candle.Close
+	s.tr.push(tr)
+
+	switch {
+	case i < s.period-1:
+		s.sum += tr
+	case i == s.period-1:
+		s.sum += tr
+		s.value = s.sum / float64(s.period)
+	case s.wilder:
+		s.value = s.alpha*tr + (1-s.alpha)*s.value
+	default:
+		s.sum += tr
+		s.sum -= s.tr.at(i - s.period)
+		s.value = s.sum / float64(s.period)
+	}
+	return s.value
+}
+
+// Ready ATR พร้อมใช้แล้ว (ได้ครบ period แท่ง)
+func (s *ATRState) Ready() bool {
+	return s.count >= s.period
+}
+
+// Value ATR ล่าสุด (0 = ยังไม่พร้อม)
+func (s *ATRState) Value() float64 {
+	return s.value
+}
+
+// RSIState RSI แบบ Wilder (RMA ของกำไร/ขาดทุน เริ่มจาก SMA) แบบ streaming เท่ากับ indicators.RSI
+type RSIState struct {
+	period    int
+	alpha     float64
+	count     int
+	prevClose float64
+	avgGain   float64
+	avgLoss   float64
+	value     float64
+}
+
+// NewRSIState สร้าง RSI แบบ streaming
+func NewRSIState(period int) *RSIState {
+	return &RSIState{period: period, alpha: 1 / float64(period)}
+}
+
+// Update เพิ่มราคาปิดใหม่และคืน RSI ล่าสุด
+func (s *RSIState) Update(close float64) float64 {
+	i := s.count
+	s.count++
+	change := close - s.prevClose
+	s.prevClose = close
+	if i == 0 {
+		return s.value
+	}
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+
+	switch {
+	case i < s.period:
+		s.avgGain += gain
+		s.avgLoss += loss
+	case i == s.period:
+		s.avgGain = (s.avgGain + gain) / float64(s.period)
+		s.avgLoss = (s.avgLoss + loss) / float64(s.period)
+		s.value = rsiFromAverages(s.avgGain, s.avgLoss)
+	default:
+		s.avgGain = s.alpha*gain + (1-s.alpha)*s.avgGain
+		s.avgLoss = s.alpha*loss + (1-s.alpha)*s.avgLoss
+		s.value = rsiFromAverages(s.avgGain, s.avgLoss)
+	}
+	return s.value
+}
+
+// Value RSI ล่าสุด (0 = ยังไม่พร้อม)
+func (s *RSIState) Value() float64 {
+	return s.value
+}
+
+// rsiFromAverages RSI จากค่าเฉลี่ยกำไร/ขาดทุน (ไม่มีขาดทุน = 100, ไม่มีกำไร = 0)
+func rsiFromAverages(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		return 100
+	}
+	if avgGain == 0 {
+		return 0
+	}
+	return 100 - (100 / (1 + avgGain/avgLoss))
+}
+
+// SuperTrendState SuperTrend (hl2 ± factor x ATR) แบบ streaming เท่ากับ indicators.SuperTrendFromATR
+type SuperTrendState struct {
+	atr          *ATRState
+	factor       float64
+	prevClose    float64
+	upper, lower float64
+	trend        int
+}
+
+// NewSuperTrendState สร้าง SuperTrend แบบ streaming
+func NewSuperTrendState(atrPeriod int, wilderATR bool, factor float64) *SuperTrendState {
+	return &SuperTrendState{atr: NewATRState(atrPeriod, wilderATR), factor: factor}
+}
+
+// Update เพิ่มแท่งใหม่และคืนทิศทาง (1 = ขาขึ้น, -1 = ขาลง, 0 = ATR ยังไม่พร้อม)
+func (s *SuperTrendState) Update(candle OHLCV) int {
+	atr := s.atr.Update(candle)
+	prevClose := s.prevClose
+	s.prevClose = candle.Close
+
+	if !s.atr.Ready() {
+		s.trend = 0
+		return s.trend
+	}
+	hl2 := (candle.High + candle.Low) / 2
+	basicUpper := hl2 + s.factor*atr
+	basicLower := hl2 - s.factor*atr
+
+	if s.trend == 0 {
+		// แท่งแรกที่ ATR พร้อมเริ่มเป็นขาลงเหมือน ta.supertrend
+		s.upper, s.lower = basicUpper, basicLower
+		s.trend = -1
+		return s.trend
+	}
+
+	if basicUpper < s.upper || prevClose > s.upper {
+		s.upper = basicUpper
+	}
+	if basicLower > s.lower || prevClose < s.lower {
+		s.lower = basicLower
+	}
+
+	if s.trend == -1 && candle.Close > s.upper {
+		s.trend = 1
+	} else if s.trend == 1 && candle.Close < s.lower {
+		s.trend = -1
+	}
+	return s.trend
+}
+
+// Trend ทิศทางล่าสุด
+func (s *SuperTrendState) Trend() int {
+	return s.trend
+}
+
+// PivotState ตรวจ Pivot High/Low แบบ streaming: pivot ที่แท่ง i ยืนยันได้เมื่อได้แท่ง i+period แล้ว
+// (เงื่อนไขเดียวกับ Indicators.calculatePivotPoints)
+type PivotState struct {
+	period int
+	bars   *ring[OHLCV]
+}
+
+// NewPivotState สร้างตัวตรวจ pivot แบบ streaming
+func NewPivotState(period int) *PivotState {
+	return &PivotState{period: period, bars: newRing[OHLCV](2*period + 1)}
+}
+
+// Update เพิ่มแท่งใหม่และคืน index ของแท่งที่เพิ่งตรวจได้ (-1 = ยังไม่มี)
+// พร้อมราคา pivot high/low ของแท่งนั้น (0 = ไม่ใช่ pivot)
+func (s *PivotState) Update(candle OHLCV) (index int, pivotHigh, pivotLow float64) {
+	s.bars.push(candle)
+	t := s.bars.count - 1
+	i := t - s.period
+	if i < s.period {
+		return -1, 0, 0
+	}
+
+	center := s.bars.at(i)
+	isHigh, isLow := true, true
+	for j := i - s.period; j <= t; j++ {
+		if j == i {
+			continue
+		}
+		other := s.bars.at(j)
+		if other.High >= center.High {
+			isHigh = false
+		}
+		if other.Low <= center.Low {
+			isLow = false
+		}
+	}
+	if isHigh {
+		pivotHigh = center.High
+	}
+	if isLow {
+		pivotLow = center.Low
+	}
+	return i, pivotHigh, pivotLow
+}
+
+// pivotChain สถานะ TUp/TDown/Trend ของ Pivot SuperTrend ที่แท่งหนึ่ง
+type pivotChain struct {
+	tUp, tDown float64
+	trend      int
+	superTrend float64
+}
+
+// pivotBar ข้อมูลต่อแท่งที่ PivotSuperTrendState ต้องย้อนดู
+type pivotBar struct {
+	candle OHLCV
+	atr    float64
+	ema    float64
+}
+
+// PivotSuperTrendState Pivot Point SuperTrend + EMA แบบ streaming ที่ให้ผลเท่ากับ
+// Indicators.AnalyzePivotPointSuperTrend บนข้อมูลทั้งหมดจนถึงแท่งล่าสุด
+// (pivot มีผลกับ center line ตอนแท่งที่ยืนยันได้เหมือน Pine จึงไม่ต้องย้อนคำนวณแท่งเก่า)
+type PivotSuperTrendState struct {
+	ind    *Indicators
+	count  int
+	bars   *ring[pivotBar] // แท่งล่าสุด 2 แท่ง
+	pivots *PivotState
+	atr    *ATRState
+	ema    *EMAState
+
+	center float64    // center line ล่าสุด
+	chain  pivotChain // สถานะของแท่งล่าสุด
+}
+
+// NewPivotSuperTrendState สร้าง Pivot SuperTrend แบบ streaming ด้วยพารามิเตอร์ของ ind
+func NewPivotSuperTrendState(ind *Indicators) *PivotSuperTrendState {
+	return &PivotSuperTrendState{
+		ind:    ind,
+		bars:   newRing[pivotBar](2),
+		pivots: NewPivotState(ind.pivotPeriod),
+		atr:    NewATRState(ind.atrPeriod, true),
+		ema:    NewEMAState(ind.emaPeriod),
+	}
+}
+
+// Count จำนวนแท่งที่ประมวลผลแล้ว
+func (s *PivotSuperTrendState) Count() int {
+	return s.count
+}
+
+// Update เพิ่มแท่งใหม่และคืนผลวิเคราะห์ ณ แท่งนั้น
+func (s *PivotSuperTrendState) Update(candle OHLCV) *SuperTrendAnalysis {
+	t := s.count
+	s.count++
+	s.bars.push(pivotBar{candle: candle, atr: s.atr.Update(candle), ema: s.ema.Update(candle.Close)})
+
+	// pivot ของแท่ง t-period ยืนยันได้ที่แท่งนี้
+	if c, pivotHigh, pivotLow := s.pivots.Update(candle); c >= 0 {
+		lastPivot := pivotHigh
+		if lastPivot == 0 {
+			lastPivot = pivotLow
+		}
+		if lastPivot != 0 {
+			if s.center == 0 {
+				s.center = lastPivot
+			} else {
+				s.center = (s.center*2 + lastPivot) / 3
+			}
+		}
+	}
+
+	prev := s.chain
+	s.chain = s.step(prev, t)
+	return s.analysis(s.chain, prev)
+}
+
+// step สถานะของแท่ง i จากสถานะแท่งก่อนหน้า (เท่ากับ calculateSuperTrendBands + calculateSuperTrend)
+func (s *PivotSuperTrendState) step(prev pivotChain, i int) pivotChain {
+	if i == 0 {
+		return pivotChain{trend: 1}
+	}
+	bar, prevBar := s.bars.at(i), s.bars.at(i-1)
+
+	var upperBand, lowerBand float64
+	if s.center != 0 && bar.atr != 0 {
+		upperBand = s.center - (s.ind.atrFactor * bar.atr)
+		lowerBand = s.center + (s.ind.atrFactor * bar.atr)
+	}
+
+	var next pivotChain
+	close, prevClose := bar.candle.Close, prevBar.candle.Close
+	if prevClose > prev.tUp {
+		next.tUp = math.Max(upperBand, prev.tUp)
+	} else {
+		next.tUp = upperBand
+	}
+	if prevClose < prev.tDown {
+		next.tDown = math.Min(lowerBand, prev.tDown)
+	} else {
+		next.tDown = lowerBand
+	}
+
+	if close > prev.tDown {
+		next.trend = 1
+	} else if close < prev.tUp {
+		next.trend = -1
+	} else {
+		next.trend = prev.trend
+	}
+
+	if next.trend == 1 {
+		next.superTrend = next.tUp
+	} else {
+		next.superTrend = next.tDown
+	}
+	return next
+}
+
+// analysis ผลวิเคราะห์ของแท่งล่าสุดโดยใช้ analyzeSignal/calculateRiskReward ชุดเดียวกับ batch
+func (s *PivotSuperTrendState) analysis(current, prev pivotChain) *SuperTrendAnalysis {
+	if s.count < s.ind.emaPeriod {
+		return &SuperTrendAnalysis{Trend: 0, Signal: "NEUTRAL", Confidence: 0, RiskRewardRatio: 0}
+	}
+
+	// แท่งล่าสุด 2 แท่ง (หรือ 1 แท่งถ้ามีแท่งเดียว) พอสำหรับ analyzeSignal และ calculateRiskReward
+	t := s.count - 1
+	var ohlcv []OHLCV
+	var trend []int
+	var ema, superTrend, atr []float64
+	if t > 0 {
+		bar := s.bars.at(t - 1)
+		ohlcv, trend = append(ohlcv, bar.candle), append(trend, prev.trend)
+		ema, superTrend, atr = append(ema, bar.ema), append(superTrend, prev.superTrend), append(atr, bar.atr)
+	}
+	bar := s.bars.at(t)
+	ohlcv, trend = append(ohlcv, bar.candle), append(trend, current.trend)
+	ema, superTrend, atr = append(ema, bar.ema), append(superTrend, current.superTrend), append(atr, bar.atr)
+
+	signal, confidence := s.ind.analyzeSignal(ohlcv, trend, ema, superTrend)
+	return &SuperTrendAnalysis{
+		Trend:           current.trend,
+		Signal:          signal,
+		Confidence:      confidence,
+		RiskRewardRatio: s.ind.calculateRiskReward(ohlcv, superTrend, atr),
+		SuperTrendValue: current.superTrend,
+		EMA100:          bar.ema,
+		CurrentPrice:    bar.candle.Close,
+		ATR:             bar.atr,
+	}
+}
+
+// SetIncrementalIndicators ให้ analyzeMarket ใช้ PivotSuperTrendState บนข้อมูลทั้งหมดแทนการคำนวณใหม่
+// จาก max_lookback แท่งล่าสุดทุกแท่ง (เร็วกว่ามากแต่ EMA/ATR ต่อเนื่องจากต้นข้อมูล ผลจึงต่างจากแบบ window)
+func (bt *Backtester) SetIncrementalIndicators(enabled bool) {
+	bt.incremental = enabled
+	bt.stream = nil
+}
+
+// incrementalAnalysis ผลวิเคราะห์ของแท่งปัจจุบันจาก stream (ป้อนแท่งที่ยังไม่ได้ประมวลผลจนถึง currentIndex)
+func (bt *Backtester) incrementalAnalysis() *SuperTrendAnalysis {
+	if bt.stream == nil || bt.stream.Count() > bt.currentIndex+1 {
+		bt.stream = NewPivotSuperTrendState(bt.indicators)
+		bt.streamAnalysis = nil
+	}
+	for bt.stream.Count() <= bt.currentIndex {
+		bt.streamAnalysis = bt.stream.Update(bt.ohlcvData[bt.stream.Count()])
+	}
+
+	analysis := *bt.streamAnalysis
+	analysis.CurrentPrice = bt.currentPrice
+	return &analysis
+}
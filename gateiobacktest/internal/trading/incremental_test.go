@@ -0,0 +1,206 @@
+package trading
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gateio-trading-bot/internal/indicators"
+)
+
+// syntheticCandles แท่งเทียน 15m แบบ random walk ที่สร้างซ้ำได้จาก seed
+func syntheticCandles(n int, seed int64) []OHLCV {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]OHLCV, n)
+	price := 100.0
+	for i := range data {
+		open := price
+		price *= 1 + rng.NormFloat64()*0.004
+		high := math.Max(open, price) * (1 + rng.Float64()*0.003)
+		low := math.Min(open, price) * (1 - rng.Float64()*0.003)
+		data[i] = OHLCV{
+			Timestamp: 1_700_000_000 + int64(i)*900,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     price,
+			Volume:    1000 + rng.Float64()*500,
+		}
+	}
+	return data
+}
+
+func TestEMAStateMatchesBatch(t *testing.T) {
+	data := syntheticCandles(500, 1)
+	ind := NewIndicators()
+	for _, period := range []int{1, 9, 21, 100} {
+		batch := ind.calculateEMA(data, period)
+		stream := NewEMAState(period)
+		for i, candle := range data {
+			if got := stream.Update(candle.Close); got != batch[i] {
+				t.Fatalf("EMA(%d) แท่ง %d: stream %v, batch %v", period, i, got, batch[i])
+			}
+		}
+	}
+}
+
+func TestATRStateMatchesBatch(t *testing.T) {
+	data := syntheticCandles(500, 2)
+	bars := toBars(data)
+	for _, wilder := range []bool{false, true} {
+		for _, period := range []int{1, 10, 14} {
+			batch := indicators.SMA(indicators.TrueRange(bars), period)
+			if wilder {
+				batch = indicators.ATR(bars, period)
+			}
+			batch = indicators.Nz(batch)
+
+			stream := NewATRState(period, wilder)
+			for i, candle := range data {
+				if got := stream.Update(candle); got != batch[i] {
+					t.Fatalf("ATR(%d, wilder=%v) แท่ง %d: stream %v, batch %v", period, wilder, i, got, batch[i])
+				}
+			}
+		}
+	}
+}
+
+func TestRSIStateMatchesBatch(t *testing.T) {
+	data := syntheticCandles(500, 3)
+	for _, period := range []int{2, 14} {
+		batch := indicators.Nz(indicators.RSI(closePrices(data), period))
+		stream := NewRSIState(period)
+		for i, candle := range data {
+			if got := stream.Update(candle.Close); got != batch[i] {
+				t.Fatalf("RSI(%d) แท่ง %d: stream %v, batch %v", period, i, got, batch[i])
+			}
+		}
+	}
+}
+
+func TestSuperTrendStateMatchesBatch(t *testing.T) {
+	data := syntheticCandles(500, 4)
+	bars := toBars(data)
+	for _, wilder := range []bool{false, true} {
+		atr := indicators.SMA(indicators.TrueRange(bars), 10)
+		if wilder {
+			atr = indicators.ATR(bars, 10)
+		}
+		_, batch := indicators.SuperTrendFromATR(bars, atr, 2.5)
+
+		stream := NewSuperTrendState(10, wilder, 2.5)
+		for i, candle := range data {
+			if got := stream.Update(candle); got != batch[i] {
+				t.Fatalf("SuperTrend(wilder=%v) แท่ง %d: stream %d, batch %d", wilder, i, got, batch[i])
+			}
+		}
+	}
+}
+
+func TestPivotStateMatchesBatch(t *testing.T) {
+	data := syntheticCandles(500, 5)
+	ind := NewIndicators()
+	highs, lows := ind.calculatePivotPoints(data)
+
+	stream := NewPivotState(ind.pivotPeriod)
+	checked := 0
+	for _, candle := range data {
+		index, high, low := stream.Update(candle)
+		if index < 0 {
+			continue
+		}
+		if high != highs[index] || low != lows[index] {
+			t.Fatalf("pivot แท่ง %d: stream (%v, %v), batch (%v, %v)", index, high, low, highs[index], lows[index])
+		}
+		checked++
+	}
+	if want := len(data) - 2*ind.pivotPeriod; checked != want {
+		t.Fatalf("ตรวจ pivot %d แท่ง, ต้องการ %d", checked, want)
+	}
+}
+
+func TestPivotSuperTrendStateMatchesBatch(t *testing.T) {
+	data := syntheticCandles(400, 6)
+	cases := []StrategyParams{
+		DefaultStrategyParams(),
+		{PivotPeriod: 3, ATRPeriod: 14, ATRFactor: 2.0, EMAPeriod: 50},
+		{PivotPeriod: 1, ATRPeriod: 5, ATRFactor: 1.5, EMAPeriod: 1},
+	}
+
+	for _, params := range cases {
+		ind := NewIndicatorsWithParams(params)
+		stream := NewPivotSuperTrendState(ind)
+		for i, candle := range data {
+			got := stream.Update(candle)
+			want := ind.AnalyzePivotPointSuperTrend(data[:i+1])
+			if *got != *want {
+				t.Fatalf("params %+v แท่ง %d:\nstream %+v\nbatch  %+v", params, i, *got, *want)
+			}
+		}
+	}
+}
+
+func TestIncrementalAnalyzeMarketCatchesUp(t *testing.T) {
+	data := syntheticCandles(300, 7)
+	bt, err := NewBacktesterSimple("TEST_USDT", 0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bt.ohlcvData = data
+	bt.SetIncrementalIndicators(true)
+
+	// เรียกข้ามแท่งและย้อนกลับ (เริ่มรันใหม่) ต้องได้ผลเท่ากับ batch บนข้อมูลถึงแท่งนั้น
+	for _, index := range []int{150, 151, 200, 200, 299, 120} {
+		bt.currentIndex = index
+		bt.currentPrice = data[index].Close
+		got := bt.analyzeMarket()
+		want := bt.indicators.AnalyzePivotPointSuperTrend(data[:index+1])
+		if *got != *want {
+			t.Fatalf("แท่ง %d:\nincremental %+v\nbatch       %+v", index, *got, *want)
+		}
+	}
+}
+
+// benchmarkBars หนึ่งปีของแท่ง 15m
+const benchmarkBars = 365 * 96
+
+func BenchmarkPivotSuperTrend(b *testing.B) {
+	data := syntheticCandles(benchmarkBars, 42)
+	ind := NewIndicators()
+
+	b.Run("window", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for i := 100; i < len(data); i++ {
+				ind.AnalyzePivotPointSuperTrend(data[i-99 : i+1])
+			}
+		}
+	})
+	b.Run("incremental", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			stream := NewPivotSuperTrendState(ind)
+			for _, candle := range data {
+				stream.Update(candle)
+			}
+		}
+	})
+}
+
+func BenchmarkRunStrategy(b *testing.B) {
+	data := syntheticCandles(benchmarkBars, 42)
+
+	for _, mode := range []struct {
+		name        string
+		incremental bool
+	}{{"window", false}, {"incremental", true}} {
+		b.Run(mode.name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				bt := newQuietBacktester(b, "TEST_USDT", 0)
+				bt.SetIncrementalIndicators(mode.incremental)
+				bt.LoadHistoricalData(data)
+				if _, err := bt.RunStrategy(NewPivotSuperTrendStrategy()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
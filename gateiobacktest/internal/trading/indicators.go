@@ -1,15 +1,30 @@
 package trading
 
 import (
+	"fmt"
 	"math"
+
+	"gateio-trading-bot/internal/indicators"
+)
+
+const (
+	rsiPeriod     = 14  // ความยาว RSI มาตรฐานของ Wilder
+	rsiWarmupBars = 100 // แท่งเพิ่มก่อนช่วงที่คืนค่า ให้ค่าเฉลี่ยของ Wilder ลู่เข้า
 )
 
+// MarketDataSource แหล่งแท่งเทียนของ exchange (GateClient.GetOHLCV)
+type MarketDataSource interface {
+	GetOHLCV(contract, interval string, limit int) ([]OHLCV, error)
+}
+
 // Indicators สำหรับคำนวณ technical indicators
 type Indicators struct {
 	pivotPeriod int
 	atrPeriod   int
 	atrFactor   float64
 	emaPeriod   int
+
+	marketData MarketDataSource // ใช้ดึงแท่งเทียนใน GetRSI (nil = ดึงไม่ได้)
 }
 
 // NewIndicators สร้าง Indicators ใหม่
@@ -22,6 +37,49 @@ func NewIndicators() *Indicators {
 	}
 }
 
+// closePrices ราคาปิดของทุกแท่ง
+func closePrices(data []OHLCV) []float64 {
+	closes := make([]float64, len(data))
+	for i, candle := range data {
+		closes[i] = candle.Close
+	}
+	return closes
+}
+
+// toBars แปลงแท่งเทียนเป็นคอลัมน์สำหรับแพ็กเกจ indicators
+func toBars(data []OHLCV) indicators.Bars {
+	bars := indicators.Bars{
+		Time:   make([]int64, len(data)),
+		Open:   make([]float64, len(data)),
+		High:   make([]float64, len(data)),
+		Low:    make([]float64, len(data)),
+		Close:  make([]float64, len(data)),
+		Volume: make([]float64, len(data)),
+	}
+	for i, candle := range data {
+		bars.Time[i] = candle.Timestamp
+		bars.Open[i] = candle.Open
+		bars.High[i] = candle.High
+		bars.Low[i] = candle.Low
+		bars.Close[i] = candle.Close
+		bars.Volume[i] = candle.Volume
+	}
+	return bars
+}
+
+// lastOr ค่าของแท่งล่าสุด หรือ fallback เมื่อข้อมูลยังไม่พอ (NaN)
+func lastOr(series []float64, fallback float64) float64 {
+	if value := indicators.Last(series); !math.IsNaN(value) {
+		return value
+	}
+	return fallback
+}
+
+// SetMarketDataSource กำหนดแหล่งแท่งเทียนที่ GetRSI ใช้
+func (ind *Indicators) SetMarketDataSource(source MarketDataSource) {
+	ind.marketData = source
+}
+
 // AnalyzePivotPointSuperTrend วิเคราะห์ Pivot Point SuperTrend + EMA100
 func (ind *Indicators) AnalyzePivotPointSuperTrend(ohlcv []OHLCV) *SuperTrendAnalysis {
 	if len(ohlcv) < ind.emaPeriod {
@@ -33,20 +91,8 @@ func (ind *Indicators) AnalyzePivotPointSuperTrend(ohlcv []OHLCV) *SuperTrendAna
 		}
 	}
 
-	// คำนวณ Pivot Points
-	pivotHighs, pivotLows := ind.calculatePivotPoints(ohlcv)
-
-	// คำนวณ Center Line จาก Pivot Points
-	centerLine := ind.calculateCenterLine(pivotHighs, pivotLows, len(ohlcv))
-
-	// คำนวณ ATR
-	atr := ind.calculateATR(ohlcv)
-
-	// คำนวณ SuperTrend Bands
-	upperBand, lowerBand := ind.calculateSuperTrendBands(centerLine, atr)
-
-	// คำนวณ SuperTrend และ Trend
-	superTrend, trend := ind.calculateSuperTrend(ohlcv, upperBand, lowerBand)
+	series := ind.pivotSuperTrend(ohlcv)
+	atr, superTrend, trend := series.atr, series.superTrend, series.trend
 
 	// คำนวณ EMA100
 	ema100 := ind.calculateEMA(ohlcv, ind.emaPeriod)
@@ -75,6 +121,37 @@ func (ind *Indicators) AnalyzePivotPointSuperTrend(ohlcv []OHLCV) *SuperTrendAna
 	}
 }
 
+// pivotSuperTrendSeries ค่าทุกแท่งของ Pivot Point SuperTrend (0 = ยังไม่พร้อม)
+type pivotSuperTrendSeries struct {
+	centerLine []float64
+	upperBand  []float64 // Up ของ Pine: center - factor x ATR
+	lowerBand  []float64 // Dn ของ Pine: center + factor x ATR
+	atr        []float64
+	superTrend []float64
+	trend      []int
+}
+
+// pivotSuperTrend คำนวณ Pivot Point SuperTrend ทุกแท่ง
+func (ind *Indicators) pivotSuperTrend(ohlcv []OHLCV) pivotSuperTrendSeries {
+	var series pivotSuperTrendSeries
+
+	// คำนวณ Pivot Points
+	pivotHighs, pivotLows := ind.calculatePivotPoints(ohlcv)
+
+	// คำนวณ Center Line จาก Pivot Points
+	series.centerLine = ind.calculateCenterLine(pivotHighs, pivotLows, len(ohlcv))
+
+	// คำนวณ ATR
+	series.atr = ind.calculateATR(ohlcv)
+
+	// คำนวณ SuperTrend Bands
+	series.upperBand, series.lowerBand = ind.calculateSuperTrendBands(series.centerLine, series.atr)
+
+	// คำนวณ SuperTrend และ Trend
+	series.superTrend, series.trend = ind.calculateSuperTrend(ohlcv, series.upperBand, series.lowerBand)
+	return series
+}
+
 // calculatePivotPoints คำนวณ Pivot High/Low
 func (ind *Indicators) calculatePivotPoints(ohlcv []OHLCV) ([]float64, []float64) {
 	period := ind.pivotPeriod
@@ -118,7 +195,7 @@ func (ind *Indicators) calculatePivotPoints(ohlcv []OHLCV) ([]float64, []float64
 	return pivotHighs, pivotLows
 }
 
-// calculateCenterLine คำนวณ Center Line จาก Pivot Points
+// calculateCenterLine คำนวณ Center Line จาก Pivot Points (pivot มีผลตั้งแต่แท่งที่ยืนยันได้)
 func (ind *Indicators) calculateCenterLine(pivotHighs, pivotLows []float64, length int) []float64 {
 	centerLine := make([]float64, length)
 	var center float64
@@ -126,10 +203,13 @@ func (ind *Indicators) calculateCenterLine(pivotHighs, pivotLows []float64, leng
 	for i := 0; i < length; i++ {
 		var lastPivot float64
 
-		if pivotHighs[i] != 0 {
-			lastPivot = pivotHighs[i]
-		} else if pivotLows[i] != 0 {
-			lastPivot = pivotLows[i]
+		// pivothigh(prd, prd) ของ Pine ให้ค่าที่แท่งที่ยืนยัน pivot ได้ (prd แท่งหลังแท่ง pivot)
+		if c := i - ind.pivotPeriod; c >= 0 {
+			if pivotHighs[c] != 0 {
+				lastPivot = pivotHighs[c]
+			} else if pivotLows[c] != 0 {
+				lastPivot = pivotLows[c]
+			}
 		}
 
 		if lastPivot != 0 {
@@ -147,39 +227,9 @@ func (ind *Indicators) calculateCenterLine(pivotHighs, pivotLows []float64, leng
 	return centerLine
 }
 
-// calculateATR คำนวณ Average True Range
+// calculateATR คำนวณ Average True Range แบบ Wilder เหมือน atr() ของ Pine (0 = ข้อมูลยังไม่พอ)
 func (ind *Indicators) calculateATR(ohlcv []OHLCV) []float64 {
-	period := ind.atrPeriod
-	atr := make([]float64, len(ohlcv))
-
-	if len(ohlcv) < period {
-		return atr
-	}
-
-	// คำนวณ True Range
-	trueRanges := make([]float64, len(ohlcv))
-	for i := 1; i < len(ohlcv); i++ {
-		high := ohlcv[i].High
-		low := ohlcv[i].Low
-		prevClose := ohlcv[i-1].Close
-
-		tr1 := high - low
-		tr2 := math.Abs(high - prevClose)
-		tr3 := math.Abs(low - prevClose)
-
-		trueRanges[i] = math.Max(tr1, math.Max(tr2, tr3))
-	}
-
-	// คำนวณ ATR (Simple Moving Average ของ True Range)
-	for i := period; i < len(ohlcv); i++ {
-		sum := 0.0
-		for j := i - period + 1; j <= i; j++ {
-			sum += trueRanges[j]
-		}
-		atr[i] = sum / float64(period)
-	}
-
-	return atr
+	return indicators.Nz(indicators.ATR(toBars(ohlcv), ind.atrPeriod))
 }
 
 // calculateSuperTrendBands คำนวณ SuperTrend Upper/Lower Bands
@@ -205,6 +255,9 @@ func (ind *Indicators) calculateSuperTrend(ohlcv []OHLCV, upperBand, lowerBand [
 	trend := make([]int, length)
 	tUp := make([]float64, length)
 	tDown := make([]float64, length)
+	if length > 0 {
+		trend[0] = 1 // nz(Trend[1], 1) ของ Pine
+	}
 
 	for i := 1; i < length; i++ {
 		close := ohlcv[i].Close
@@ -247,28 +300,9 @@ func (ind *Indicators) calculateSuperTrend(ohlcv []OHLCV, upperBand, lowerBand [
 	return superTrend, trend
 }
 
-// calculateEMA คำนวณ Exponential Moving Average
+// calculateEMA คำนวณ Exponential Moving Average (0 = ข้อมูลยังไม่พอ)
 func (ind *Indicators) calculateEMA(ohlcv []OHLCV, period int) []float64 {
-	ema := make([]float64, len(ohlcv))
-
-	if len(ohlcv) < period {
-		return ema
-	}
-
-	// คำนวณ SMA สำหรับค่าเริ่มต้น
-	sum := 0.0
-	for i := 0; i < period; i++ {
-		sum += ohlcv[i].Close
-	}
-	ema[period-1] = sum / float64(period)
-
-	// คำนวณ EMA
-	multiplier := 2.0 / float64(period+1)
-	for i := period; i < len(ohlcv); i++ {
-		ema[i] = (ohlcv[i].Close * multiplier) + (ema[i-1] * (1 - multiplier))
-	}
-
-	return ema
+	return indicators.Nz(indicators.EMA(closePrices(ohlcv), period))
 }
 
 // analyzeSignal วิเคราะห์ Signal และ Confidence
@@ -362,35 +396,28 @@ func (ind *Indicators) isPriceRejectFromEMA(ohlcv []OHLCV, ema100 []float64, cur
 	return false
 }
 
-// GetRSI ดึงข้อมูล RSI ของเหรียญ (คืนค่าย้อนหลัง timeframes ตาม period)
+// GetRSI ดึงแท่งเทียน interval ของเหรียญจาก market data source แล้วคำนวณ Wilder RSI(14)
+// คืนค่าย้อนหลัง timeframes ค่า เรียงจากเก่าไปใหม่ (ค่าสุดท้ายคือแท่งล่าสุด)
 func (ind *Indicators) GetRSI(symbol, interval string, timeframes int) ([]float64, error) {
-	// ต้องการข้อมูล OHLCV เพื่อคำนวณ RSI
-	// ดึงข้อมูล candlestick ย้อนหลัง (timeframes + 14 สำหรับ RSI period)
-	// requiredCandles := timeframes + 14  // TODO: ใช้เมื่อเชื่อมต่อ API จริง
-
-	// TODO: ใช้ GateClient ดึงข้อมูล OHLCV จริง
-	// สำหรับตอนนี้จำลองการคำนวณ RSI จากข้อมูลจริง
-
-	rsiValues := make([]float64, timeframes)
-
-	// จำลอง RSI ที่แปรผันตามเวลา (แทนค่าคงที่)
-	baseRSI := 50.0
-	for i := 0; i < timeframes; i++ {
-		// สร้างค่า RSI ที่แปรผันตามตำแหน่ง (เพื่อดูการเปลี่ยนแปลง)
-		variation := math.Sin(float64(i)*0.3) * 15.0 // ใช้ sine wave สำหรับการแปรผัน
-		rsiValue := baseRSI + variation
-
-		// จำกัดให้อยู่ในช่วง 0-100
-		if rsiValue > 100 {
-			rsiValue = 100
-		} else if rsiValue < 0 {
-			rsiValue = 0
-		}
+	if ind.marketData == nil {
+		return nil, fmt.Errorf("ยังไม่ได้กำหนด market data source สำหรับคำนวณ RSI")
+	}
+	if timeframes <= 0 {
+		return nil, fmt.Errorf("จำนวนค่า RSI ต้องมากกว่า 0 (ได้ %d)", timeframes)
+	}
 
-		rsiValues[timeframes-1-i] = rsiValue // เรียงจากเก่าไปใหม่
+	// Wilder RSI ขึ้นกับค่าเฉลี่ยสะสม จึงดึงแท่งเผื่อ warmup ให้ค่าลู่เข้าหาค่าบน exchange
+	required := timeframes + rsiPeriod
+	ohlcv, err := ind.marketData.GetOHLCV(symbol, interval, required+rsiWarmupBars)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถดึงข้อมูล %s %s สำหรับ RSI: %v", symbol, interval, err)
+	}
+	if len(ohlcv) < required {
+		return nil, fmt.Errorf("ข้อมูล %s %s ไม่พอคำนวณ RSI: ได้ %d แท่ง ต้องการอย่างน้อย %d", symbol, interval, len(ohlcv), required)
 	}
 
-	return rsiValues, nil
+	rsi := indicators.RSI(closePrices(ohlcv), rsiPeriod)
+	return rsi[len(rsi)-timeframes:], nil
 }
 
 // calculateRiskReward คำนวณ Risk-Reward Ratio
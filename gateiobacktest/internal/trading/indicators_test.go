@@ -0,0 +1,86 @@
+package trading
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gateio-trading-bot/internal/indicators"
+)
+
+// fakeMarketData MarketDataSource ที่คืน limit แท่งล่าสุดของ candles เหมือน exchange และจำพารามิเตอร์ที่ถูกเรียก
+type fakeMarketData struct {
+	candles []OHLCV
+	err     error
+
+	contract, interval string
+	limit              int
+}
+
+func (f *fakeMarketData) GetOHLCV(contract, interval string, limit int) ([]OHLCV, error) {
+	f.contract, f.interval, f.limit = contract, interval, limit
+	if f.err != nil {
+		return nil, f.err
+	}
+	if limit < len(f.candles) {
+		return f.candles[len(f.candles)-limit:], nil
+	}
+	return f.candles, nil
+}
+
+func TestGetRSIMatchesWilderRSI(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		candles int
+	}{
+		{"full warmup", 300},
+		{"partial warmup", 30}, // exchange คืนน้อยกว่าที่ขอแต่ยังพอสำหรับ 5 ค่า
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			source := &fakeMarketData{candles: syntheticCandles(tc.candles, 11)}
+			ind := NewIndicators()
+			ind.SetMarketDataSource(source)
+
+			got, err := ind.GetRSI("SOL_USDT", "4h", 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if source.contract != "SOL_USDT" || source.interval != "4h" || source.limit != 5+rsiPeriod+rsiWarmupBars {
+				t.Fatalf("GetOHLCV(%q, %q, %d), ต้องการ (SOL_USDT, 4h, %d)", source.contract, source.interval, source.limit, 5+rsiPeriod+rsiWarmupBars)
+			}
+
+			fetched := source.candles[max(0, len(source.candles)-source.limit):]
+			rsi := indicators.RSI(closePrices(fetched), rsiPeriod)
+			want := rsi[len(rsi)-5:]
+			if len(got) != 5 {
+				t.Fatalf("ได้ %d ค่า, ต้องการ 5", len(got))
+			}
+			for i := range want {
+				if !approx(got[i], want[i]) || got[i] < 0 || got[i] > 100 {
+					t.Fatalf("RSI[%d] = %v, ต้องการ %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetRSIErrors(t *testing.T) {
+	ind := NewIndicators()
+	if _, err := ind.GetRSI("SOL_USDT", "1h", 5); err == nil || !strings.Contains(err.Error(), "market data source") {
+		t.Fatalf("ไม่มี source: %v, ต้องการ error ว่ายังไม่ได้กำหนด market data source", err)
+	}
+
+	source := &fakeMarketData{candles: syntheticCandles(10, 11)}
+	ind.SetMarketDataSource(source)
+	if _, err := ind.GetRSI("SOL_USDT", "1h", 5); err == nil || !strings.Contains(err.Error(), "ได้ 10 แท่ง ต้องการอย่างน้อย 19") {
+		t.Fatalf("ข้อมูล 10 แท่ง: %v, ต้องการ error ข้อมูลไม่พอ", err)
+	}
+	if _, err := ind.GetRSI("SOL_USDT", "1h", 0); err == nil {
+		t.Fatal("timeframes = 0 ต้องคืน error")
+	}
+
+	source.err = errors.New("timeout")
+	if _, err := ind.GetRSI("SOL_USDT", "1h", 5); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("source error: %v, ต้องการส่งต่อ error ของ source", err)
+	}
+}
@@ -0,0 +1,259 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// EMAScoreMode วิธีให้คะแนนสัญญาณของ EMAScoreStrategy
+type EMAScoreMode string
+
+const (
+	EMAScoreConfidence EMAScoreMode = "confidence" // EMA เรียงตัวเป็นเงื่อนไขบังคับ แล้วบวก confidence จาก spread/volume/RSI (enhanced_triple_ema_1h เดิม)
+	EMAScoreChecklist  EMAScoreMode = "checklist"  // ผ่านอย่างน้อย 4 ใน 5 เงื่อนไข (real_market_backtest เดิม)
+)
+
+// EMAScoreStrategy กลยุทธ์ EMA(9,21,50) + RSI + Volume ที่ใช้ SL 2xATR และ R:R 1:2 ถือไม่เกิน 24 ชั่วโมง
+type EMAScoreStrategy struct {
+	Mode EMAScoreMode
+
+	signal     string // "LONG", "SHORT" หรือ "NEUTRAL"
+	confidence float64
+	atr        float64
+}
+
+// NewEMAScoreStrategy สร้างกลยุทธ์ EMA score ตามโหมดที่กำหนด
+func NewEMAScoreStrategy(mode EMAScoreMode) *EMAScoreStrategy {
+	return &EMAScoreStrategy{Mode: mode}
+}
+
+// Name ชื่อกลยุทธ์
+func (s *EMAScoreStrategy) Name() string {
+	if s.Mode == EMAScoreChecklist {
+		return "Real Market EMA Checklist"
+	}
+	return "Enhanced Triple EMA 1H"
+}
+
+// WarmupBars เริ่มจากแท่งที่ 50 เพื่อให้ EMA50 พร้อม
+func (s *EMAScoreStrategy) WarmupBars() int {
+	return 50
+}
+
+// Fees โหมด confidence ใช้ค่าธรรมเนียม Future 0.05% ต่อขาและหักขาเข้าตอนเปิด ตามโปรแกรมเดิม
+func (s *EMAScoreStrategy) Fees(bt *Backtester) FeeModel {
+	if s.Mode == EMAScoreChecklist {
+		return FeeModel{EntryRate: bt.commission, ExitRate: bt.commission}
+	}
+	return FeeModel{EntryRate: 0.0005, ExitRate: 0.0005, ChargeEntryOnOpen: true}
+}
+
+// OnBar วิเคราะห์ 50 แท่งล่าสุด
+func (s *EMAScoreStrategy) OnBar(bt *Backtester) {
+	s.signal, s.confidence, s.atr = "NEUTRAL", 0, 0
+	if bt.currentIndex < 50 {
+		return
+	}
+
+	data := bt.recentCandles(50)
+	ema9 := bt.calculateEMA(data, 9)
+	ema21 := bt.calculateEMA(data, 21)
+	ema50 := bt.calculateEMA(data, 50)
+	rsi := bt.calculateRSI(data, 14)
+	volumeMA := bt.calculateAvgVolume(data, 20)
+	s.atr = bt.calculateATR(data, 14)
+
+	price := bt.currentPrice
+	volumeOK := data[len(data)-1].Volume > volumeMA*1.2
+	spreadOK := math.Abs(ema9-ema50)/ema50*100 > 0.15
+
+	bullish := ema9 > ema21 && ema21 > ema50
+	bearish := ema9 < ema21 && ema21 < ema50
+	longPrice, shortPrice := price >= ema9*0.998, price <= ema9*1.002
+
+	if s.Mode == EMAScoreChecklist {
+		longScore := countTrue(bullish, longPrice, rsi >= 40 && rsi <= 80, volumeOK, spreadOK)
+		shortScore := countTrue(bearish, shortPrice, rsi >= 20 && rsi <= 60, volumeOK, spreadOK)
+		if longScore >= 4 {
+			s.signal, s.confidence = "LONG", float64(longScore)/5*100
+		} else if shortScore >= 4 {
+			s.signal, s.confidence = "SHORT", float64(shortScore)/5*100
+		}
+		return
+	}
+
+	if bullish && longPrice && rsi > 40 && rsi < 80 {
+		s.signal = "LONG"
+	} else if bearish && shortPrice && rsi > 20 && rsi < 60 {
+		s.signal = "SHORT"
+	}
+
+	s.confidence = 60
+	if spreadOK {
+		s.confidence += 15
+	}
+	if volumeOK {
+		s.confidence += 10
+	}
+	if (s.signal == "LONG" && rsi >= 50 && rsi <= 70) || (s.signal == "SHORT" && rsi >= 30 && rsi <= 50) {
+		s.confidence += 15
+	}
+}
+
+// EntrySignal เข้าเมื่อ confidence ถึง 80% พร้อม SL 2xATR และ TP 4xATR
+func (s *EMAScoreStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	if (s.signal != "LONG" && s.signal != "SHORT") || s.confidence < 80 || s.atr <= 0 {
+		return nil
+	}
+
+	stopLoss, takeProfit := atrStops(s.signal, bt.currentPrice, s.atr, 2.0, 2.0)
+	signal := &EntrySignal{
+		Side:       s.signal,
+		StopLoss:   stopLoss,
+		TakeProfit: takeProfit,
+		Reason:     fmt.Sprintf("%s %s (Conf: %.1f%%)", s.Name(), s.signal, s.confidence),
+	}
+	if s.Mode == EMAScoreConfidence {
+		signal.Leverage = 5.0
+	}
+	return signal
+}
+
+// PositionSize เสี่ยง 1% ของเงินทุน (โหมด confidence ใช้ 5x leverage และจำกัดเงินไม่เกิน 15%)
+func (s *EMAScoreStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	if s.Mode == EMAScoreConfidence {
+		return bt.leveragedQuantity(signal, 0.01, 5.0, 0.15)
+	}
+	return bt.leveragedQuantity(signal, 0.01, 1.0, 1.0)
+}
+
+// ExitSignal SL/TP, สัญญาณกลับทิศ (เฉพาะโหมด confidence ที่ ≥ 85%) และเวลาถือ 24 ชั่วโมง
+func (s *EMAScoreStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	if reason := closeStopExit(bt, pos); reason != "" {
+		return reason
+	}
+	if s.Mode == EMAScoreConfidence && s.confidence >= 85 &&
+		((pos.Side == "LONG" && s.signal == "SHORT") || (pos.Side == "SHORT" && s.signal == "LONG")) {
+		return "Signal Reversal"
+	}
+	if bt.currentTime.Sub(pos.EntryTime) >= 24*time.Hour {
+		return "Time Limit (24h)"
+	}
+	return ""
+}
+
+// countTrue จำนวนเงื่อนไขที่เป็นจริง
+func countTrue(conditions ...bool) int {
+	n := 0
+	for _, ok := range conditions {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// VolumeBreakoutEMAStrategy volume breakout ที่ได้รับการยืนยันจาก EMA(9,21,50) (จาก volume_breakout_ema เดิม)
+// SL 1%, TP 2%, ถือไม่เกิน 12 ชั่วโมง, ขนาด 0.5% ของเงินทุน
+type VolumeBreakoutEMAStrategy struct {
+	rules legacyRules
+
+	ema9, ema21         float64
+	prevEMA9, prevEMA21 float64
+	trendStrength       float64 // ระยะ EMA9-EMA50 (%) เมื่อเรียงตัว (ลบ = ขาลง, 0 = ไม่เรียงตัว)
+	volumeRatio         float64
+	bodyPct             float64
+}
+
+// NewVolumeBreakoutEMAStrategy สร้างกลยุทธ์ Volume Breakout + EMA Trend
+func NewVolumeBreakoutEMAStrategy() *VolumeBreakoutEMAStrategy {
+	return &VolumeBreakoutEMAStrategy{
+		rules: legacyRules{StopLossPct: 1, TakeProfitPct: 2, MaxHold: 12 * time.Hour, SizePct: 0.5},
+	}
+}
+
+// Name ชื่อกลยุทธ์
+func (s *VolumeBreakoutEMAStrategy) Name() string {
+	return "Volume Breakout + EMA Trend"
+}
+
+// WarmupBars รอให้ EMA50 พร้อม
+func (s *VolumeBreakoutEMAStrategy) WarmupBars() int {
+	return 50
+}
+
+// Fees คิดค่าคอมมิชชั่นของ backtester ทั้งขาเข้าและขาออกตอนปิด
+func (s *VolumeBreakoutEMAStrategy) Fees(bt *Backtester) FeeModel {
+	return FeeModel{EntryRate: bt.commission, ExitRate: bt.commission}
+}
+
+// OnBar คำนวณ EMA, trend strength และ volume ratio
+func (s *VolumeBreakoutEMAStrategy) OnBar(bt *Backtester) {
+	data := bt.recentCandles(150)
+	last := len(data) - 1
+	ema9 := bt.indicators.calculateEMA(data, 9)
+	ema21 := bt.indicators.calculateEMA(data, 21)
+	ema50 := bt.indicators.calculateEMA(data, 50)
+
+	s.ema9, s.ema21 = ema9[last], ema21[last]
+	s.prevEMA9, s.prevEMA21 = s.ema9, s.ema21
+	if last > 0 {
+		s.prevEMA9, s.prevEMA21 = ema9[last-1], ema21[last-1]
+	}
+
+	s.trendStrength = 0
+	if e50 := ema50[last]; e50 > 0 {
+		if s.ema9 > s.ema21 && s.ema21 > e50 {
+			s.trendStrength = (s.ema9 - e50) / e50 * 100
+		} else if s.ema9 < s.ema21 && s.ema21 < e50 {
+			s.trendStrength = -(e50 - s.ema9) / e50 * 100
+		}
+	}
+
+	s.volumeRatio = 0
+	if volumeMA := bt.calculateAvgVolume(data, 20); volumeMA > 0 {
+		s.volumeRatio = data[last].Volume / volumeMA
+	}
+	s.bodyPct = (data[last].Close - data[last].Open) / data[last].Open * 100
+}
+
+// EntrySignal volume ≥ 2x พร้อมแท่งเทียนยาว ≥ 1% ตามทิศ EMA และ confidence ≥ 75%
+func (s *VolumeBreakoutEMAStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	if s.volumeRatio < 2.0 {
+		return nil
+	}
+	confidence := math.Min(90.0, 60.0+math.Abs(s.trendStrength)+(s.volumeRatio-2.0)*10)
+	if confidence < 75 {
+		return nil
+	}
+
+	reason := fmt.Sprintf("Volume Breakout + EMA Trend (%.1f%% confidence)", confidence)
+	if s.bodyPct >= 1.0 && s.trendStrength > 1.0 && bt.currentPrice > s.ema21 {
+		return s.rules.signal(bt, "LONG", reason)
+	}
+	if s.bodyPct <= -1.0 && s.trendStrength < -1.0 && bt.currentPrice < s.ema21 {
+		return s.rules.signal(bt, "SHORT", reason)
+	}
+	return nil
+}
+
+// PositionSize notional 0.5% ของเงินทุน
+func (s *VolumeBreakoutEMAStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return s.rules.quantity(bt)
+}
+
+// ExitSignal SL/TP, เวลาถือ และ EMA9 ตัด EMA21 สวนทางหรือราคาหลุด EMA21 ขณะเทรนด์กลับ
+func (s *VolumeBreakoutEMAStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	if reason := s.rules.exit(bt, pos); reason != "" {
+		return reason
+	}
+	if pos.Side == "LONG" {
+		if (s.ema9 < s.ema21 && s.prevEMA9 >= s.prevEMA21) || (bt.currentPrice < s.ema21 && s.trendStrength < 0) {
+			return "EMA Trend Reversal"
+		}
+	} else if (s.ema9 > s.ema21 && s.prevEMA9 <= s.prevEMA21) || (bt.currentPrice > s.ema21 && s.trendStrength > 0) {
+		return "EMA Trend Reversal"
+	}
+	return ""
+}
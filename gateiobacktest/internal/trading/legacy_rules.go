@@ -0,0 +1,86 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+)
+
+// legacyRules กติกาความเสี่ยงแบบคงที่ของกลยุทธ์ที่ย้ายมาจากโปรแกรม standalone เดิม
+// SL/TP เป็น % จากราคาเข้า จำกัดเวลาถือตามเวลาของแท่งเทียน และขนาดเป็น notional % ของเงินทุน
+type legacyRules struct {
+	StopLossPct   float64       // ระยะ Stop Loss (%)
+	TakeProfitPct float64       // ระยะ Take Profit (%)
+	MaxHold       time.Duration // เวลาถือสูงสุด (0 = ไม่จำกัด)
+	SizePct       float64       // notional ต่อเทรดเป็น % ของเงินทุน (ไม่ใช้ leverage)
+}
+
+// signal สร้างสัญญาณเข้าที่ราคาปัจจุบันพร้อม SL/TP ตาม %
+func (r legacyRules) signal(bt *Backtester, side, reason string) *EntrySignal {
+	price := bt.currentPrice
+	stopLoss := price * (1 - r.StopLossPct/100)
+	takeProfit := price * (1 + r.TakeProfitPct/100)
+	if side == "SHORT" {
+		stopLoss = price * (1 + r.StopLossPct/100)
+		takeProfit = price * (1 - r.TakeProfitPct/100)
+	}
+	return &EntrySignal{Side: side, StopLoss: stopLoss, TakeProfit: takeProfit, Reason: reason}
+}
+
+// quantity ขนาด position ตาม notional % ของเงินทุน
+func (r legacyRules) quantity(bt *Backtester) float64 {
+	if bt.currentPrice <= 0 {
+		return 0
+	}
+	return bt.currentCapital * r.SizePct / 100 / bt.currentPrice
+}
+
+// exit ตรวจ SL/TP ที่ราคาปิด (กรณีปิดการ fill ระหว่างแท่ง) และเวลาถือสูงสุด
+func (r legacyRules) exit(bt *Backtester, pos *BacktestPosition) string {
+	if reason := closeStopExit(bt, pos); reason != "" {
+		return reason
+	}
+	if r.MaxHold > 0 && bt.currentTime.Sub(pos.EntryTime) >= r.MaxHold {
+		return fmt.Sprintf("Time Limit (%.0fh)", r.MaxHold.Hours())
+	}
+	return ""
+}
+
+// closeStopExit เหตุผลการปิดเมื่อราคาปิดแตะ SL/TP ของ position ("" = ยังไม่แตะ)
+func closeStopExit(bt *Backtester, pos *BacktestPosition) string {
+	price := bt.currentPrice
+	if pos.Side == "LONG" {
+		if pos.StopLoss > 0 && price <= pos.StopLoss {
+			return "Stop Loss Hit"
+		}
+		if pos.TakeProfit > 0 && price >= pos.TakeProfit {
+			return "Take Profit Hit"
+		}
+		return ""
+	}
+	if pos.StopLoss > 0 && price >= pos.StopLoss {
+		return "Stop Loss Hit"
+	}
+	if pos.TakeProfit > 0 && price <= pos.TakeProfit {
+		return "Take Profit Hit"
+	}
+	return ""
+}
+
+// recentCandles แท่งเทียนล่าสุดไม่เกิน n แท่งจนถึงแท่งปัจจุบัน
+func (bt *Backtester) recentCandles(n int) []OHLCV {
+	start := bt.currentIndex + 1 - n
+	if start < 0 {
+		start = 0
+	}
+	return bt.ohlcvData[start : bt.currentIndex+1]
+}
+
+// isGreen แท่งเทียนปิดสูงกว่าเปิด
+func isGreen(candle OHLCV) bool {
+	return candle.Close > candle.Open
+}
+
+// isRed แท่งเทียนปิดต่ำกว่าเปิด
+func isRed(candle OHLCV) bool {
+	return candle.Close < candle.Open
+}
@@ -0,0 +1,148 @@
+package trading
+
+import (
+	"math"
+	"time"
+)
+
+// SimpleSignal ตัวหาสัญญาณของกลยุทธ์อย่างง่าย (จาก simple_strategies เดิม) คืนทิศและเหตุผล ("" = ไม่มีสัญญาณ)
+type SimpleSignal func(bt *Backtester) (side, reason string)
+
+// SimpleSignalStrategy กลยุทธ์อย่างง่ายที่ใช้กติกาความเสี่ยงร่วมกัน:
+// SL 1%, TP 2%, ถือไม่เกิน 12 ชั่วโมง และเสี่ยง 0.5% ของเงินทุนต่อเทรด
+type SimpleSignalStrategy struct {
+	Signal SimpleSignal
+	name   string
+	rules  legacyRules
+}
+
+// NewSimpleSignalStrategy สร้างกลยุทธ์อย่างง่ายจากชื่อและตัวหาสัญญาณ
+func NewSimpleSignalStrategy(name string, signal SimpleSignal) *SimpleSignalStrategy {
+	return &SimpleSignalStrategy{
+		Signal: signal,
+		name:   name,
+		rules:  legacyRules{StopLossPct: 1, TakeProfitPct: 2, MaxHold: 12 * time.Hour},
+	}
+}
+
+// Name ชื่อกลยุทธ์
+func (s *SimpleSignalStrategy) Name() string {
+	return s.name
+}
+
+// WarmupBars เริ่มจากแท่งที่ 20
+func (s *SimpleSignalStrategy) WarmupBars() int {
+	return 20
+}
+
+// Fees คิดค่าคอมมิชชั่นของ backtester ทั้งขาเข้าและขาออกตอนปิด
+func (s *SimpleSignalStrategy) Fees(bt *Backtester) FeeModel {
+	return FeeModel{EntryRate: bt.commission, ExitRate: bt.commission}
+}
+
+// OnBar ไม่มี state ต่อแท่ง
+func (s *SimpleSignalStrategy) OnBar(bt *Backtester) {}
+
+// EntrySignal ใช้ตัวหาสัญญาณพร้อม SL/TP ตาม %
+func (s *SimpleSignalStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	side, reason := s.Signal(bt)
+	if side == "" {
+		return nil
+	}
+	return s.rules.signal(bt, side, reason)
+}
+
+// PositionSize เสี่ยง 0.5% ของเงินทุนถึง SL (notional ไม่เกินเงินทุน)
+func (s *SimpleSignalStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return bt.leveragedQuantity(signal, 0.005, 1.0, 1.0)
+}
+
+// ExitSignal SL/TP และเวลาถือ 12 ชั่วโมง
+func (s *SimpleSignalStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	return s.rules.exit(bt, pos)
+}
+
+// RSIBounceSignal LONG เมื่อ RSI ตีกลับจากใต้ 30 (ยังไม่เกิน 40), SHORT เมื่อย่อจากเหนือ 70 (ยังไม่ต่ำกว่า 60)
+func RSIBounceSignal(bt *Backtester) (string, string) {
+	if bt.currentIndex < 16 {
+		return "", ""
+	}
+	data := bt.recentCandles(16)
+	rsi := bt.calculateRSI(data, 14)
+	prevRSI := bt.calculateRSI(data[:len(data)-1], 14)
+
+	if prevRSI < 30 && rsi > prevRSI && rsi < 40 {
+		return "LONG", "RSI Oversold Bounce"
+	}
+	if prevRSI > 70 && rsi < prevRSI && rsi > 60 {
+		return "SHORT", "RSI Overbought Decline"
+	}
+	return "", ""
+}
+
+// VolumeBreakoutSignal volume มากกว่า 2 เท่าของค่าเฉลี่ย 10 แท่ง และราคาปิดเปลี่ยนเกิน 1%
+func VolumeBreakoutSignal(bt *Backtester) (string, string) {
+	if bt.currentIndex < 10 {
+		return "", ""
+	}
+	data := bt.recentCandles(10)
+	current, prev := data[len(data)-1], bt.ohlcvData[bt.currentIndex-1]
+
+	if current.Volume <= bt.calculateAvgVolume(data, 10)*2.0 {
+		return "", ""
+	}
+	change := (current.Close - prev.Close) / prev.Close * 100
+	if change > 1.0 {
+		return "LONG", "Volume Breakout Up"
+	}
+	if change < -1.0 {
+		return "SHORT", "Volume Breakout Down"
+	}
+	return "", ""
+}
+
+// PriceActionSignal Bullish/Bearish Engulfing และ Hammer/Doji ที่ทำ low ต่ำกว่า 2 แท่งก่อนหน้า
+func PriceActionSignal(bt *Backtester) (string, string) {
+	if bt.currentIndex < 2 {
+		return "", ""
+	}
+	current := bt.ohlcvData[bt.currentIndex]
+	prev1 := bt.ohlcvData[bt.currentIndex-1]
+	prev2 := bt.ohlcvData[bt.currentIndex-2]
+
+	if isRed(prev1) && isGreen(current) && current.Open < prev1.Close && current.Close > prev1.Open {
+		return "LONG", "Bullish Engulfing"
+	}
+	if isGreen(prev1) && isRed(current) && current.Open > prev1.Close && current.Close < prev1.Open {
+		return "SHORT", "Bearish Engulfing"
+	}
+
+	totalSize := current.High - current.Low
+	if totalSize > 0 && math.Abs(current.Close-current.Open)/totalSize < 0.3 &&
+		current.Low < prev1.Low && current.Low < prev2.Low {
+		return "LONG", "Hammer/Doji Reversal"
+	}
+	return "", ""
+}
+
+// MeanReversionSignal เทรดกลับหา MA20 เมื่อราคาห่างเกิน 2%
+func MeanReversionSignal(bt *Backtester) (string, string) {
+	if bt.currentIndex < 20 {
+		return "", ""
+	}
+	data := bt.recentCandles(20)
+	sum := 0.0
+	for _, candle := range data {
+		sum += candle.Close
+	}
+	ma20 := sum / float64(len(data))
+
+	deviation := (bt.currentPrice - ma20) / ma20 * 100
+	if deviation < -2.0 {
+		return "LONG", "Mean Reversion Up"
+	}
+	if deviation > 2.0 {
+		return "SHORT", "Mean Reversion Down"
+	}
+	return "", ""
+}
@@ -0,0 +1,236 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"gateio-trading-bot/internal/indicators"
+)
+
+// PivotRSIStrategy กลยุทธ์ Pivot SuperTrend + EMA100 ที่ออกด้วย RSI (จาก pivot_supertrend_strategy เดิม)
+// SL 3%, TP 5%, ถือไม่เกิน 24 ชั่วโมง, ขนาด 1% ของเงินทุน
+type PivotRSIStrategy struct {
+	TrendEntry bool // true = เข้าตามเทรนด์ (แท่งก่อนหน้าปิดฝั่ง EMA100), false = เข้าเฉพาะแท่งที่เทรนด์พลิก
+
+	rules     legacyRules
+	analysis  *SuperTrendAnalysis
+	prevTrend int
+	prevEMA   float64
+	rsi       float64
+}
+
+// NewPivotRSIStrategy สร้างกลยุทธ์ Pivot SuperTrend + RSI exit
+func NewPivotRSIStrategy(trendEntry bool) *PivotRSIStrategy {
+	return &PivotRSIStrategy{
+		TrendEntry: trendEntry,
+		rules:      legacyRules{StopLossPct: 3, TakeProfitPct: 5, MaxHold: 24 * time.Hour, SizePct: 1},
+	}
+}
+
+// Name ชื่อกลยุทธ์
+func (s *PivotRSIStrategy) Name() string {
+	if s.TrendEntry {
+		return "Pivot SuperTrend Trend + EMA100 + RSI Exit"
+	}
+	return "Pivot SuperTrend Flip + EMA100 + RSI Exit"
+}
+
+// WarmupBars รอให้ EMA100 พร้อม
+func (s *PivotRSIStrategy) WarmupBars() int {
+	return 100
+}
+
+// Fees คิดค่าคอมมิชชั่นของ backtester ทั้งขาเข้าและขาออกตอนปิด
+func (s *PivotRSIStrategy) Fees(bt *Backtester) FeeModel {
+	return FeeModel{EntryRate: bt.commission, ExitRate: bt.commission}
+}
+
+// OnBar วิเคราะห์ Pivot SuperTrend และ RSI โดยเก็บค่าของแท่งก่อนหน้าไว้ตรวจการพลิกเทรนด์
+func (s *PivotRSIStrategy) OnBar(bt *Backtester) {
+	s.prevTrend, s.prevEMA = 0, 0
+	if s.analysis != nil {
+		s.prevTrend, s.prevEMA = s.analysis.Trend, s.analysis.EMA100
+	}
+	s.analysis = bt.analyzeMarket()
+	s.rsi = bt.calculateRSI(bt.recentCandles(rsiPeriod+rsiWarmupBars), rsiPeriod)
+}
+
+// EntrySignal LONG เมื่อเทรนด์ขึ้น + แท่งก่อนหน้าเขียว + อยู่เหนือ EMA100 (SHORT กลับกัน)
+func (s *PivotRSIStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	analysis := s.analysis
+	if analysis == nil || analysis.EMA100 == 0 || bt.currentIndex < 1 {
+		return nil
+	}
+	prev := bt.ohlcvData[bt.currentIndex-1]
+
+	long, short := false, false
+	if s.TrendEntry {
+		long = analysis.Trend == 1 && isGreen(prev) && s.prevEMA > 0 && prev.Close > s.prevEMA
+		short = analysis.Trend == -1 && isRed(prev) && s.prevEMA > 0 && prev.Close < s.prevEMA
+	} else {
+		long = analysis.Trend == 1 && s.prevTrend == -1 && isGreen(prev) && bt.currentPrice > analysis.EMA100
+		short = analysis.Trend == -1 && s.prevTrend == 1 && isRed(prev) && bt.currentPrice < analysis.EMA100
+	}
+
+	switch {
+	case long:
+		return s.rules.signal(bt, "LONG", fmt.Sprintf("LONG Signal (RSI: %.1f, EMA100: %.2f)", s.rsi, analysis.EMA100))
+	case short:
+		return s.rules.signal(bt, "SHORT", fmt.Sprintf("SHORT Signal (RSI: %.1f, EMA100: %.2f)", s.rsi, analysis.EMA100))
+	}
+	return nil
+}
+
+// PositionSize notional 1% ของเงินทุน
+func (s *PivotRSIStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return s.rules.quantity(bt)
+}
+
+// ExitSignal SL/TP, เวลาถือ และ RSI 70/30
+func (s *PivotRSIStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	if reason := s.rules.exit(bt, pos); reason != "" {
+		return reason
+	}
+	if (pos.Side == "LONG" && s.rsi > 70) || (pos.Side == "SHORT" && s.rsi < 30) {
+		return fmt.Sprintf("RSI Exit (RSI: %.1f)", s.rsi)
+	}
+	return ""
+}
+
+// SuperTrendEMAStrategy กลยุทธ์ SuperTrend มาตรฐาน + EMA filter
+// (จาก simple_supertrend, simplified_supertrend และ multi_ema_supertrend เดิม)
+type SuperTrendEMAStrategy struct {
+	name        string
+	ATRPeriod   int
+	WilderATR   bool    // ใช้ ATR แบบ Wilder แทน SMA ของ True Range
+	Factor      float64 // ตัวคูณ ATR ของ SuperTrend
+	EMAPeriod   int
+	FlipEntry   bool    // true = เข้าเฉพาะแท่งที่เทรนด์พลิกและแท่งปัจจุบันตามทิศ, false = ตามเทรนด์และแท่งก่อนหน้าตามทิศ
+	EMABreakPct float64 // ออกเมื่อราคาหลุด EMA ฝั่งตรงข้ามเกิน % นี้ (0 = ไม่ใช้)
+
+	rules     legacyRules
+	trend     int
+	prevTrend int
+	ema       float64
+	rsi       float64
+}
+
+// NewSimpleSuperTrendStrategy SuperTrend(10, 2) พลิกเทรนด์ + EMA21, SL 2% TP 3% ถือไม่เกิน 12 ชั่วโมง
+func NewSimpleSuperTrendStrategy() *SuperTrendEMAStrategy {
+	return &SuperTrendEMAStrategy{
+		name:      "Simple SuperTrend + EMA21",
+		ATRPeriod: 10,
+		Factor:    2,
+		EMAPeriod: 21,
+		FlipEntry: true,
+		rules:     legacyRules{StopLossPct: 2, TakeProfitPct: 3, MaxHold: 12 * time.Hour, SizePct: 1},
+	}
+}
+
+// NewSimplifiedSuperTrendStrategy SuperTrend(10, 2.5) ตามเทรนด์ + EMA50, SL 2% TP 3% ถือไม่เกิน 18 ชั่วโมง
+func NewSimplifiedSuperTrendStrategy() *SuperTrendEMAStrategy {
+	return &SuperTrendEMAStrategy{
+		name:      "Simplified SuperTrend + EMA50",
+		ATRPeriod: 10,
+		Factor:    2.5,
+		EMAPeriod: 50,
+		rules:     legacyRules{StopLossPct: 2, TakeProfitPct: 3, MaxHold: 18 * time.Hour, SizePct: 1},
+	}
+}
+
+// NewMultiEMASuperTrendStrategy SuperTrend(14 Wilder, 2.5) ตามเทรนด์ + EMA100, SL 3% TP 5% ถือไม่เกิน 24 ชั่วโมง
+func NewMultiEMASuperTrendStrategy() *SuperTrendEMAStrategy {
+	return &SuperTrendEMAStrategy{
+		name:        "Multi-EMA SuperTrend + EMA100",
+		ATRPeriod:   14,
+		WilderATR:   true,
+		Factor:      2.5,
+		EMAPeriod:   100,
+		EMABreakPct: 2,
+		rules:       legacyRules{StopLossPct: 3, TakeProfitPct: 5, MaxHold: 24 * time.Hour, SizePct: 1},
+	}
+}
+
+// Name ชื่อกลยุทธ์
+func (s *SuperTrendEMAStrategy) Name() string {
+	return s.name
+}
+
+// WarmupBars รอให้ EMA และ ATR พร้อม
+func (s *SuperTrendEMAStrategy) WarmupBars() int {
+	if s.EMAPeriod > s.ATRPeriod {
+		return s.EMAPeriod
+	}
+	return s.ATRPeriod + 1
+}
+
+// Fees คิดค่าคอมมิชชั่นของ backtester ทั้งขาเข้าและขาออกตอนปิด
+func (s *SuperTrendEMAStrategy) Fees(bt *Backtester) FeeModel {
+	return FeeModel{EntryRate: bt.commission, ExitRate: bt.commission}
+}
+
+// OnBar คำนวณ SuperTrend, EMA และ RSI จากแท่งย้อนหลัง
+func (s *SuperTrendEMAStrategy) OnBar(bt *Backtester) {
+	data := bt.recentCandles(s.EMAPeriod + 100)
+	bars := toBars(data)
+	atr := indicators.ATR(bars, s.ATRPeriod)
+	if !s.WilderATR {
+		atr = indicators.SMA(indicators.TrueRange(bars), s.ATRPeriod)
+	}
+	_, trend := indicators.SuperTrendFromATR(bars, atr, s.Factor)
+
+	last := len(data) - 1
+	s.trend = trend[last]
+	s.prevTrend = 0
+	if last > 0 {
+		s.prevTrend = trend[last-1]
+	}
+	s.ema = bt.indicators.calculateEMA(data, s.EMAPeriod)[last]
+	s.rsi = bt.calculateRSI(bt.recentCandles(rsiPeriod+rsiWarmupBars), rsiPeriod)
+}
+
+// EntrySignal เข้าตามทิศ SuperTrend เมื่อราคาอยู่ฝั่งเดียวกับ EMA และแท่งเทียนยืนยันทิศ
+func (s *SuperTrendEMAStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	if s.ema == 0 || bt.currentIndex < 1 {
+		return nil
+	}
+	candle := bt.ohlcvData[bt.currentIndex]
+	if !s.FlipEntry {
+		candle = bt.ohlcvData[bt.currentIndex-1]
+	}
+	flipped := !s.FlipEntry || s.prevTrend != s.trend
+
+	if s.trend == 1 && flipped && isGreen(candle) && bt.currentPrice > s.ema {
+		return s.rules.signal(bt, "LONG", fmt.Sprintf("SuperTrend LONG (EMA%d: %.2f, RSI: %.1f)", s.EMAPeriod, s.ema, s.rsi))
+	}
+	if s.trend == -1 && flipped && isRed(candle) && bt.currentPrice < s.ema {
+		return s.rules.signal(bt, "SHORT", fmt.Sprintf("SuperTrend SHORT (EMA%d: %.2f, RSI: %.1f)", s.EMAPeriod, s.ema, s.rsi))
+	}
+	return nil
+}
+
+// PositionSize notional 1% ของเงินทุน
+func (s *SuperTrendEMAStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return s.rules.quantity(bt)
+}
+
+// ExitSignal SL/TP, เวลาถือ, RSI 70/30, SuperTrend กลับทิศ และราคาหลุด EMA
+func (s *SuperTrendEMAStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	if reason := s.rules.exit(bt, pos); reason != "" {
+		return reason
+	}
+
+	long := pos.Side == "LONG"
+	if (long && s.rsi > 70) || (!long && s.rsi < 30) {
+		return fmt.Sprintf("RSI Exit (RSI: %.1f)", s.rsi)
+	}
+	if (long && s.trend == -1) || (!long && s.trend == 1) {
+		return "SuperTrend Reversal"
+	}
+	if s.EMABreakPct > 0 && s.ema > 0 {
+		if (long && bt.currentPrice < s.ema*(1-s.EMABreakPct/100)) || (!long && bt.currentPrice > s.ema*(1+s.EMABreakPct/100)) {
+			return fmt.Sprintf("EMA%d Break (%.0f%%)", s.EMAPeriod, s.EMABreakPct)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,388 @@
+package trading
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// IndicatorReporter กลยุทธ์ที่เปิดเผยค่า indicators ของแท่งปัจจุบัน (หลัง OnBar) ให้ตัวตรวจ look-ahead เทียบได้
+type IndicatorReporter interface {
+	IndicatorValues(bt *Backtester) map[string]float64
+}
+
+// IndicatorSeriesFunc คำนวณ indicators ทั้ง series (คืน ชื่อ → ค่าต่อแท่ง ยาวเท่า data)
+type IndicatorSeriesFunc func(data []OHLCV) map[string][]float64
+
+// LookAheadConfig การตั้งค่าตัวตรวจ look-ahead bias
+type LookAheadConfig struct {
+	Start     int     `json:"start"`     // index แรกที่ตรวจ (0 = WarmupBars ของกลยุทธ์)
+	End       int     `json:"end"`       // index สุดท้าย (ไม่รวม, 0 = จนจบข้อมูล)
+	Tolerance float64 `json:"tolerance"` // ค่าต่างสัมพัทธ์ที่ยอมรับได้ (0 = 1e-9)
+}
+
+// LookAheadDiff แท่งที่ค่าจากข้อมูลเต็มไม่ตรงกับค่าจากข้อมูลที่ตัดถึงแท่งนั้น
+type LookAheadDiff struct {
+	Index     int       `json:"index"`
+	Time      time.Time `json:"time"`
+	Field     string    `json:"field"`
+	Full      string    `json:"full"`      // ค่าเมื่อเห็นข้อมูลทั้งหมด
+	Truncated string    `json:"truncated"` // ค่าเมื่อเห็นข้อมูลถึงแท่งนี้เท่านั้น
+}
+
+// LookAheadReport ผลการตรวจ look-ahead bias
+type LookAheadReport struct {
+	Name        string          `json:"name"`
+	BarsChecked int             `json:"bars_checked"`
+	BarsLeaking int             `json:"bars_leaking"` // จำนวนแท่งที่มีค่าต่างอย่างน้อยหนึ่งค่า
+	FieldCounts map[string]int  `json:"field_counts"` // จำนวนแท่งที่ต่างแยกตามค่า
+	Diffs       []LookAheadDiff `json:"diffs"`
+}
+
+// HasLeak มีการใช้ข้อมูลอนาคตหรือไม่
+func (r *LookAheadReport) HasLeak() bool {
+	return r.BarsLeaking > 0
+}
+
+// lookAheadBar ค่าที่บันทึกได้ของแท่งหนึ่ง
+type lookAheadBar struct {
+	signal string // "-" = ไม่มีสัญญาณ
+	exit   string // เหตุผลออกของ position สมมติฝั่ง LONG และ SHORT
+	values map[string]float64
+}
+
+// DetectLookAhead รันกลยุทธ์สองรอบเพื่อหาการใช้ข้อมูลอนาคต:
+// รอบแรกเห็นข้อมูลทั้ง series รอบสองตัดข้อมูลให้จบที่แท่งปัจจุบันทุกแท่ง
+// แล้วรายงานทุกแท่งที่สัญญาณเข้า (side/SL/TP), สัญญาณออกของ position สมมติ หรือค่า IndicatorValues ไม่ตรงกัน
+// newStrategy ต้องสร้าง instance ใหม่ทุกครั้ง เพราะสองรอบต้องเริ่มจาก state ว่างเหมือนกัน
+func DetectLookAhead(symbol string, data []OHLCV, newStrategy func() Strategy, config LookAheadConfig) (*LookAheadReport, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาสำหรับตรวจ look-ahead")
+	}
+
+	strategy := newStrategy()
+	if config.Start <= 0 {
+		config.Start = strategy.WarmupBars()
+	}
+	if config.End <= 0 || config.End > len(data) {
+		config.End = len(data)
+	}
+	if config.Start >= config.End {
+		return nil, fmt.Errorf("ข้อมูล %d แท่งไม่พอสำหรับเริ่มตรวจที่แท่ง %d", len(data), config.Start)
+	}
+
+	full := probeStrategy(symbol, data, strategy, config, false)
+	truncated := probeStrategy(symbol, data, newStrategy(), config, true)
+
+	report := newLookAheadReport(strategy.Name())
+	for i := config.Start; i < config.End; i++ {
+		a, b := full[i-config.Start], truncated[i-config.Start]
+		var diffs []LookAheadDiff
+		if a.signal != b.signal {
+			diffs = append(diffs, LookAheadDiff{Field: "signal", Full: a.signal, Truncated: b.signal})
+		}
+		if a.exit != b.exit {
+			diffs = append(diffs, LookAheadDiff{Field: "exit", Full: a.exit, Truncated: b.exit})
+		}
+		diffs = append(diffs, compareValues(a.values, b.values, config.Tolerance)...)
+		report.add(i, time.Unix(data[i].Timestamp, 0), diffs)
+	}
+	return report, nil
+}
+
+// probeStrategy เรียก OnBar, EntrySignal และ ExitSignal ทุกแท่งโดยไม่เปิด position จริง
+// ExitSignal ถูกถามด้วย position สมมติทั้ง LONG และ SHORT ที่เข้าที่ราคาปิดของแท่งแรกที่ตรวจ (SL/TP ห่าง 2%)
+// truncate = ให้กลยุทธ์เห็นข้อมูลถึงแท่งปัจจุบันเท่านั้น
+func probeStrategy(symbol string, data []OHLCV, strategy Strategy, config LookAheadConfig, truncate bool) []lookAheadBar {
+	bt, _ := NewBacktesterSimple(symbol, 0, 1000)
+	bt.SetLogOutput(io.Discard)
+	bt.fees = strategy.Fees(bt)
+	reporter, _ := strategy.(IndicatorReporter)
+	entry := data[config.Start]
+
+	bars := make([]lookAheadBar, 0, config.End-config.Start)
+	for i := config.Start; i < config.End; i++ {
+		bt.ohlcvData = data
+		if truncate {
+			bt.ohlcvData = data[:i+1]
+		}
+		bt.currentIndex = i
+		bt.currentTime = time.Unix(data[i].Timestamp, 0)
+		bt.currentPrice = data[i].Close
+
+		bar := probeBar(bt, strategy, reporter)
+		bar.exit = probeExit(bt, strategy, "LONG", entry) + " | " + probeExit(bt, strategy, "SHORT", entry)
+		bars = append(bars, bar)
+	}
+	return bars
+}
+
+// probeExit เหตุผลออกของ position สมมติฝั่ง side ("-" = ถือต่อ, panic ถือเป็นค่าหนึ่งเหมือนสัญญาณเข้า)
+// position ถูกสร้างใหม่ทุกแท่งและถอดออกหลังถาม เพื่อไม่ให้ผลของแท่งหนึ่งค้างไปแท่งถัดไป
+func probeExit(bt *Backtester, strategy Strategy, side string, entry OHLCV) (reason string) {
+	direction := 1.0
+	if side == "SHORT" {
+		direction = -1
+	}
+	pos := &BacktestPosition{
+		Symbol:     bt.symbol,
+		Side:       side,
+		EntryTime:  time.Unix(entry.Timestamp, 0),
+		EntryPrice: entry.Close,
+		Quantity:   1,
+		StopLoss:   entry.Close * (1 - 0.02*direction),
+		TakeProfit: entry.Close * (1 + 0.02*direction),
+	}
+	bt.position = pos
+	defer func() {
+		bt.position = nil
+		if r := recover(); r != nil {
+			reason = fmt.Sprintf("panic: %v", r)
+		}
+	}()
+
+	if reason = strategy.ExitSignal(bt, pos); reason == "" {
+		reason = "-"
+	}
+	return reason
+}
+
+// probeBar บันทึกสัญญาณและ indicators ของแท่งเดียว
+// panic (เช่น index เกินข้อมูลที่ตัดไว้) ถือเป็นสัญญาณของการอ่านข้อมูลอนาคต
+func probeBar(bt *Backtester, strategy Strategy, reporter IndicatorReporter) (bar lookAheadBar) {
+	bar.signal = "-"
+	defer func() {
+		if r := recover(); r != nil {
+			bar.signal = fmt.Sprintf("panic: %v", r)
+		}
+	}()
+
+	strategy.OnBar(bt)
+	if signal := strategy.EntrySignal(bt); signal != nil {
+		bar.signal = fmt.Sprintf("%s SL=%.8g TP=%.8g", signal.Side, signal.StopLoss, signal.TakeProfit)
+	}
+	if reporter != nil {
+		bar.values = reporter.IndicatorValues(bt)
+	}
+	return bar
+}
+
+// DetectIndicatorLookAhead ตรวจฟังก์ชันที่คำนวณ indicators ทั้ง series ล่วงหน้า (เช่น pre-calculate ตอนโหลดข้อมูล)
+// โดยเทียบค่าที่แท่ง i จากการคำนวณบนข้อมูลทั้งหมด กับการคำนวณบน data[:i+1]
+// ใช้เวลา O(n²) จึงควรจำกัดช่วงด้วย Start/End
+func DetectIndicatorLookAhead(name string, data []OHLCV, compute IndicatorSeriesFunc, config LookAheadConfig) (*LookAheadReport, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาสำหรับตรวจ look-ahead")
+	}
+	if config.End <= 0 || config.End > len(data) {
+		config.End = len(data)
+	}
+	if config.Start < 0 || config.Start >= config.End {
+		return nil, fmt.Errorf("ช่วงตรวจไม่ถูกต้อง: %d-%d", config.Start, config.End)
+	}
+
+	full := compute(data)
+	report := newLookAheadReport(name)
+	for i := config.Start; i < config.End; i++ {
+		truncated := compute(data[:i+1])
+		report.add(i, time.Unix(data[i].Timestamp, 0),
+			compareValues(seriesAt(full, i), seriesAt(truncated, i), config.Tolerance))
+	}
+	return report, nil
+}
+
+// seriesAt ค่าของทุก indicator ที่แท่ง i (ข้ามชื่อที่ series สั้นกว่า)
+func seriesAt(series map[string][]float64, i int) map[string]float64 {
+	values := make(map[string]float64, len(series))
+	for name, s := range series {
+		if i < len(s) {
+			values[name] = s[i]
+		}
+	}
+	return values
+}
+
+// compareValues เทียบค่า indicators สองชุด (ชื่อที่มีฝั่งเดียวถือว่าต่าง)
+func compareValues(full, truncated map[string]float64, tolerance float64) []LookAheadDiff {
+	if tolerance <= 0 {
+		tolerance = 1e-9
+	}
+
+	names := make([]string, 0, len(full)+len(truncated))
+	for name := range full {
+		names = append(names, name)
+	}
+	for name := range truncated {
+		if _, ok := full[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []LookAheadDiff
+	for _, name := range names {
+		a, okA := full[name]
+		b, okB := truncated[name]
+		if okA && okB && valuesEqual(a, b, tolerance) {
+			continue
+		}
+		diff := LookAheadDiff{Field: name, Full: "-", Truncated: "-"}
+		if okA {
+			diff.Full = fmt.Sprintf("%.8g", a)
+		}
+		if okB {
+			diff.Truncated = fmt.Sprintf("%.8g", b)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// valuesEqual เทียบ float แบบสัมพัทธ์ (NaN เท่ากับ NaN)
+func valuesEqual(a, b, tolerance float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	return math.Abs(a-b) <= tolerance*scale
+}
+
+func newLookAheadReport(name string) *LookAheadReport {
+	return &LookAheadReport{Name: name, FieldCounts: make(map[string]int)}
+}
+
+// add บันทึกผลของแท่งหนึ่ง
+func (r *LookAheadReport) add(index int, t time.Time, diffs []LookAheadDiff) {
+	r.BarsChecked++
+	if len(diffs) == 0 {
+		return
+	}
+	r.BarsLeaking++
+	for _, d := range diffs {
+		d.Index = index
+		d.Time = t
+		r.FieldCounts[d.Field]++
+		r.Diffs = append(r.Diffs, d)
+	}
+}
+
+// PrintSummary แสดงผลการตรวจ look-ahead bias (แสดงรายละเอียดไม่เกิน limit รายการ, 0 = ทั้งหมด)
+func (r *LookAheadReport) PrintSummary(limit int) {
+	fmt.Printf("\n🔮 ===== ตรวจ Look-Ahead Bias: %s =====\n", r.Name)
+	fmt.Printf("📊 ตรวจ %d แท่ง | พบค่าต่าง %d แท่ง\n", r.BarsChecked, r.BarsLeaking)
+	if !r.HasLeak() {
+		fmt.Println("✅ ไม่พบการใช้ข้อมูลอนาคต")
+		return
+	}
+
+	fields := make([]string, 0, len(r.FieldCounts))
+	for field := range r.FieldCounts {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		fmt.Printf("   ⚠️ %-20s %d แท่ง\n", field, r.FieldCounts[field])
+	}
+
+	fmt.Printf("\n%-8s %-17s %-20s %-24s %-24s\n", "Index", "เวลา", "ค่า", "ข้อมูลเต็ม", "ข้อมูลถึงแท่งนี้")
+	for i, d := range r.Diffs {
+		if limit > 0 && i >= limit {
+			fmt.Printf("... อีก %d รายการ\n", len(r.Diffs)-limit)
+			break
+		}
+		fmt.Printf("%-8d %-17s %-20s %-24s %-24s\n",
+			d.Index, d.Time.UTC().Format("2006-01-02 15:04"), d.Field, d.Full, d.Truncated)
+	}
+}
+
+// analysisValues ค่า indicators หลักจากผลวิเคราะห์ (nil = ยังไม่มีผลวิเคราะห์)
+func analysisValues(analysis *SuperTrendAnalysis) map[string]float64 {
+	if analysis == nil {
+		return nil
+	}
+	return map[string]float64{
+		"trend":       float64(analysis.Trend),
+		"supertrend":  analysis.SuperTrendValue,
+		"ema":         analysis.EMA100,
+		"atr":         analysis.ATR,
+		"confidence":  analysis.Confidence,
+		"risk_reward": analysis.RiskRewardRatio,
+	}
+}
+
+// IndicatorValues ค่า indicators ของแท่งปัจจุบันสำหรับตรวจ look-ahead
+func (s *PivotSuperTrendStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	return analysisValues(bt.analyzeMarket())
+}
+
+// IndicatorValues ค่า indicators ของแท่งปัจจุบันสำหรับตรวจ look-ahead
+func (s *AggressiveStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	return analysisValues(s.analysis)
+}
+
+// IndicatorValues ค่า indicators ของแท่งปัจจุบันสำหรับตรวจ look-ahead
+func (s *TripleEMA1HStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	return analysisValues(s.analysis)
+}
+
+// IndicatorValues ค่า indicators ของแท่งปัจจุบันสำหรับตรวจ look-ahead
+func (s *TripleEMA15mStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	return analysisValues(s.analysis)
+}
+
+// IndicatorValues ค่า indicators ของแท่งปัจจุบันสำหรับตรวจ look-ahead
+func (s *EMAScoreStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	return map[string]float64{
+		"direction":  signalDirection(s.signal),
+		"confidence": s.confidence,
+		"atr":        s.atr,
+	}
+}
+
+// IndicatorValues ค่า indicators ของแท่งปัจจุบันสำหรับตรวจ look-ahead
+func (s *VolumeBreakoutEMAStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	return map[string]float64{
+		"ema9":           s.ema9,
+		"ema21":          s.ema21,
+		"prev_ema9":      s.prevEMA9,
+		"prev_ema21":     s.prevEMA21,
+		"trend_strength": s.trendStrength,
+		"volume_ratio":   s.volumeRatio,
+		"body_pct":       s.bodyPct,
+	}
+}
+
+// IndicatorValues ค่า indicators ของแท่งปัจจุบันสำหรับตรวจ look-ahead
+func (s *PivotRSIStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	values := analysisValues(s.analysis)
+	if values == nil {
+		values = make(map[string]float64)
+	}
+	values["prev_trend"] = float64(s.prevTrend)
+	values["prev_ema"] = s.prevEMA
+	values["rsi"] = s.rsi
+	return values
+}
+
+// IndicatorValues ค่า indicators ของแท่งปัจจุบันสำหรับตรวจ look-ahead
+func (s *SuperTrendEMAStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	return map[string]float64{
+		"trend":      float64(s.trend),
+		"prev_trend": float64(s.prevTrend),
+		"ema":        s.ema,
+		"rsi":        s.rsi,
+	}
+}
+
+// signalDirection แปลงทิศสัญญาณเป็นตัวเลข (LONG = 1, SHORT = -1, อื่นๆ = 0)
+func signalDirection(side string) float64 {
+	switch side {
+	case "LONG":
+		return 1
+	case "SHORT":
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,225 @@
+package trading
+
+import (
+	"strings"
+	"testing"
+
+	"gateio-trading-bot/internal/indicators"
+)
+
+// peekStrategy กลยุทธ์ที่จงใจใช้ข้อมูลอนาคต: เข้า LONG เมื่อแท่งถัดไปปิดสูงกว่า
+// และรายงาน SMA แบบกึ่งกลาง (แท่ง i-2 ถึง i+2) ซึ่งใช้แท่งหลังแท่งปัจจุบัน
+// unchecked = อ่านแท่งถัดไปโดยไม่ตรวจขอบเขตข้อมูล (panic เมื่อข้อมูลถูกตัด)
+type peekStrategy struct {
+	scriptedStrategy
+	unchecked bool
+}
+
+func (s *peekStrategy) Name() string { return "peek" }
+
+func (s *peekStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	i := bt.currentIndex
+	if !s.unchecked && i+1 >= len(bt.ohlcvData) {
+		return nil
+	}
+	if bt.ohlcvData[i+1].Close > bt.ohlcvData[i].Close {
+		return &EntrySignal{Side: "LONG", StopLoss: bt.ohlcvData[i].Low}
+	}
+	return nil
+}
+
+func (s *peekStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	return map[string]float64{
+		"centered_sma": centeredSMA(bt.ohlcvData, bt.currentIndex),
+		"close":        bt.ohlcvData[bt.currentIndex].Close,
+	}
+}
+
+// centeredSMA ค่าเฉลี่ยราคาปิดของแท่ง i-2 ถึง i+2 เท่าที่มีข้อมูล
+func centeredSMA(data []OHLCV, i int) float64 {
+	sum, n := 0.0, 0
+	for j := i - 2; j <= i+2; j++ {
+		if j >= 0 && j < len(data) {
+			sum += data[j].Close
+			n++
+		}
+	}
+	return sum / float64(n)
+}
+
+// trailingStrategy กลยุทธ์ที่ใช้เฉพาะข้อมูลถึงแท่งปัจจุบัน (ไม่ควรถูกรายงาน)
+type trailingStrategy struct{ scriptedStrategy }
+
+func (s *trailingStrategy) Name() string { return "trailing" }
+
+func (s *trailingStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	i := bt.currentIndex
+	if i > 0 && bt.ohlcvData[i].Close > bt.ohlcvData[i-1].Close {
+		return &EntrySignal{Side: "LONG"}
+	}
+	return nil
+}
+
+func (s *trailingStrategy) IndicatorValues(bt *Backtester) map[string]float64 {
+	i := bt.currentIndex
+	return map[string]float64{"sma": centeredSMA(bt.ohlcvData[:i+1], i)}
+}
+
+func TestDetectLookAheadFindsFutureBars(t *testing.T) {
+	data := syntheticCandles(200, 9)
+	report, err := DetectLookAhead("TEST_USDT", data, func() Strategy { return &peekStrategy{} }, LookAheadConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.HasLeak() || report.BarsChecked != len(data) {
+		t.Fatalf("ตรวจ %d แท่ง พบ %d แท่ง, ต้องการตรวจ %d แท่งและพบการรั่ว", report.BarsChecked, report.BarsLeaking, len(data))
+	}
+
+	// สัญญาณต่างทุกแท่งที่แท่งถัดไปปิดสูงกว่า ส่วน SMA กึ่งกลางต่างทุกแท่งยกเว้นแท่งสุดท้ายที่ไม่มีอนาคตให้เห็นอยู่แล้ว
+	signals := 0
+	for i := 0; i+1 < len(data); i++ {
+		if data[i+1].Close > data[i].Close {
+			signals++
+		}
+	}
+	if signals == 0 || report.FieldCounts["signal"] != signals {
+		t.Fatalf("signal ต่าง %d แท่ง, ต้องการ %d", report.FieldCounts["signal"], signals)
+	}
+	if got := report.FieldCounts["centered_sma"]; got != len(data)-1 {
+		t.Fatalf("centered_sma ต่าง %d แท่ง, ต้องการ %d", got, len(data)-1)
+	}
+	if report.FieldCounts["close"] != 0 || report.BarsLeaking != len(data)-1 {
+		t.Fatalf("close ต่าง %d แท่ง แท่งที่รั่ว %d, ต้องการ 0 และ %d", report.FieldCounts["close"], report.BarsLeaking, len(data)-1)
+	}
+	for _, d := range report.Diffs {
+		if d.Field == "signal" && (!strings.HasPrefix(d.Full, "LONG") || d.Truncated != "-") {
+			t.Fatalf("แท่ง %d: สัญญาณ %q กับ %q, ต้องการ LONG เฉพาะเมื่อเห็นข้อมูลเต็ม", d.Index, d.Full, d.Truncated)
+		}
+	}
+
+	// อ่านแท่งถัดไปโดยไม่ตรวจขอบเขต: ข้อมูลที่ตัดไว้ทำให้ panic ซึ่งถูกรายงานเป็นค่าต่างแทนที่จะล้มทั้งการตรวจ
+	report, err = DetectLookAhead("TEST_USDT", data, func() Strategy { return &peekStrategy{unchecked: true} },
+		LookAheadConfig{Start: 10, End: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.BarsChecked != 40 || report.FieldCounts["signal"] != 40 {
+		t.Fatalf("ตรวจ %d แท่ง signal ต่าง %d แท่ง, ต้องการ 40 ทั้งคู่", report.BarsChecked, report.FieldCounts["signal"])
+	}
+	for _, d := range report.Diffs {
+		if d.Field == "signal" && !strings.HasPrefix(d.Truncated, "panic:") {
+			t.Fatalf("แท่ง %d: ข้อมูลที่ตัดได้ %q, ต้องการ panic", d.Index, d.Truncated)
+		}
+	}
+}
+
+// peekExitStrategy ไม่มีสัญญาณเข้า แต่ออกจาก position เมื่อแท่งถัดไปปิดต่ำกว่า
+type peekExitStrategy struct{ scriptedStrategy }
+
+func (s *peekExitStrategy) Name() string { return "peek-exit" }
+
+func (s *peekExitStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	i := bt.currentIndex
+	if i+1 < len(bt.ohlcvData) && bt.ohlcvData[i+1].Close < bt.ohlcvData[i].Close {
+		return "PEEK_" + pos.Side
+	}
+	return ""
+}
+
+func TestDetectLookAheadFindsFutureExits(t *testing.T) {
+	data := syntheticCandles(200, 9)
+	report, err := DetectLookAhead("TEST_USDT", data, func() Strategy { return &peekExitStrategy{} }, LookAheadConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ExitSignal ถูกถามด้วย position สมมติทั้งสองฝั่ง: ค่าต่างเฉพาะแท่งที่แท่งถัดไปปิดต่ำกว่า
+	exits := 0
+	for i := 0; i+1 < len(data); i++ {
+		if data[i+1].Close < data[i].Close {
+			exits++
+		}
+	}
+	if exits == 0 || report.FieldCounts["exit"] != exits || report.FieldCounts["signal"] != 0 || report.BarsLeaking != exits {
+		t.Fatalf("exit ต่าง %d แท่ง (%v), ต้องการ %d", report.FieldCounts["exit"], report.FieldCounts, exits)
+	}
+	for _, d := range report.Diffs {
+		if d.Full != "PEEK_LONG | PEEK_SHORT" || d.Truncated != "- | -" {
+			t.Fatalf("แท่ง %d: สัญญาณออก %q กับ %q, ต้องการ PEEK ทั้งสองฝั่งเฉพาะเมื่อเห็นข้อมูลเต็ม", d.Index, d.Full, d.Truncated)
+		}
+	}
+}
+
+func TestDetectLookAheadPassesTrailingStrategy(t *testing.T) {
+	data := syntheticCandles(200, 9)
+	report, err := DetectLookAhead("TEST_USDT", data, func() Strategy { return &trailingStrategy{} }, LookAheadConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.HasLeak() || report.BarsChecked != len(data) {
+		t.Fatalf("ตรวจ %d แท่ง พบ %d แท่ง (%v), ต้องการไม่พบการรั่ว", report.BarsChecked, report.BarsLeaking, report.FieldCounts)
+	}
+}
+
+func TestDetectIndicatorLookAhead(t *testing.T) {
+	data := syntheticCandles(120, 4)
+	closes := func(data []OHLCV) []float64 {
+		values := make([]float64, len(data))
+		for i, c := range data {
+			values[i] = c.Close
+		}
+		return values
+	}
+
+	// normalize ด้วยราคาสูงสุดของทั้ง series: ค่าที่แท่ง i ขึ้นกับราคาหลังแท่ง i
+	normalized := func(data []OHLCV) map[string][]float64 {
+		values := closes(data)
+		peak := 0.0
+		for _, v := range values {
+			peak = max(peak, v)
+		}
+		for i := range values {
+			values[i] /= peak
+		}
+		return map[string][]float64{"normalized": values}
+	}
+	report, err := DetectIndicatorLookAhead("normalized", data, normalized, LookAheadConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.HasLeak() || report.FieldCounts["normalized"] != report.BarsLeaking {
+		t.Fatalf("normalized: พบ %d แท่ง (%v), ต้องการพบการรั่ว", report.BarsLeaking, report.FieldCounts)
+	}
+
+	ema := func(data []OHLCV) map[string][]float64 {
+		return map[string][]float64{"ema": indicators.EMA(closes(data), 20)}
+	}
+	report, err = DetectIndicatorLookAhead("ema", data, ema, LookAheadConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.HasLeak() || report.BarsChecked != len(data) {
+		t.Fatalf("ema: ตรวจ %d แท่ง พบ %d แท่ง, ต้องการไม่พบการรั่ว", report.BarsChecked, report.BarsLeaking)
+	}
+}
+
+func TestRegisteredStrategiesHaveNoLookAhead(t *testing.T) {
+	data := syntheticCandles(400, 17)
+	for _, info := range Strategies() {
+		if info.NeedsAI {
+			continue // ต้องมี AI client หรือ cache ซึ่งคำตอบผูกกับแท่งเทียนใน window อยู่แล้ว
+		}
+		t.Run(info.Name, func(t *testing.T) {
+			report, err := DetectLookAhead("TEST_USDT", data, info.New, LookAheadConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.HasLeak() {
+				t.Fatalf("พบค่าต่าง %d แท่ง %v เช่น %+v", report.BarsLeaking, report.FieldCounts, report.Diffs[0])
+			}
+			if report.BarsChecked != len(data)-info.New().WarmupBars() {
+				t.Fatalf("ตรวจ %d แท่ง, ต้องการ %d", report.BarsChecked, len(data)-info.New().WarmupBars())
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package trading
+
+// MarginMode โหมด margin ของบัญชี futures
+type MarginMode string
+
+const (
+	// IsolatedMargin ใช้เฉพาะ margin ของ position นั้นค้ำประกัน
+	IsolatedMargin MarginMode = "isolated"
+	// CrossMargin ใช้เงินทุนทั้งบัญชีค้ำประกัน position
+	CrossMargin MarginMode = "cross"
+)
+
+// LiquidationExit เหตุผลการปิดเมื่อ position ถูก liquidate
+const LiquidationExit = "LIQUIDATION"
+
+// FuturesAccount การตั้งค่าบัญชี perpetual futures สำหรับ backtest
+type FuturesAccount struct {
+	MarginMode            MarginMode `json:"margin_mode" yaml:"margin_mode"`
+	Leverage              float64    `json:"leverage" yaml:"leverage"`                               // leverage เริ่มต้น (ใช้เมื่อสัญญาณไม่กำหนด)
+	MaintenanceMarginRate float64    `json:"maintenance_margin_rate" yaml:"maintenance_margin_rate"` // อัตรา maintenance margin (0.005 = 0.5%)
+}
+
+// DefaultFuturesAccount ค่าเริ่มต้นแบบเดียวกับบอท live: isolated 5x, MMR 0.5%
+func DefaultFuturesAccount() FuturesAccount {
+	return FuturesAccount{
+		MarginMode:            IsolatedMargin,
+		Leverage:              5.0,
+		MaintenanceMarginRate: 0.005,
+	}
+}
+
+// SetFuturesAccount เปิดการจำลอง futures margin/leverage/liquidation
+func (bt *Backtester) SetFuturesAccount(account FuturesAccount) {
+	if account.Leverage < 1 {
+		account.Leverage = 1
+	}
+	if account.MarginMode == "" {
+		account.MarginMode = IsolatedMargin
+	}
+	bt.futures = &account
+}
+
+// applyMargin กำหนด leverage, margin และราคา liquidation ให้ position ที่เพิ่งเปิด
+// ลดขนาด position ถ้า margin ที่ต้องใช้เกินเงินทุนที่มี
+func (bt *Backtester) applyMargin(pos *BacktestPosition, leverage float64) {
+	if bt.futures == nil {
+		return
+	}
+
+	if leverage <= 0 {
+		leverage = bt.futures.Leverage
+	}
+
+	notional := pos.EntryPrice * pos.Quantity
+	margin := notional / leverage
+	if margin > bt.currentCapital {
+		pos.Quantity = bt.currentCapital * leverage / pos.EntryPrice
+		margin = bt.currentCapital
+		bt.logf("⚠️ Margin ไม่พอ ลดขนาด position เหลือ %.6f\n", pos.Quantity)
+	}
+
+	pos.Leverage = leverage
+	pos.MarginMode = bt.futures.MarginMode
+	pos.Margin = margin
+	pos.LiquidationPrice = bt.liquidationPrice(pos)
+
+	bt.logf("🏦 %s %.1fx: Margin $%.2f, Liquidation $%.2f\n",
+		pos.MarginMode, pos.Leverage, pos.Margin, pos.LiquidationPrice)
+}
+
+// liquidationPrice ราคาที่ equity ของ position เหลือเท่ากับ maintenance margin (0 = ไม่มีทางถูก liquidate)
+func (bt *Backtester) liquidationPrice(pos *BacktestPosition) float64 {
+	mmr := bt.futures.MaintenanceMarginRate
+
+	// collateral ที่ค้ำ position: isolated = margin ของ position, cross = เงินทุนทั้งบัญชี
+	collateral := pos.Margin
+	if pos.MarginMode == CrossMargin {
+		collateral = bt.currentCapital
+	}
+
+	var price float64
+	if pos.Side == "LONG" {
+		// collateral + (P - entry) * qty = mmr * P * qty
+		price = (pos.EntryPrice*pos.Quantity - collateral) / (pos.Quantity * (1 - mmr))
+	} else {
+		// collateral + (entry - P) * qty = mmr * P * qty
+		price = (collateral + pos.EntryPrice*pos.Quantity) / (pos.Quantity * (1 + mmr))
+	}
+
+	if price < 0 {
+		return 0
+	}
+	return price
+}
+
+// checkLiquidation ปิด position แบบบังคับถ้าราคาแตะราคา liquidation (คืน true ถ้าถูก liquidate)
+//
+// ราคา fill ใช้กติกาเดียวกับ SL/TP ระหว่างแท่ง: ราคาเคลื่อนมาถึงระดับระหว่างแท่ง fill ที่ราคา liquidation,
+// ราคาเปิด gap ข้ามระดับไปแล้ว fill ที่ราคาเปิด (ขาดทุนเกิน margin ได้ เพราะไม่ได้จำลอง insurance fund)
+// และเมื่อปิดการจำลองระหว่างแท่งจะ fill ที่ราคาปิดเหมือนการออกของกลยุทธ์
+// ถ้า Stop Loss อยู่ก่อนราคา liquidation บนเส้นทางราคา (รวมกรณีราคาเปิด gap ข้าม SL แต่ยังไม่ถึงราคา liquidation)
+// ให้ SL ทำงานก่อน
+func (bt *Backtester) checkLiquidation(candle OHLCV) bool {
+	pos := bt.position
+	if pos == nil {
+		return false
+	}
+
+	// cross ค้ำด้วยเงินทุนทั้งบัญชีซึ่งเปลี่ยนตาม funding และการปิดบางส่วน จึงคำนวณราคา liquidation ใหม่ทุกแท่ง
+	if pos.MarginMode == CrossMargin {
+		pos.LiquidationPrice = bt.liquidationPrice(pos)
+	}
+	if pos.LiquidationPrice <= 0 {
+		return false
+	}
+
+	liq := pos.LiquidationPrice
+	long := pos.Side == "LONG"
+
+	// ราคาที่แย่ที่สุดของแท่ง (ดูเฉพาะราคาปิดถ้าไม่ได้จำลองระหว่างแท่ง)
+	worst := candle.Close
+	if bt.intrabarFills {
+		if long {
+			worst = candle.Low
+		} else {
+			worst = candle.High
+		}
+	}
+
+	if (long && worst > liq) || (!long && worst < liq) {
+		return false
+	}
+
+	// SL อยู่ระหว่างราคาเปิดกับราคา liquidation → SL ถูก fill ก่อน (ที่ SL หรือที่ราคาเปิดถ้า gap)
+	if bt.intrabarFills && pos.StopLoss > 0 {
+		if long && pos.StopLoss > liq && candle.Open > liq {
+			return false
+		}
+		if !long && pos.StopLoss < liq && candle.Open < liq {
+			return false
+		}
+	}
+
+	fill := liq
+	switch {
+	case !bt.intrabarFills:
+		fill = candle.Close
+	case (long && candle.Open <= liq) || (!long && candle.Open >= liq):
+		fill = candle.Open
+	}
+
+	bt.logf("💥 Liquidation %s: ราคา $%.2f แตะราคา liquidation $%.2f → fill $%.2f\n", pos.Side, worst, liq, fill)
+	bt.closePositionAt(fill, LiquidationExit)
+	return true
+}
@@ -0,0 +1,111 @@
+package trading
+
+import (
+	"io"
+	"testing"
+)
+
+func TestLiquidationPrice(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		mode    MarginMode
+		side    string
+		capital float64
+		want    float64
+	}{
+		// entry 100 × 100, margin 10x = 1000, MMR 0.5%
+		// LONG: (notional - collateral) / (qty × (1 - mmr)), SHORT: (collateral + notional) / (qty × (1 + mmr))
+		{"isolated long", IsolatedMargin, "LONG", 5000, (10000 - 1000) / (100 * 0.995)},
+		{"isolated short", IsolatedMargin, "SHORT", 5000, (1000 + 10000) / (100 * 1.005)},
+		{"cross long", CrossMargin, "LONG", 5000, (10000 - 5000) / (100 * 0.995)},
+		{"cross short", CrossMargin, "SHORT", 5000, (5000 + 10000) / (100 * 1.005)},
+		// cross ที่เงินทุนมากกว่า notional: LONG ไม่มีทางถูก liquidate
+		{"cross long covered", CrossMargin, "LONG", 20000, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bt := &Backtester{currentCapital: tc.capital, log: io.Discard}
+			bt.SetFuturesAccount(FuturesAccount{MarginMode: tc.mode, Leverage: 10, MaintenanceMarginRate: 0.005})
+			pos := &BacktestPosition{Side: tc.side, EntryPrice: 100, Quantity: 100}
+			bt.applyMargin(pos, 0)
+
+			if pos.Margin != 1000 || !approx(pos.LiquidationPrice, tc.want) {
+				t.Fatalf("margin %v liquidation %v, ต้องการ 1000 และ %v", pos.Margin, pos.LiquidationPrice, tc.want)
+			}
+		})
+	}
+}
+
+func TestLiquidationVersusStopLoss(t *testing.T) {
+	// LONG 10 ที่ 100 แบบ isolated 10x: margin 100, liquidation = 900 / 9.95
+	liq := 900 / 9.95
+	for _, tc := range []struct {
+		name     string
+		stopLoss float64
+		bar      OHLCV
+		price    float64
+		reason   string
+	}{
+		{"sl before liquidation", 95, candle(99, 99.5, 85, 88), 95, "STOP_LOSS"},
+		{"gap through sl only", 95, candle(93, 94, 85, 88), 93, "STOP_LOSS"},
+		{"gap through both", 95, candle(89, 90, 85, 88), 89, LiquidationExit},
+		{"no sl", 0, candle(99, 99.5, 85, 88), liq, LiquidationExit},
+		{"sl beyond liquidation", 88, candle(99, 99.5, 85, 88), liq, LiquidationExit},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := hourly(candle(100, 100, 100, 100), tc.bar, candle(88, 88, 88, 88))
+			strategy := &scriptedStrategy{
+				entries:  map[int]*EntrySignal{0: {Side: "LONG", StopLoss: tc.stopLoss, Leverage: 10, Reason: "test"}},
+				quantity: 10,
+			}
+			result := runScripted(t, data, strategy, func(bt *Backtester) {
+				bt.SetFuturesAccount(DefaultFuturesAccount())
+			})
+
+			if len(result.Trades) != 1 {
+				t.Fatalf("ได้ %d เทรด, ต้องการ 1", len(result.Trades))
+			}
+			if trade := result.Trades[0]; !approx(trade.ExitPrice, tc.price) || trade.ExitReason != tc.reason {
+				t.Fatalf("ออกที่ %v (%s), ต้องการ %v (%s)", trade.ExitPrice, trade.ExitReason, tc.price, tc.reason)
+			}
+		})
+	}
+}
+
+func TestShortGapThroughLiquidationFillsAtOpen(t *testing.T) {
+	// SHORT 10 ที่ 100 แบบ isolated 10x: liquidation = 1100 / 10.05 ≈ 109.45
+	data := hourly(candle(100, 100, 100, 100), candle(112, 113, 111, 112), candle(112, 112, 112, 112))
+	strategy := &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "SHORT", Leverage: 10, Reason: "test"}},
+		quantity: 10,
+	}
+	result := runScripted(t, data, strategy, func(bt *Backtester) {
+		bt.SetFuturesAccount(DefaultFuturesAccount())
+	})
+	if trade := result.Trades[0]; trade.ExitPrice != 112 || trade.ExitReason != LiquidationExit {
+		t.Fatalf("ออกที่ %v (%s), ต้องการ gap fill ที่ราคาเปิด 112", trade.ExitPrice, trade.ExitReason)
+	}
+}
+
+func TestCrossLiquidationFollowsCapital(t *testing.T) {
+	// cross 5x: LONG 40 ที่ 100 (notional 4000) ค้ำด้วยเงินทุน 1000 → liquidation ≈ 75.38
+	// funding 12.5% ก่อนแท่งที่สองหักเงินทุน 500 → liquidation ≈ 87.94 ซึ่งแท่งที่สองแตะ
+	data := hourly(
+		candle(100, 100, 100, 100),
+		candle(100, 100.5, 99.5, 100),
+		candle(100, 100.5, 85, 90),
+		candle(90, 90, 90, 90),
+	)
+	strategy := &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "LONG", Reason: "test"}},
+		quantity: 40,
+	}
+	result := runScripted(t, data, strategy, func(bt *Backtester) {
+		bt.SetFuturesAccount(FuturesAccount{MarginMode: CrossMargin, Leverage: 5, MaintenanceMarginRate: 0.005})
+		bt.SetFundingModel(NewSeriesFundingModel([]FundingRate{{Timestamp: data[1].Timestamp, Rate: 0.125}}))
+	})
+
+	want := (4000 - 500) / (40 * 0.995)
+	if trade := result.Trades[0]; trade.ExitReason != LiquidationExit || !approx(trade.ExitPrice, want) {
+		t.Fatalf("ออกที่ %v (%s), ต้องการ liquidation ที่ %v หลัง funding", trade.ExitPrice, trade.ExitReason, want)
+	}
+}
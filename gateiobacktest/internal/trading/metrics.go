@@ -0,0 +1,177 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// PerformanceMetrics ตัวชี้วัดผลการเทรดมาตรฐาน
+type PerformanceMetrics struct {
+	SharpeRatio          float64       `json:"sharpe_ratio"`  // รายปี จากผลตอบแทนรายวัน (risk-free = 0)
+	SortinoRatio         float64       `json:"sortino_ratio"` // รายปี ใช้เฉพาะความผันผวนขาลง
+	CalmarRatio          float64       `json:"calmar_ratio"`  // ผลตอบแทนรายปี / Max Drawdown %
+	AnnualReturnPct      float64       `json:"annual_return_pct"`
+	ProfitFactor         float64       `json:"profit_factor"` // กำไรรวม / ขาดทุนรวม (0 = ไม่มีเทรดขาดทุน)
+	Expectancy           float64       `json:"expectancy"`    // NetPnL เฉลี่ยต่อเทรด ($)
+	AvgWin               float64       `json:"avg_win"`
+	AvgLoss              float64       `json:"avg_loss"`     // ค่าบวก ($)
+	PayoffRatio          float64       `json:"payoff_ratio"` // AvgWin / AvgLoss
+	MaxConsecutiveWins   int           `json:"max_consecutive_wins"`
+	MaxConsecutiveLosses int           `json:"max_consecutive_losses"`
+	ExposurePct          float64       `json:"exposure_pct"` // % ของแท่งที่ถือ position
+	AvgDuration          time.Duration `json:"avg_duration"`
+	AvgMAEPct            float64       `json:"avg_mae_pct"` // Maximum Adverse Excursion เฉลี่ย (% จากราคาเข้า)
+	AvgMFEPct            float64       `json:"avg_mfe_pct"` // Maximum Favorable Excursion เฉลี่ย (% จากราคาเข้า)
+}
+
+// CalculateMetrics คำนวณตัวชี้วัดจากรายการเทรดและ equity รายวัน
+// exposurePct คำนวณจากจำนวนแท่งที่ถือ position ซึ่ง backtester นับไว้ระหว่างรัน
+func CalculateMetrics(trades []BacktestTrade, equity []DailyReturn, maxDrawdownPct, exposurePct float64) PerformanceMetrics {
+	m := PerformanceMetrics{ExposurePct: exposurePct}
+
+	// ตัวชี้วัดจากรายการเทรด
+	var grossWin, grossLoss, totalDuration float64
+	var wins, losses, winStreak, lossStreak int
+	var totalMAE, totalMFE float64
+
+	for _, trade := range trades {
+		if trade.NetPnL > 0 {
+			grossWin += trade.NetPnL
+			wins++
+			winStreak++
+			lossStreak = 0
+		} else {
+			grossLoss += -trade.NetPnL
+			losses++
+			lossStreak++
+			winStreak = 0
+		}
+		if winStreak > m.MaxConsecutiveWins {
+			m.MaxConsecutiveWins = winStreak
+		}
+		if lossStreak > m.MaxConsecutiveLosses {
+			m.MaxConsecutiveLosses = lossStreak
+		}
+
+		totalDuration += float64(trade.Duration)
+		totalMAE += trade.MAEPct
+		totalMFE += trade.MFEPct
+	}
+
+	if n := len(trades); n > 0 {
+		m.Expectancy = (grossWin - grossLoss) / float64(n)
+		m.AvgDuration = time.Duration(totalDuration / float64(n))
+		m.AvgMAEPct = totalMAE / float64(n)
+		m.AvgMFEPct = totalMFE / float64(n)
+	}
+	if wins > 0 {
+		m.AvgWin = grossWin / float64(wins)
+	}
+	if losses > 0 {
+		m.AvgLoss = grossLoss / float64(losses)
+	}
+	if m.AvgLoss > 0 {
+		m.PayoffRatio = m.AvgWin / m.AvgLoss
+	}
+	// ไม่มีเทรดขาดทุน: Profit Factor = 0 (หาค่าไม่ได้ และ JSON ไม่รองรับ Inf)
+	if grossLoss > 0 {
+		m.ProfitFactor = grossWin / grossLoss
+	}
+
+	// ตัวชี้วัดจาก equity รายวัน
+	returns := equityReturns(equity)
+	if len(returns) >= 2 {
+		mean, std := meanStdDev(returns)
+		if std > 0 {
+			m.SharpeRatio = mean / std * math.Sqrt(365)
+		}
+
+		downside := 0.0
+		for _, r := range returns {
+			if r < 0 {
+				downside += r * r
+			}
+		}
+		if downside > 0 {
+			m.SortinoRatio = mean / math.Sqrt(downside/float64(len(returns))) * math.Sqrt(365)
+		}
+	}
+
+	if len(equity) >= 2 && equity[0].Equity > 0 {
+		first, last := equity[0], equity[len(equity)-1]
+		days := last.Date.Sub(first.Date).Hours() / 24
+		if days > 0 && last.Equity > 0 {
+			m.AnnualReturnPct = (math.Pow(last.Equity/first.Equity, 365/days) - 1) * 100
+		}
+	}
+	if maxDrawdownPct > 0 {
+		m.CalmarRatio = m.AnnualReturnPct / maxDrawdownPct
+	}
+
+	return m
+}
+
+// equityReturns ผลตอบแทนรายช่วงจาก equity ต่อเนื่อง
+func equityReturns(equity []DailyReturn) []float64 {
+	returns := make([]float64, 0, len(equity))
+	for i := 1; i < len(equity); i++ {
+		if prev := equity[i-1].Equity; prev > 0 {
+			returns = append(returns, (equity[i].Equity-prev)/prev)
+		}
+	}
+	return returns
+}
+
+// meanStdDev ค่าเฉลี่ยและส่วนเบี่ยงเบนมาตรฐาน (sample) ของข้อมูล
+func meanStdDev(values []float64) (mean, std float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)-1))
+}
+
+// trackExcursion อัปเดต MAE/MFE ของ position จากราคาสูง/ต่ำที่ราคาผ่าน
+func (bt *Backtester) trackExcursion(high, low float64) {
+	pos := bt.position
+	if pos == nil {
+		return
+	}
+
+	favorable := (high - pos.EntryPrice) / pos.EntryPrice * 100
+	adverse := (pos.EntryPrice - low) / pos.EntryPrice * 100
+	if pos.Side == "SHORT" {
+		favorable = (pos.EntryPrice - low) / pos.EntryPrice * 100
+		adverse = (high - pos.EntryPrice) / pos.EntryPrice * 100
+	}
+
+	pos.MFEPct = math.Max(pos.MFEPct, favorable)
+	pos.MAEPct = math.Max(pos.MAEPct, adverse)
+}
+
+// PrintMetrics แสดงตัวชี้วัดผลการเทรดในรูปแบบเดียวกันทุก runner
+func (r *BacktestResult) PrintMetrics() {
+	r.Metrics.Print()
+}
+
+// Print แสดงตัวชี้วัดผลการเทรด
+func (m PerformanceMetrics) Print() {
+	fmt.Println("📐 ตัวชี้วัดผลการเทรด:")
+	fmt.Printf("   Sharpe: %.2f | Sortino: %.2f | Calmar: %.2f | ผลตอบแทนรายปี: %.2f%%\n",
+		m.SharpeRatio, m.SortinoRatio, m.CalmarRatio, m.AnnualReturnPct)
+	fmt.Printf("   Profit Factor: %.2f | Expectancy: $%.2f/เทรด\n", m.ProfitFactor, m.Expectancy)
+	fmt.Printf("   กำไรเฉลี่ย: $%.2f | ขาดทุนเฉลี่ย: $%.2f | Payoff: %.2f\n", m.AvgWin, m.AvgLoss, m.PayoffRatio)
+	fmt.Printf("   ชนะติดกันสูงสุด: %d | แพ้ติดกันสูงสุด: %d\n", m.MaxConsecutiveWins, m.MaxConsecutiveLosses)
+	fmt.Printf("   Exposure: %.2f%% | ถือเฉลี่ย: %v\n", m.ExposurePct, m.AvgDuration.Round(time.Minute))
+	fmt.Printf("   MAE เฉลี่ย: %.2f%% | MFE เฉลี่ย: %.2f%%\n", m.AvgMAEPct, m.AvgMFEPct)
+}
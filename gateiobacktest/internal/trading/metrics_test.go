@@ -0,0 +1,56 @@
+package trading
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCalculateMetricsByHand(t *testing.T) {
+	day := func(i int, equity float64) DailyReturn {
+		return DailyReturn{Date: time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC), Equity: equity}
+	}
+	// ผลตอบแทนรายวัน +10%, -10%, +10%
+	equity := []DailyReturn{day(0, 100), day(1, 110), day(2, 99), day(3, 108.9)}
+	var trades []BacktestTrade
+	for _, pnl := range []float64{30, -10, 20, -5, 0} {
+		trades = append(trades, BacktestTrade{NetPnL: pnl})
+	}
+
+	m := CalculateMetrics(trades, equity, 10, 50)
+
+	// mean = 1/30, sample std = sqrt(0.02667/2) = 0.11547, Sharpe = mean/std x sqrt(365)
+	mean, std := 1.0/30, math.Sqrt((2*math.Pow(0.2/3, 2)+math.Pow(0.4/3, 2))/2)
+	if want := mean / std * math.Sqrt(365); !approx(m.SharpeRatio, want) || math.Abs(m.SharpeRatio-5.515131) > 1e-6 {
+		t.Fatalf("Sharpe %v, ต้องการ %v", m.SharpeRatio, want)
+	}
+	// downside deviation = sqrt(0.1² / 3) นับทุกวันเป็นตัวหาร
+	if want := mean / math.Sqrt(0.01/3) * math.Sqrt(365); !approx(m.SortinoRatio, want) || math.Abs(m.SortinoRatio-11.030261) > 1e-6 {
+		t.Fatalf("Sortino %v, ต้องการ %v", m.SortinoRatio, want)
+	}
+	// กำไรรวม 50 / ขาดทุนรวม 15 (เทรด 0 นับเป็นเทรดขาดทุน: AvgLoss = 15/3)
+	if !approx(m.ProfitFactor, 50.0/15) || !approx(m.Expectancy, 7) || !approx(m.AvgWin, 25) ||
+		!approx(m.AvgLoss, 5) || !approx(m.PayoffRatio, 5) {
+		t.Fatalf("PF %v expectancy %v avg win/loss %v/%v payoff %v", m.ProfitFactor, m.Expectancy, m.AvgWin, m.AvgLoss, m.PayoffRatio)
+	}
+	if m.MaxConsecutiveWins != 1 || m.MaxConsecutiveLosses != 2 {
+		t.Fatalf("ชนะติดกัน %d แพ้ติดกัน %d, ต้องการ 1 และ 2", m.MaxConsecutiveWins, m.MaxConsecutiveLosses)
+	}
+	annual := (math.Pow(1.089, 365.0/3) - 1) * 100
+	if !approx(m.AnnualReturnPct, annual) || !approx(m.CalmarRatio, annual/10) || m.ExposurePct != 50 {
+		t.Fatalf("annual %v calmar %v exposure %v", m.AnnualReturnPct, m.CalmarRatio, m.ExposurePct)
+	}
+}
+
+func TestCalculateMetricsWithoutLosses(t *testing.T) {
+	// ไม่มีเทรดขาดทุนและไม่มีวันขาดทุน: PF และ Sortino หาค่าไม่ได้จึงเป็น 0, equity คงที่ทำให้ Sharpe เป็น 0
+	trades := []BacktestTrade{{NetPnL: 10}, {NetPnL: 5}}
+	rising := []DailyReturn{{Equity: 100}, {Equity: 101}, {Equity: 103}}
+	if m := CalculateMetrics(trades, rising, 0, 0); m.ProfitFactor != 0 || m.SortinoRatio != 0 || m.SharpeRatio <= 0 || m.CalmarRatio != 0 {
+		t.Fatalf("PF %v Sortino %v Sharpe %v Calmar %v", m.ProfitFactor, m.SortinoRatio, m.SharpeRatio, m.CalmarRatio)
+	}
+	flat := []DailyReturn{{Equity: 100}, {Equity: 100}, {Equity: 100}}
+	if m := CalculateMetrics(nil, flat, 0, 0); m.SharpeRatio != 0 || m.SortinoRatio != 0 || m.Expectancy != 0 {
+		t.Fatalf("equity คงที่: Sharpe %v Sortino %v expectancy %v", m.SharpeRatio, m.SortinoRatio, m.Expectancy)
+	}
+}
@@ -0,0 +1,245 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// ResampleMethod วิธีสุ่มลำดับเทรดสำหรับ Monte Carlo
+type ResampleMethod string
+
+const (
+	ShuffleTrades   ResampleMethod = "shuffle"   // สลับลำดับเทรดเดิม (ไม่ซ้ำ)
+	BootstrapTrades ResampleMethod = "bootstrap" // สุ่มเทรดแบบใส่คืน
+	BlockBootstrap  ResampleMethod = "block"     // สุ่มเป็นช่วงต่อเนื่องขนาด BlockSize (รักษา autocorrelation)
+)
+
+// MonteCarloConfig การตั้งค่า Monte Carlo simulation
+type MonteCarloConfig struct {
+	Iterations       int            `json:"iterations"` // จำนวนรอบจำลอง (0 = 5000)
+	Method           ResampleMethod `json:"method"`
+	BlockSize        int            `json:"block_size"`         // ขนาด block สำหรับ BlockBootstrap (0 = 5)
+	RuinThresholdPct float64        `json:"ruin_threshold_pct"` // ขาดทุนจากเงินทุนเริ่มต้นกี่ % ถือว่า ruin (0 = 50%)
+	Seed             int64          `json:"seed"`
+}
+
+// Distribution สถิติการกระจายของค่าจากทุกรอบจำลอง
+type Distribution struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	Min    float64 `json:"min"`
+	P5     float64 `json:"p5"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	P95    float64 `json:"p95"`
+	Max    float64 `json:"max"`
+}
+
+// MonteCarloResult ผล Monte Carlo simulation
+type MonteCarloResult struct {
+	Iterations          int            `json:"iterations"`
+	Trades              int            `json:"trades"`
+	Method              ResampleMethod `json:"method"`
+	InitialCapital      float64        `json:"initial_capital"`
+	FinalEquity         Distribution   `json:"final_equity"`
+	MaxDrawdownPct      Distribution   `json:"max_drawdown_pct"`
+	LongestLosingStreak Distribution   `json:"longest_losing_streak"`
+	ProbabilityOfLoss   float64        `json:"probability_of_loss"` // % ของรอบที่จบต่ำกว่าเงินทุนเริ่มต้น
+	RiskOfRuin          float64        `json:"risk_of_ruin"`        // % ของรอบที่ equity ลงถึงระดับ ruin
+	RuinThresholdPct    float64        `json:"ruin_threshold_pct"`
+}
+
+// RunMonteCarlo สุ่มลำดับเทรดของผล backtest หลายพันรอบเพื่อหาช่วงความเชื่อมั่นของผลลัพธ์
+// แต่ละเทรดใช้ผลตอบแทนเป็น % ของ equity ก่อนเข้าเทรด (ทบต้นตามลำดับที่สุ่มได้)
+func RunMonteCarlo(result *BacktestResult, config MonteCarloConfig) (*MonteCarloResult, error) {
+	if result == nil || len(result.Trades) == 0 {
+		return nil, fmt.Errorf("ไม่มีเทรดสำหรับ Monte Carlo")
+	}
+
+	if config.Iterations <= 0 {
+		config.Iterations = 5000
+	}
+	if config.Method == "" {
+		config.Method = BootstrapTrades
+	}
+	if config.BlockSize <= 0 {
+		config.BlockSize = 5
+	}
+	if config.RuinThresholdPct <= 0 {
+		config.RuinThresholdPct = 50
+	}
+
+	returns := tradeReturns(result)
+	n := len(returns)
+	rng := rand.New(rand.NewSource(config.Seed))
+	ruinLevel := result.InitialCapital * (1 - config.RuinThresholdPct/100)
+
+	finals := make([]float64, config.Iterations)
+	drawdowns := make([]float64, config.Iterations)
+	streaks := make([]float64, config.Iterations)
+	losses, ruins := 0, 0
+	path := make([]float64, n)
+
+	for i := 0; i < config.Iterations; i++ {
+		if err := resampleReturns(path, returns, config, rng); err != nil {
+			return nil, err
+		}
+
+		equity := result.InitialCapital
+		peak := equity
+		maxDD := 0.0
+		streak, longest := 0, 0
+		ruined := false
+
+		for _, r := range path {
+			equity *= 1 + r
+			if equity > peak {
+				peak = equity
+			}
+			if dd := (peak - equity) / peak * 100; dd > maxDD {
+				maxDD = dd
+			}
+			if equity <= ruinLevel {
+				ruined = true
+			}
+			if r < 0 {
+				streak++
+				if streak > longest {
+					longest = streak
+				}
+			} else {
+				streak = 0
+			}
+		}
+
+		finals[i] = equity
+		drawdowns[i] = maxDD
+		streaks[i] = float64(longest)
+		if equity < result.InitialCapital {
+			losses++
+		}
+		if ruined {
+			ruins++
+		}
+	}
+
+	return &MonteCarloResult{
+		Iterations:          config.Iterations,
+		Trades:              n,
+		Method:              config.Method,
+		InitialCapital:      result.InitialCapital,
+		FinalEquity:         newDistribution(finals),
+		MaxDrawdownPct:      newDistribution(drawdowns),
+		LongestLosingStreak: newDistribution(streaks),
+		ProbabilityOfLoss:   float64(losses) / float64(config.Iterations) * 100,
+		RiskOfRuin:          float64(ruins) / float64(config.Iterations) * 100,
+		RuinThresholdPct:    config.RuinThresholdPct,
+	}, nil
+}
+
+// tradeReturns ผลตอบแทนของแต่ละเทรดเทียบกับ equity ก่อนเข้าเทรด
+// ขาที่ทยอยปิดของ position เดียวกันรวมเป็นเทรดเดียว เพื่อไม่ให้ scale-out เพิ่มจำนวนเทรดที่สุ่ม
+func tradeReturns(result *BacktestResult) []float64 {
+	trades := PositionTrades(result.Trades)
+	returns := make([]float64, 0, len(trades))
+	equity := result.InitialCapital
+	for _, trade := range trades {
+		if equity <= 0 {
+			returns = append(returns, -1)
+			continue
+		}
+		returns = append(returns, trade.NetPnL/equity)
+		equity += trade.NetPnL
+	}
+	return returns
+}
+
+// resampleReturns สุ่มลำดับผลตอบแทนลงใน path ตามวิธีที่กำหนด
+func resampleReturns(path, returns []float64, config MonteCarloConfig, rng *rand.Rand) error {
+	n := len(returns)
+
+	switch config.Method {
+	case ShuffleTrades:
+		copy(path, returns)
+		rng.Shuffle(n, func(i, j int) { path[i], path[j] = path[j], path[i] })
+	case BootstrapTrades:
+		for i := range path {
+			path[i] = returns[rng.Intn(n)]
+		}
+	case BlockBootstrap:
+		for i := 0; i < n; {
+			start := rng.Intn(n)
+			for j := 0; j < config.BlockSize && i < n; j++ {
+				path[i] = returns[(start+j)%n]
+				i++
+			}
+		}
+	default:
+		return fmt.Errorf("ไม่รู้จักวิธี resample: %s", config.Method)
+	}
+
+	return nil
+}
+
+// newDistribution คำนวณสถิติและ percentile ของค่าทั้งหมด
+func newDistribution(values []float64) Distribution {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mean := 0.0
+	for _, v := range sorted {
+		mean += v
+	}
+	mean /= float64(len(sorted))
+
+	variance := 0.0
+	for _, v := range sorted {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return Distribution{
+		Mean:   mean,
+		StdDev: math.Sqrt(variance / float64(len(sorted))),
+		Min:    sorted[0],
+		P5:     percentile(sorted, 5),
+		P25:    percentile(sorted, 25),
+		Median: percentile(sorted, 50),
+		P75:    percentile(sorted, 75),
+		P95:    percentile(sorted, 95),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// percentile ค่า percentile แบบ linear interpolation จากข้อมูลที่เรียงแล้ว
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	pos := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	weight := pos - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
+
+// PrintSummary แสดงผล Monte Carlo พร้อมช่วงความเชื่อมั่น 90% (P5-P95)
+func (r *MonteCarloResult) PrintSummary() {
+	fmt.Printf("\n🎲 ===== Monte Carlo (%s, %d รอบ, %d เทรด) =====\n", r.Method, r.Iterations, r.Trades)
+	fmt.Printf("%-22s %12s %12s %12s %12s %12s\n", "", "P5", "P25", "Median", "P75", "P95")
+	fmt.Printf("%-22s %12.2f %12.2f %12.2f %12.2f %12.2f\n", "Final Equity ($)",
+		r.FinalEquity.P5, r.FinalEquity.P25, r.FinalEquity.Median, r.FinalEquity.P75, r.FinalEquity.P95)
+	fmt.Printf("%-22s %12.2f %12.2f %12.2f %12.2f %12.2f\n", "Max Drawdown (%)",
+		r.MaxDrawdownPct.P5, r.MaxDrawdownPct.P25, r.MaxDrawdownPct.Median, r.MaxDrawdownPct.P75, r.MaxDrawdownPct.P95)
+	fmt.Printf("%-22s %12.0f %12.0f %12.0f %12.0f %12.0f\n", "Longest Losing Streak",
+		r.LongestLosingStreak.P5, r.LongestLosingStreak.P25, r.LongestLosingStreak.Median,
+		r.LongestLosingStreak.P75, r.LongestLosingStreak.P95)
+	fmt.Printf("\n📉 โอกาสขาดทุน: %.2f%%\n", r.ProbabilityOfLoss)
+	fmt.Printf("💀 Risk of Ruin (ขาดทุน ≥ %.0f%%): %.2f%%\n", r.RuinThresholdPct, r.RiskOfRuin)
+	if r.Trades < 30 {
+		fmt.Printf("⚠️ มีเพียง %d เทรด ผลการจำลองยังไม่น่าเชื่อถือ\n", r.Trades)
+	}
+}
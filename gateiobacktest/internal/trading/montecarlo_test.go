@@ -0,0 +1,98 @@
+package trading
+
+import (
+	"reflect"
+	"testing"
+)
+
+// monteCarloResult ผล backtest 40 เทรดบนทุน $1000: ชนะ $30 สลับแพ้ $20 แล้วแพ้ติดกัน 5 ไม้ท้ายสุด (จบที่ $1200)
+func monteCarloResult() *BacktestResult {
+	result := &BacktestResult{InitialCapital: 1000, FinalCapital: 1000}
+	for i := 0; i < 40; i++ {
+		pnl := 30.0
+		if i%2 == 1 || i >= 36 {
+			pnl = -20
+		}
+		result.Trades = append(result.Trades, BacktestTrade{ID: i + 1, NetPnL: pnl})
+		result.FinalCapital += pnl
+	}
+	return result
+}
+
+func TestMonteCarloSeededPercentiles(t *testing.T) {
+	result := monteCarloResult()
+	for _, method := range []ResampleMethod{ShuffleTrades, BootstrapTrades, BlockBootstrap} {
+		t.Run(string(method), func(t *testing.T) {
+			config := MonteCarloConfig{Iterations: 2000, Method: method, Seed: 42}
+			mc, err := RunMonteCarlo(result, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mc.Iterations != 2000 || mc.Trades != 40 {
+				t.Fatalf("รอบ %d เทรด %d, ต้องการ 2000 และ 40", mc.Iterations, mc.Trades)
+			}
+
+			for name, d := range map[string]Distribution{
+				"final_equity":          mc.FinalEquity,
+				"max_drawdown_pct":      mc.MaxDrawdownPct,
+				"longest_losing_streak": mc.LongestLosingStreak,
+			} {
+				ordered := []float64{d.Min, d.P5, d.P25, d.Median, d.P75, d.P95, d.Max}
+				for i := 1; i < len(ordered); i++ {
+					if ordered[i] < ordered[i-1] {
+						t.Fatalf("%s: percentile ไม่เรียงลำดับ min/p5/p25/median/p75/p95/max = %v", name, ordered)
+					}
+				}
+				if d.Mean < d.Min || d.Mean > d.Max || d.StdDev < 0 {
+					t.Fatalf("%s: mean %v std %v อยู่นอกช่วง [%v, %v]", name, d.Mean, d.StdDev, d.Min, d.Max)
+				}
+			}
+			// การสุ่มแบบใส่คืนได้ final equity หลายค่า percentile จึงต้องไม่เท่ากันหมด
+			if method != ShuffleTrades && mc.FinalEquity.P5 >= mc.FinalEquity.P95 {
+				t.Fatalf("final equity P5 %v ไม่น้อยกว่า P95 %v", mc.FinalEquity.P5, mc.FinalEquity.P95)
+			}
+			if mc.ProbabilityOfLoss < 0 || mc.ProbabilityOfLoss > 100 || mc.RiskOfRuin != 0 {
+				t.Fatalf("โอกาสขาดทุน %v%% risk of ruin %v%%", mc.ProbabilityOfLoss, mc.RiskOfRuin)
+			}
+
+			// seed เดียวกันได้ผลเหมือนเดิมทุกครั้ง
+			again, err := RunMonteCarlo(result, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(mc, again) {
+				t.Fatalf("seed เดียวกันได้ผลต่างกัน: %+v กับ %+v", mc.FinalEquity, again.FinalEquity)
+			}
+		})
+	}
+}
+
+func TestMonteCarloShuffleKeepsFinalEquity(t *testing.T) {
+	// สลับลำดับอย่างเดียว: ผลตอบแทนทบต้นคูณกันได้ค่าเดิมทุกรอบ ต่างกันแค่ drawdown และ streak
+	result := monteCarloResult()
+	mc, err := RunMonteCarlo(result, MonteCarloConfig{Iterations: 500, Method: ShuffleTrades, Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approx(mc.FinalEquity.Min, result.FinalCapital) || !approx(mc.FinalEquity.Max, result.FinalCapital) {
+		t.Fatalf("final equity %v-%v, ต้องการ %v ทุกรอบ", mc.FinalEquity.Min, mc.FinalEquity.Max, result.FinalCapital)
+	}
+	// ลำดับเดิมแพ้ติดกันยาวสุด 5 ไม้จากทั้งหมด 20 ไม้ที่แพ้ การสลับทำให้ streak และ drawdown ต่างกันในแต่ละรอบ
+	if mc.LongestLosingStreak.Min < 1 || mc.LongestLosingStreak.Max > 20 || mc.MaxDrawdownPct.Max <= mc.MaxDrawdownPct.Min {
+		t.Fatalf("streak %v-%v drawdown %v-%v", mc.LongestLosingStreak.Min, mc.LongestLosingStreak.Max,
+			mc.MaxDrawdownPct.Min, mc.MaxDrawdownPct.Max)
+	}
+
+	if _, err := RunMonteCarlo(result, MonteCarloConfig{Method: "jackknife"}); err == nil {
+		t.Fatal("RunMonteCarlo ต้องคืน error เมื่อไม่รู้จักวิธี resample")
+	}
+}
+
+func TestPercentileInterpolates(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40, 50}
+	for _, tc := range []struct{ p, want float64 }{{0, 10}, {25, 20}, {50, 30}, {90, 46}, {100, 50}} {
+		if got := percentile(sorted, tc.p); !approx(got, tc.want) {
+			t.Fatalf("P%v = %v, ต้องการ %v", tc.p, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package trading
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ParamRange ค่าที่จะทดสอบของพารามิเตอร์หนึ่งตัว
+type ParamRange struct {
+	Name   string    `json:"name"` // ชื่อตาม ParamNames เช่น "atr_multiplier"
+	Values []float64 `json:"values"`
+}
+
+// NewParamRange สร้างช่วงค่าจาก min ถึง max ทีละ step
+func NewParamRange(name string, min, max, step float64) ParamRange {
+	var values []float64
+	for v := min; v <= max+step/1e6; v += step {
+		values = append(values, math.Round(v*1e6)/1e6)
+	}
+	return ParamRange{Name: name, Values: values}
+}
+
+// Objective เกณฑ์จัดอันดับผลการ optimize
+type Objective string
+
+const (
+	ObjectiveReturn   Objective = "return"   // ผลตอบแทนรวมสูงสุด
+	ObjectiveSharpe   Objective = "sharpe"   // Sharpe ratio สูงสุด
+	ObjectiveDrawdown Objective = "drawdown" // Max drawdown ต่ำสุด
+)
+
+// SearchMethod วิธีค้นหาใน parameter space
+type SearchMethod string
+
+const (
+	GridSearch   SearchMethod = "grid"   // ทดสอบทุกชุด
+	RandomSearch SearchMethod = "random" // สุ่มตามจำนวน Samples
+)
+
+// OptimizerConfig การตั้งค่า optimizer
+type OptimizerConfig struct {
+	Space     []ParamRange `json:"space"`
+	Method    SearchMethod `json:"method"`
+	Samples   int          `json:"samples"` // จำนวนชุดที่สุ่ม (RandomSearch)
+	Workers   int          `json:"workers"` // จำนวน backtest ที่รันพร้อมกัน (0 = จำนวน CPU)
+	Objective Objective    `json:"objective"`
+	Seed      int64        `json:"seed"`
+}
+
+// OptimizationRun ผลของพารามิเตอร์หนึ่งชุด
+type OptimizationRun struct {
+	Rank           int            `json:"rank"`
+	Params         StrategyParams `json:"params"`
+	Score          float64        `json:"score"`
+	TotalReturnPct float64        `json:"total_return_pct"`
+	SharpeRatio    float64        `json:"sharpe_ratio"`
+	MaxDrawdownPct float64        `json:"max_drawdown_pct"`
+	WinRate        float64        `json:"win_rate"`
+	TotalTrades    int            `json:"total_trades"`
+	Error          string         `json:"error,omitempty"`
+}
+
+// StrategyRunner รันกลยุทธ์บน backtester ที่ตั้งพารามิเตอร์แล้ว
+type StrategyRunner func(bt *Backtester) (*BacktestResult, error)
+
+// Optimizer ค้นหาพารามิเตอร์ที่ดีที่สุดด้วยการรัน backtest หลายชุดแบบขนาน
+type Optimizer struct {
+	symbol         string
+	data           []OHLCV
+	initialCapital float64
+	base           StrategyParams
+	runner         StrategyRunner
+
+	// ช่วงเวลาที่ใช้เทรด (ข้อมูลก่อน start ใช้เป็น warmup)
+	start time.Time
+	end   time.Time
+
+	incremental bool            // ใช้ตัวชี้วัดแบบ streaming ในทุกการรัน
+	config      *StrategyConfig // config ที่ใช้กับทุกการรัน (nil = ค่าเริ่มต้นของ backtester)
+	log         io.Writer       // ปลายทางความคืบหน้า (nil = os.Stdout)
+}
+
+// NewOptimizer สร้าง optimizer (runner = nil ใช้ RunBacktest)
+func NewOptimizer(symbol string, data []OHLCV, initialCapital float64, runner StrategyRunner) *Optimizer {
+	if runner == nil {
+		runner = func(bt *Backtester) (*BacktestResult, error) {
+			return bt.RunBacktest()
+		}
+	}
+	o := &Optimizer{
+		symbol:         symbol,
+		data:           data,
+		initialCapital: initialCapital,
+		base:           DefaultStrategyParams(),
+		runner:         runner,
+	}
+	if len(data) > 0 {
+		o.start = time.Unix(data[0].Timestamp, 0)
+		o.end = time.Unix(data[len(data)-1].Timestamp, 0)
+	}
+	return o
+}
+
+// SetWindow จำกัดช่วงเวลาที่ใช้ประเมินพารามิเตอร์ (เช่น in-sample ของ walk-forward)
+func (o *Optimizer) SetWindow(start, end time.Time) {
+	o.start = start
+	o.end = end
+}
+
+// SetConfig ใช้ config เดียวกับคำสั่ง run ในทุกการรัน (commission, futures, funding, intrabar fills ฯลฯ)
+// params ของ config เป็นค่าพื้นฐาน และ start_date/end_date จำกัดช่วงที่ประเมิน (ข้อมูลก่อนหน้าใช้เป็น warmup)
+func (o *Optimizer) SetConfig(config StrategyConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	config.Symbols = []string{o.symbol}
+	o.config = &config
+	o.base = config.Params
+	o.initialCapital = config.Risk.Capital
+	o.incremental = config.IncrementalIndicators
+
+	start, end, _ := config.DateRange()
+	if !start.IsZero() {
+		if end.IsZero() {
+			end = time.Now()
+		}
+		o.SetWindow(start, end)
+	}
+	return nil
+}
+
+// SetLogOutput กำหนดปลายทางความคืบหน้าของ optimize และ walk-forward (nil = os.Stdout)
+func (o *Optimizer) SetLogOutput(w io.Writer) {
+	o.log = w
+}
+
+// SetBaseParams กำหนดค่าพื้นฐานของพารามิเตอร์ที่ไม่ได้อยู่ใน parameter space
+func (o *Optimizer) SetBaseParams(params StrategyParams) {
+	o.base = params
+}
+
+// SetIncrementalIndicators ใช้ตัวชี้วัดแบบ streaming ในทุกการรัน (เร็วขึ้นมากกับข้อมูลยาว)
+func (o *Optimizer) SetIncrementalIndicators(enabled bool) {
+	o.incremental = enabled
+}
+
+// Run รัน optimization และคืนผลเรียงตาม objective (อันดับ 1 = ดีที่สุด)
+func (o *Optimizer) Run(config OptimizerConfig) ([]OptimizationRun, error) {
+	if len(o.data) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาสำหรับ optimize")
+	}
+
+	if config.Method == "" {
+		config.Method = GridSearch
+	}
+	if config.Objective == "" {
+		config.Objective = ObjectiveReturn
+	}
+
+	candidates, err := o.candidates(config)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	writeLog(o.log, "🔧 เริ่ม optimize %s: %d ชุดพารามิเตอร์ (%s), %d workers, objective=%s\n",
+		o.symbol, len(candidates), config.Method, workers, config.Objective)
+
+	runs := make([]OptimizationRun, len(candidates))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	started := time.Now()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				runs[i] = o.evaluate(candidates[i], config.Objective)
+
+				mu.Lock()
+				done++
+				if done%10 == 0 || done == len(candidates) {
+					writeLog(o.log, "⏳ optimize %d/%d (%.0fs)\n", done, len(candidates), time.Since(started).Seconds())
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range candidates {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		if (runs[i].Error == "") != (runs[j].Error == "") {
+			return runs[i].Error == ""
+		}
+		return runs[i].Score > runs[j].Score
+	})
+	for i := range runs {
+		runs[i].Rank = i + 1
+	}
+
+	return runs, nil
+}
+
+// candidates สร้างชุดพารามิเตอร์ตามวิธีค้นหา
+func (o *Optimizer) candidates(config OptimizerConfig) ([]StrategyParams, error) {
+	if len(config.Space) == 0 {
+		return nil, fmt.Errorf("parameter space ว่าง")
+	}
+	for _, r := range config.Space {
+		if len(r.Values) == 0 {
+			return nil, fmt.Errorf("พารามิเตอร์ %s ไม่มีค่าให้ทดสอบ", r.Name)
+		}
+		if _, err := o.base.Get(r.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	var candidates []StrategyParams
+
+	switch config.Method {
+	case RandomSearch:
+		if config.Samples <= 0 {
+			return nil, fmt.Errorf("random search ต้องกำหนด samples > 0")
+		}
+		rng := rand.New(rand.NewSource(config.Seed))
+		for i := 0; i < config.Samples; i++ {
+			params := o.base
+			for _, r := range config.Space {
+				params.Set(r.Name, r.Values[rng.Intn(len(r.Values))])
+			}
+			candidates = append(candidates, params)
+		}
+	case GridSearch:
+		candidates = []StrategyParams{o.base}
+		for _, r := range config.Space {
+			var next []StrategyParams
+			for _, params := range candidates {
+				for _, value := range r.Values {
+					p := params
+					p.Set(r.Name, value)
+					next = append(next, p)
+				}
+			}
+			candidates = next
+		}
+	default:
+		return nil, fmt.Errorf("ไม่รู้จักวิธีค้นหา: %s", config.Method)
+	}
+
+	return candidates, nil
+}
+
+// newBacktester สร้าง backtester ของหนึ่งการรันตาม config ของ optimizer ด้วยพารามิเตอร์และเงินทุนที่กำหนด
+// เทรดเฉพาะช่วง start-end (ปิด log ของแต่ละ backtest เพื่อแสดงเฉพาะความคืบหน้า)
+func (o *Optimizer) newBacktester(params StrategyParams, capital float64, start, end time.Time) (*Backtester, error) {
+	bt, err := NewBacktesterSimple(o.symbol, 0, capital)
+	if err != nil {
+		return nil, err
+	}
+	bt.SetLogOutput(io.Discard)
+	if o.config != nil {
+		config := *o.config
+		config.Params = params
+		config.Risk.Capital = capital
+		if err := bt.ApplyConfig(config); err != nil {
+			return nil, err
+		}
+	}
+	if err := bt.SetStrategyParams(params); err != nil {
+		return nil, err
+	}
+	bt.SetIncrementalIndicators(o.incremental)
+	bt.LoadOHLCVData(o.data, start, end)
+	return bt, nil
+}
+
+// evaluate รัน backtest หนึ่งชุดพารามิเตอร์และคำนวณคะแนน
+func (o *Optimizer) evaluate(params StrategyParams, objective Objective) OptimizationRun {
+	run := OptimizationRun{Params: params}
+
+	bt, err := o.newBacktester(params, o.initialCapital, o.start, o.end)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+
+	result, err := o.runner(bt)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+
+	run.TotalReturnPct = result.TotalReturnPct
+	run.SharpeRatio = result.Metrics.SharpeRatio
+	run.MaxDrawdownPct = result.MaxDrawdownPct
+	run.WinRate = result.WinRate
+	run.TotalTrades = result.TotalTrades
+	run.Score = objectiveScore(objective, run)
+	return run
+}
+
+// objectiveScore คะแนนของผลลัพธ์ตาม objective (มากกว่า = ดีกว่า)
+func objectiveScore(objective Objective, run OptimizationRun) float64 {
+	switch objective {
+	case ObjectiveSharpe:
+		return run.SharpeRatio
+	case ObjectiveDrawdown:
+		return -run.MaxDrawdownPct
+	default:
+		return run.TotalReturnPct
+	}
+}
+
+// PrintOptimizationTable เขียนตารางผล optimize top N อันดับลง w
+func PrintOptimizationTable(w io.Writer, runs []OptimizationRun, top int) {
+	if top <= 0 || top > len(runs) {
+		top = len(runs)
+	}
+
+	fmt.Fprintf(w, "\n🏆 ===== ผลการ Optimize (Top %d จาก %d) =====\n", top, len(runs))
+	fmt.Fprintf(w, "%-5s %6s %6s %7s %6s %7s %6s %10s %8s %8s %8s %7s\n",
+		"Rank", "Pivot", "ATR", "Factor", "EMA", "ATRx", "RR", "Return%", "Sharpe", "MaxDD%", "WinRate", "Trades")
+	for _, run := range runs[:top] {
+		if run.Error != "" {
+			fmt.Fprintf(w, "%-5d ❌ %s\n", run.Rank, run.Error)
+			continue
+		}
+		p := run.Params
+		fmt.Fprintf(w, "%-5d %6d %6d %7.2f %6d %7.2f %6.2f %10.2f %8.2f %8.2f %8.2f %7d\n",
+			run.Rank, p.PivotPeriod, p.ATRPeriod, p.ATRFactor, p.EMAPeriod, p.ATRMultiplier, p.RiskReward,
+			run.TotalReturnPct, run.SharpeRatio, run.MaxDrawdownPct, run.WinRate, run.TotalTrades)
+	}
+}
+
+// SaveOptimizationCSV บันทึกตารางผล optimize เป็นไฟล์ CSV
+func SaveOptimizationCSV(runs []OptimizationRun, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถสร้างไฟล์ %s ได้: %v", filename, err)
+	}
+	defer file.Close()
+
+	return WriteOptimizationCSV(file, runs)
+}
+
+// WriteOptimizationCSV เขียนตารางผล optimize (ทุกอันดับ) เป็น CSV ลง w
+func WriteOptimizationCSV(w io.Writer, runs []OptimizationRun) error {
+	writer := csv.NewWriter(w)
+	header := append([]string{"rank"}, ParamNames...)
+	header = append(header, "score", "total_return_pct", "sharpe_ratio", "max_drawdown_pct", "win_rate", "total_trades", "error")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, run := range runs {
+		row := []string{strconv.Itoa(run.Rank)}
+		for _, name := range ParamNames {
+			value, _ := run.Params.Get(name)
+			row = append(row, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		row = append(row,
+			strconv.FormatFloat(run.Score, 'f', 4, 64),
+			strconv.FormatFloat(run.TotalReturnPct, 'f', 4, 64),
+			strconv.FormatFloat(run.SharpeRatio, 'f', 4, 64),
+			strconv.FormatFloat(run.MaxDrawdownPct, 'f', 4, 64),
+			strconv.FormatFloat(run.WinRate, 'f', 2, 64),
+			strconv.Itoa(run.TotalTrades),
+			run.Error,
+		)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,211 @@
+package trading
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// scoreRunner ผลลัพธ์ที่คำนวณจากพารามิเตอร์โดยตรง (ไม่รัน backtest จริง):
+// return = atr_factor x 10, drawdown = risk_reward, sharpe = -atr_factor
+func scoreRunner(bt *Backtester) (*BacktestResult, error) {
+	p := bt.params
+	return &BacktestResult{
+		TotalReturnPct: p.ATRFactor * 10,
+		MaxDrawdownPct: p.RiskReward,
+		TotalTrades:    int(p.ATRFactor),
+		Metrics:        PerformanceMetrics{SharpeRatio: -p.ATRFactor},
+	}, nil
+}
+
+func newQuietOptimizer(data []OHLCV, runner StrategyRunner) *Optimizer {
+	optimizer := NewOptimizer("TEST_USDT", data, 1000, runner)
+	optimizer.SetLogOutput(io.Discard)
+	return optimizer
+}
+
+func TestOptimizerCandidates(t *testing.T) {
+	o := newQuietOptimizer(flatCandles(10), scoreRunner)
+	space := []ParamRange{NewParamRange("atr_factor", 2, 3, 0.5), {Name: "risk_reward", Values: []float64{1.5, 2}}}
+
+	// grid: ทุกชุดของ 3 x 2 ค่า เรียงตามพารามิเตอร์ตัวแรกก่อน ส่วนพารามิเตอร์อื่นใช้ค่าพื้นฐาน
+	grid, err := o.candidates(OptimizerConfig{Space: space, Method: GridSearch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][2]float64
+	for _, p := range grid {
+		got = append(got, [2]float64{p.ATRFactor, p.RiskReward})
+		if p.PivotPeriod != DefaultStrategyParams().PivotPeriod {
+			t.Fatalf("pivot_period %d, ต้องการค่าพื้นฐาน", p.PivotPeriod)
+		}
+	}
+	want := [][2]float64{{2, 1.5}, {2, 2}, {2.5, 1.5}, {2.5, 2}, {3, 1.5}, {3, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("grid %v, ต้องการ %v", got, want)
+	}
+
+	// random: จำนวนตาม samples, ค่าอยู่ในช่วงที่กำหนด และ seed เดียวกันได้ชุดเดิม
+	config := OptimizerConfig{Space: space, Method: RandomSearch, Samples: 20, Seed: 3}
+	random, err := o.candidates(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := o.candidates(config)
+	if len(random) != 20 || !reflect.DeepEqual(random, again) {
+		t.Fatalf("random ได้ %d ชุด (ซ้ำได้ %v), ต้องการ 20 ชุดเดิมทุกครั้ง", len(random), reflect.DeepEqual(random, again))
+	}
+	for _, p := range random {
+		if (p.ATRFactor != 2 && p.ATRFactor != 2.5 && p.ATRFactor != 3) || (p.RiskReward != 1.5 && p.RiskReward != 2) {
+			t.Fatalf("ชุดที่สุ่ม %+v อยู่นอก parameter space", p)
+		}
+	}
+
+	for name, bad := range map[string]OptimizerConfig{
+		"empty space":   {Method: GridSearch},
+		"unknown param": {Space: []ParamRange{{Name: "lookahead", Values: []float64{1}}}, Method: GridSearch},
+		"no values":     {Space: []ParamRange{{Name: "atr_factor"}}, Method: GridSearch},
+		"no samples":    {Space: space, Method: RandomSearch},
+		"method":        {Space: space, Method: "annealing"},
+	} {
+		if _, err := o.candidates(bad); err == nil {
+			t.Errorf("%s: ต้องคืน error", name)
+		}
+	}
+}
+
+func TestOptimizerRankingAndCSV(t *testing.T) {
+	// atr_factor 0 ไม่ผ่าน Validate จึงเป็นชุดที่ error และต้องอยู่ท้ายสุดเสมอ
+	space := []ParamRange{{Name: "atr_factor", Values: []float64{2, 0, 4, 3}}, {Name: "risk_reward", Values: []float64{2, 1}}}
+	for _, tc := range []struct {
+		objective Objective
+		first     [2]float64 // atr_factor, risk_reward ของอันดับ 1
+		score     float64
+	}{
+		{ObjectiveReturn, [2]float64{4, 2}, 40}, // เสมอกันให้ลำดับเดิมของ grid
+		{ObjectiveSharpe, [2]float64{2, 2}, -2},
+		{ObjectiveDrawdown, [2]float64{2, 1}, -1},
+	} {
+		runs, err := newQuietOptimizer(flatCandles(10), scoreRunner).Run(OptimizerConfig{Space: space, Workers: 2, Objective: tc.objective})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(runs) != 8 {
+			t.Fatalf("%s: ได้ %d ชุด, ต้องการ 8", tc.objective, len(runs))
+		}
+		if best := runs[0]; [2]float64{best.Params.ATRFactor, best.Params.RiskReward} != tc.first || best.Score != tc.score {
+			t.Fatalf("%s: อันดับ 1 = %+v คะแนน %v, ต้องการ %v คะแนน %v", tc.objective, best.Params, best.Score, tc.first, tc.score)
+		}
+		for i, run := range runs {
+			if run.Rank != i+1 || (i > 0 && run.Error == "" && runs[i-1].Score < run.Score) {
+				t.Fatalf("%s: อันดับ %d ไม่เรียงตามคะแนน", tc.objective, i+1)
+			}
+			if (run.Error != "") != (i >= 6) {
+				t.Fatalf("%s: อันดับ %d error %q, ต้องการชุดที่ error อยู่สองอันดับสุดท้าย", tc.objective, i+1, run.Error)
+			}
+		}
+	}
+
+	runs, _ := newQuietOptimizer(flatCandles(10), scoreRunner).Run(OptimizerConfig{Space: space, Workers: 1})
+	var buf bytes.Buffer
+	if err := WriteOptimizationCSV(&buf, runs); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(runs)+1 || rows[0][0] != "rank" || rows[0][len(rows[0])-1] != "error" || len(rows[0]) != len(ParamNames)+8 {
+		t.Fatalf("CSV %d แถว header %v", len(rows), rows[0])
+	}
+	column := func(name string) int {
+		for i, h := range rows[0] {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("ไม่มีคอลัมน์ %s", name)
+		return -1
+	}
+	for i, run := range runs {
+		row := rows[i+1]
+		factor, _ := strconv.ParseFloat(row[column("atr_factor")], 64)
+		trades, _ := strconv.Atoi(row[column("total_trades")])
+		if row[0] != strconv.Itoa(run.Rank) || factor != run.Params.ATRFactor || trades != run.TotalTrades || row[column("error")] != run.Error {
+			t.Fatalf("แถว %d = %v, ต้องการ %+v", i+1, row, run)
+		}
+	}
+}
+
+func TestOptimizerParallelMatchesSerial(t *testing.T) {
+	data := syntheticCandles(800, 21)
+	config := OptimizerConfig{
+		Space: []ParamRange{
+			{Name: "atr_factor", Values: []float64{2, 3, 4}},
+			{Name: "atr_multiplier", Values: []float64{1, 2}},
+			{Name: "risk_reward", Values: []float64{1.5, 3}},
+		},
+		Objective: ObjectiveSharpe,
+	}
+	run := func(workers int) []OptimizationRun {
+		o := newQuietOptimizer(data, nil)
+		o.SetIncrementalIndicators(true)
+		config.Workers = workers
+		runs, err := o.Run(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runs
+	}
+
+	serial, parallel := run(1), run(4)
+	if !reflect.DeepEqual(serial, parallel) {
+		t.Fatalf("ผล workers=4 ต่างจาก workers=1:\n%+v\n%+v", parallel, serial)
+	}
+	trades := 0
+	for _, r := range serial {
+		trades += r.TotalTrades
+	}
+	if trades == 0 {
+		t.Fatal("ไม่มีชุดไหนเทรดเลย ข้อมูลทดสอบไม่ครอบคลุม")
+	}
+}
+
+func TestOptimizerAppliesConfig(t *testing.T) {
+	// vCandles เริ่ม 2023-11-14: เทรดเฉพาะตั้งแต่ start_date และทุกการรันใช้ commission/futures จาก config
+	config := DefaultStrategyConfig()
+	config.StartDate, config.EndDate = "2023-11-20", "2023-11-30"
+	config.Risk.Capital = 2000
+	config.Risk.Commission = 0.001
+	config.Risk.Futures = &FuturesAccount{MarginMode: IsolatedMargin, Leverage: 3, MaintenanceMarginRate: 0.005}
+	config.Params.ATRFactor = 2.5
+
+	var results []*BacktestResult
+	runner := func(bt *Backtester) (*BacktestResult, error) {
+		result, err := bt.RunStrategy(&holdStrategy{sizePct: 1, hold: holdForever})
+		results = append(results, result)
+		return result, err
+	}
+	o := newQuietOptimizer(vCandles(20), runner)
+	if err := o.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.Run(OptimizerConfig{Space: []ParamRange{{Name: "risk_reward", Values: []float64{2}}}, Workers: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := results[0]
+	start := time.Date(2023, 11, 20, 0, 0, 0, 0, time.UTC)
+	if entry := result.Trades[0].EntryTime; entry.Before(start) {
+		t.Fatalf("เข้าเทรด %v ก่อน start_date %v (แท่ง warmup ถูกเทรด)", entry, start)
+	}
+	got := result.Config
+	if got.Risk.Capital != 2000 || got.Risk.Commission != 0.001 || got.Risk.Futures == nil || got.Risk.Futures.Leverage != 3 ||
+		got.Params.ATRFactor != 2.5 || got.Params.RiskReward != 2 || result.Trades[0].Leverage != 3 {
+		t.Fatalf("config ของการรัน %+v, ต้องการค่าจาก config และ risk_reward จาก parameter space", got.Risk)
+	}
+}
@@ -0,0 +1,530 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// OrderSide ฝั่งของ order
+type OrderSide string
+
+const (
+	BuyOrder  OrderSide = "BUY"
+	SellOrder OrderSide = "SELL"
+)
+
+// OrderType ประเภท order ที่จำลองได้
+type OrderType string
+
+const (
+	MarketOrder       OrderType = "MARKET"        // fill ทันทีที่ราคาปิดของแท่งที่ส่ง order
+	LimitOrder        OrderType = "LIMIT"         // fill ที่ Price หรือดีกว่า
+	StopMarketOrder   OrderType = "STOP_MARKET"   // ราคาแตะ StopPrice แล้ว fill แบบ market
+	StopLimitOrder    OrderType = "STOP_LIMIT"    // ราคาแตะ StopPrice แล้วกลายเป็น limit ที่ Price
+	TrailingStopOrder OrderType = "TRAILING_STOP" // stop ที่เลื่อนตามราคาสูงสุด/ต่ำสุดตั้งแต่ส่ง order
+)
+
+// TimeInForce อายุของ order
+type TimeInForce string
+
+const (
+	GTC TimeInForce = "GTC" // อยู่จนกว่าจะ fill หรือถูกยกเลิก
+	IOC TimeInForce = "IOC" // fill ทันทีที่ราคาปัจจุบัน ถ้าไม่ได้ยกเลิกทันที
+	GTD TimeInForce = "GTD" // อยู่จนถึง ExpireAt
+)
+
+// OrderStatus สถานะของ order
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "PENDING"
+	OrderTriggered OrderStatus = "TRIGGERED" // stop-limit ที่ถูก trigger แล้ว รอ fill แบบ limit
+	OrderFilled    OrderStatus = "FILLED"
+	OrderCanceled  OrderStatus = "CANCELED"
+	OrderExpired   OrderStatus = "EXPIRED"
+	OrderRejected  OrderStatus = "REJECTED"
+)
+
+// OrderRequest คำสั่งส่ง order
+type OrderRequest struct {
+	Side         OrderSide   `json:"side"`
+	Type         OrderType   `json:"type"`
+	Quantity     float64     `json:"quantity"`      // 0 ได้เฉพาะ reduce-only = ทั้ง position
+	Price        float64     `json:"price"`         // ราคา limit (LIMIT, STOP_LIMIT)
+	StopPrice    float64     `json:"stop_price"`    // ราคา trigger (STOP_MARKET, STOP_LIMIT)
+	TrailAmount  float64     `json:"trail_amount"`  // ระยะ trailing เป็นราคา
+	TrailPercent float64     `json:"trail_percent"` // ระยะ trailing เป็น % (ใช้เมื่อไม่ได้กำหนด TrailAmount)
+	ReduceOnly   bool        `json:"reduce_only"`   // ลด position เท่านั้น (ยกเลิกอัตโนมัติเมื่อไม่มี position)
+	TimeInForce  TimeInForce `json:"time_in_force"` // ค่าว่าง = GTC
+	ExpireAt     time.Time   `json:"expire_at"`     // สำหรับ GTD
+
+	// ใช้เมื่อ order เปิด position ใหม่
+	StopLoss   float64 `json:"stop_loss"`
+	TakeProfit float64 `json:"take_profit"`
+	Leverage   float64 `json:"leverage"`
+	Reason     string  `json:"reason"` // เหตุผลเข้า/ออกที่บันทึกในเทรด (ค่าว่าง = ประเภท order)
+}
+
+// Order order ที่ส่งเข้า order book ของ backtester
+type Order struct {
+	ID int `json:"id"`
+	OrderRequest
+	Status       OrderStatus `json:"status"`
+	StatusReason string      `json:"status_reason,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	TriggeredAt  time.Time   `json:"triggered_at,omitempty"`
+	FilledAt     time.Time   `json:"filled_at,omitempty"`
+	FillPrice    float64     `json:"fill_price,omitempty"`
+	FillQuantity float64     `json:"fill_quantity,omitempty"`
+
+	createdIndex int     // แท่งที่ส่ง order (เริ่มจับคู่ได้ตั้งแต่แท่งถัดไป)
+	anchor       float64 // ราคาสูงสุด (SELL) / ต่ำสุด (BUY) ตั้งแต่ส่ง trailing stop
+}
+
+// Active order ยังรอ fill อยู่หรือไม่
+func (o *Order) Active() bool {
+	return o.Status == OrderPending || o.Status == OrderTriggered
+}
+
+// trailingStop ราคา stop ปัจจุบันของ trailing stop
+func (o *Order) trailingStop() float64 {
+	distance := o.TrailAmount
+	if distance <= 0 {
+		distance = o.anchor * o.TrailPercent / 100
+	}
+	if o.Side == SellOrder {
+		return o.anchor - distance
+	}
+	return o.anchor + distance
+}
+
+// fillableAt ราคาที่ order จะ fill หรือ trigger เมื่อราคาเคลื่อนจาก from ไป to แบบต่อเนื่อง
+// (ถ้าที่ from ก็ทำงานได้แล้ว คืน from)
+func (o *Order) fillableAt(from, to float64) (float64, bool) {
+	buy := o.Side == BuyOrder
+
+	// reached: ราคา x ผ่านระดับ level ในทิศที่ทำให้ order ทำงานหรือยัง
+	var level float64
+	var reached func(x float64) bool
+
+	switch {
+	case o.Type == LimitOrder || (o.Type == StopLimitOrder && o.Status == OrderTriggered):
+		level = o.Price
+		if buy {
+			reached = func(x float64) bool { return x <= level }
+		} else {
+			reached = func(x float64) bool { return x >= level }
+		}
+	case o.Type == StopMarketOrder || o.Type == StopLimitOrder:
+		level = o.StopPrice
+		if buy {
+			reached = func(x float64) bool { return x >= level }
+		} else {
+			reached = func(x float64) bool { return x <= level }
+		}
+	case o.Type == TrailingStopOrder:
+		level = o.trailingStop()
+		if buy {
+			reached = func(x float64) bool { return x >= level }
+		} else {
+			reached = func(x float64) bool { return x <= level }
+		}
+	default:
+		return 0, false
+	}
+
+	if reached(from) {
+		return from, true
+	}
+	if reached(to) {
+		return level, true
+	}
+	return 0, false
+}
+
+// updateAnchor เลื่อนจุดอ้างอิงของ trailing stop ตามราคาที่ผ่าน
+func (o *Order) updateAnchor(price float64) {
+	if o.Type != TrailingStopOrder {
+		return
+	}
+	if o.Side == SellOrder {
+		o.anchor = math.Max(o.anchor, price)
+	} else {
+		o.anchor = math.Min(o.anchor, price)
+	}
+}
+
+// validate ตรวจความครบถ้วนของคำสั่ง
+func (req *OrderRequest) validate(now time.Time) error {
+	if req.Side != BuyOrder && req.Side != SellOrder {
+		return fmt.Errorf("ฝั่งของ order ไม่ถูกต้อง: %q", req.Side)
+	}
+	if req.Quantity < 0 || (req.Quantity == 0 && !req.ReduceOnly) {
+		return fmt.Errorf("ปริมาณของ order ต้องมากกว่า 0")
+	}
+
+	switch req.Type {
+	case MarketOrder:
+	case LimitOrder:
+		if req.Price <= 0 {
+			return fmt.Errorf("limit order ต้องกำหนด Price")
+		}
+	case StopMarketOrder:
+		if req.StopPrice <= 0 {
+			return fmt.Errorf("stop order ต้องกำหนด StopPrice")
+		}
+	case StopLimitOrder:
+		if req.StopPrice <= 0 || req.Price <= 0 {
+			return fmt.Errorf("stop-limit order ต้องกำหนด StopPrice และ Price")
+		}
+	case TrailingStopOrder:
+		if req.TrailAmount <= 0 && req.TrailPercent <= 0 {
+			return fmt.Errorf("trailing stop ต้องกำหนด TrailAmount หรือ TrailPercent")
+		}
+	default:
+		return fmt.Errorf("ไม่รู้จักประเภท order: %q", req.Type)
+	}
+
+	switch req.TimeInForce {
+	case GTC:
+	case IOC:
+		if req.Type != MarketOrder && req.Type != LimitOrder {
+			return fmt.Errorf("IOC ใช้ได้กับ market และ limit order เท่านั้น")
+		}
+	case GTD:
+		if !req.ExpireAt.After(now) {
+			return fmt.Errorf("GTD order ต้องมี ExpireAt หลังเวลาปัจจุบัน")
+		}
+	default:
+		return fmt.Errorf("ไม่รู้จัก time in force: %q", req.TimeInForce)
+	}
+
+	return nil
+}
+
+// PlaceOrder ส่ง order เข้า order book
+// market และ IOC จับคู่ทันทีที่ราคาปิดของแท่งปัจจุบัน ส่วน order อื่นเริ่มจับคู่ตั้งแต่แท่งถัดไปตามเส้นทาง OHLC
+func (bt *Backtester) PlaceOrder(req OrderRequest) (*Order, error) {
+	if req.TimeInForce == "" {
+		req.TimeInForce = GTC
+	}
+	if err := req.validate(bt.currentTime); err != nil {
+		return nil, err
+	}
+
+	bt.orderID++
+	order := &Order{
+		ID:           bt.orderID,
+		OrderRequest: req,
+		Status:       OrderPending,
+		CreatedAt:    bt.currentTime,
+		createdIndex: bt.currentIndex,
+		anchor:       bt.currentPrice,
+	}
+	bt.orders = append(bt.orders, order)
+
+	bt.logf("📝 Order #%d: %s %s %.6f (price %.4f, stop %.4f, %s%s)\n",
+		order.ID, order.Side, order.Type, order.Quantity, order.Price, order.StopPrice,
+		order.TimeInForce, map[bool]string{true: ", reduce-only", false: ""}[order.ReduceOnly])
+
+	switch {
+	case req.Type == MarketOrder:
+		bt.fillOrder(order, bt.currentPrice)
+	case req.TimeInForce == IOC:
+		if price, ok := order.fillableAt(bt.currentPrice, bt.currentPrice); ok {
+			bt.fillOrder(order, price)
+		} else {
+			bt.finishOrder(order, OrderCanceled, "IOC ไม่ได้ fill ทันที")
+		}
+	}
+
+	return order, nil
+}
+
+// CancelOrder ยกเลิก order ที่ยังรอ fill
+func (bt *Backtester) CancelOrder(id int) error {
+	order := bt.findOrder(id)
+	if order == nil {
+		return fmt.Errorf("ไม่พบ order #%d", id)
+	}
+	if !order.Active() {
+		return fmt.Errorf("order #%d ไม่ได้รอ fill แล้ว (%s)", id, order.Status)
+	}
+	bt.finishOrder(order, OrderCanceled, "ยกเลิกโดยกลยุทธ์")
+	return nil
+}
+
+// ReplaceOrder ยกเลิก order เดิมแล้วส่ง order ใหม่ที่ใช้ค่าเดิม ยกเว้น price/stopPrice/quantity ที่ไม่เป็น 0
+func (bt *Backtester) ReplaceOrder(id int, price, stopPrice, quantity float64) (*Order, error) {
+	order := bt.findOrder(id)
+	if order == nil {
+		return nil, fmt.Errorf("ไม่พบ order #%d", id)
+	}
+	if !order.Active() {
+		return nil, fmt.Errorf("order #%d ไม่ได้รอ fill แล้ว (%s)", id, order.Status)
+	}
+
+	req := order.OrderRequest
+	if price > 0 {
+		req.Price = price
+	}
+	if stopPrice > 0 {
+		req.StopPrice = stopPrice
+	}
+	if quantity > 0 {
+		req.Quantity = quantity
+	}
+	if err := req.validate(bt.currentTime); err != nil {
+		return nil, err
+	}
+
+	bt.finishOrder(order, OrderCanceled, "แทนที่ด้วย order ใหม่")
+	return bt.PlaceOrder(req)
+}
+
+// OpenOrders order ที่ยังรอ fill
+func (bt *Backtester) OpenOrders() []*Order {
+	var open []*Order
+	for _, order := range bt.orders {
+		if order.Active() {
+			open = append(open, order)
+		}
+	}
+	return open
+}
+
+// PlaceGateStopLossOrder จำลอง GateClient.CreateStopLossOrder: limit order แบบ GTC reduce-only
+// ที่ราคา stopPrice ฝั่งตรงข้ามกับ position ทั้งขนาด
+// (limit ขายที่ต่ำกว่าราคาตลาดจะ fill ทันทีที่ราคาเปิดแท่งถัดไป เหมือนบน exchange จริง)
+// reason คือเหตุผลออกที่บันทึกในเทรด (ค่าว่าง = "GATE_STOP_LOSS")
+func (bt *Backtester) PlaceGateStopLossOrder(stopPrice float64, reason string) (*Order, error) {
+	if bt.position == nil {
+		return nil, fmt.Errorf("ไม่มี position สำหรับตั้ง stop loss")
+	}
+	if reason == "" {
+		reason = "GATE_STOP_LOSS"
+	}
+
+	side := SellOrder
+	if bt.position.Side == "SHORT" {
+		side = BuyOrder
+	}
+	return bt.PlaceOrder(OrderRequest{
+		Side:        side,
+		Type:        LimitOrder,
+		Price:       stopPrice,
+		ReduceOnly:  true,
+		TimeInForce: GTC,
+		Reason:      reason,
+	})
+}
+
+func (bt *Backtester) findOrder(id int) *Order {
+	for _, order := range bt.orders {
+		if order.ID == id {
+			return order
+		}
+	}
+	return nil
+}
+
+// finishOrder ปิด order ด้วยสถานะสุดท้าย
+func (bt *Backtester) finishOrder(order *Order, status OrderStatus, reason string) {
+	order.Status = status
+	order.StatusReason = reason
+	bt.logf("🗑️ Order #%d %s: %s\n", order.ID, status, reason)
+}
+
+// fillOrder จับคู่ order ที่ราคาที่กำหนด: เปิด position ใหม่ หรือลด/ปิด position ฝั่งตรงข้าม
+// backtester ถือได้ครั้งละหนึ่ง position จึงปฏิเสธ order ที่จะเพิ่มขนาดหรือกลับฝั่ง position
+// order ที่เปิด position ผ่าน entryLimit เดียวกับสัญญาณเข้าของกลยุทธ์และถูกจำกัดไม่ให้เกินเงินทุน (ขนาดเหลือ 0 = ปฏิเสธ)
+func (bt *Backtester) fillOrder(order *Order, price float64) {
+	pos := bt.position
+	side := "LONG"
+	if order.Side == SellOrder {
+		side = "SHORT"
+	}
+	reason := order.Reason
+	if reason == "" {
+		reason = string(order.Type)
+	}
+
+	switch {
+	case pos == nil && order.ReduceOnly:
+		bt.finishOrder(order, OrderCanceled, "ไม่มี position ให้ลด")
+		return
+	case pos == nil:
+		signal := &EntrySignal{
+			Side:       side,
+			StopLoss:   order.StopLoss,
+			TakeProfit: order.TakeProfit,
+			Leverage:   order.Leverage,
+			Reason:     reason,
+		}
+		quantity := bt.affordableQuantity(bt.applyEntryLimit(signal, order.Quantity, price), price)
+		if quantity <= 0 {
+			bt.finishOrder(order, OrderRejected, "เงินทุนไม่พอหรือถูกจำกัดขนาดเป็น 0")
+			return
+		}
+		bt.openPositionAt(signal, quantity, price)
+		order.FillQuantity = bt.position.Quantity
+	case pos.Side == side:
+		bt.finishOrder(order, OrderRejected, "ไม่รองรับการเพิ่มขนาด position ด้วย order")
+		return
+	default:
+		quantity := order.Quantity
+		if quantity <= 0 || quantity > pos.Quantity {
+			quantity = pos.Quantity
+		}
+		order.FillQuantity = quantity
+		bt.closeQuantityAt(price, quantity, reason)
+	}
+
+	order.Status = OrderFilled
+	order.FilledAt = bt.currentTime
+	order.FillPrice = price
+	bt.logf("✅ Order #%d FILLED: %s %.6f @ $%.4f\n", order.ID, order.Side, order.FillQuantity, price)
+
+	// position ถูกปิดแล้ว: reduce-only ที่เหลือไม่มีอะไรให้ลด
+	if bt.position == nil {
+		bt.cancelReduceOnly()
+	}
+}
+
+// affordableQuantity จำกัดขนาดที่เปิดใหม่หรือเพิ่มเข้า position เดิมไม่ให้เกินเงินทุนที่ยังว่าง
+// (futures ลดขนาดตาม margin ใน applyMargin/checkPyramid อยู่แล้ว ส่วน spot ใช้ notional เต็ม
+// และหัก notional ของ position ที่ถืออยู่)
+func (bt *Backtester) affordableQuantity(quantity, price float64) float64 {
+	if quantity <= 0 || bt.currentCapital <= 0 {
+		return 0
+	}
+	if bt.futures != nil {
+		return quantity
+	}
+	available := bt.currentCapital
+	if bt.position != nil {
+		available -= bt.position.EntryPrice * bt.position.Quantity
+	}
+	if available <= 0 {
+		return 0
+	}
+	if quantity*price > available {
+		quantity = available / price
+		bt.logf("⚠️ เงินทุนไม่พอ ลดขนาดเหลือ %.6f\n", quantity)
+	}
+	return quantity
+}
+
+// cancelReduceOnly ยกเลิก reduce-only order ที่ยังรอ fill เมื่อไม่มี position
+func (bt *Backtester) cancelReduceOnly() {
+	for _, order := range bt.orders {
+		if order.Active() && order.ReduceOnly {
+			bt.finishOrder(order, OrderCanceled, "position ถูกปิดแล้ว")
+		}
+	}
+}
+
+// ohlcPath เส้นทางราคาภายในแท่ง: แท่งเขียว O→L→H→C, แท่งแดง O→H→L→C
+func ohlcPath(candle OHLCV) [4]float64 {
+	if candle.Close >= candle.Open {
+		return [4]float64{candle.Open, candle.Low, candle.High, candle.Close}
+	}
+	return [4]float64{candle.Open, candle.High, candle.Low, candle.Close}
+}
+
+// processOrders จับคู่ order ที่รออยู่กับเส้นทางราคาของแท่งปัจจุบัน
+// order ที่ราคาแตะก่อนบนเส้นทางถูก fill ก่อน, gap ข้ามระดับตอนเปิดแท่ง fill ที่ราคาเปิด
+// position ที่เปิดจาก order ระหว่างแท่งเริ่มตรวจ SL/TP ตั้งแต่แท่งถัดไป
+func (bt *Backtester) processOrders(candle OHLCV) {
+	if len(bt.orders) == 0 {
+		return
+	}
+
+	for _, order := range bt.orders {
+		if !order.Active() {
+			continue
+		}
+		if order.TimeInForce == GTD && !bt.currentTime.Before(order.ExpireAt) {
+			bt.finishOrder(order, OrderExpired, "หมดอายุ GTD")
+		}
+	}
+	if bt.position == nil {
+		bt.cancelReduceOnly()
+	}
+
+	path := ohlcPath(candle)
+	current := path[0]
+	bt.updateAnchors(current)
+
+	// เดินตามเส้นทางทีละช่วง (ช่วงแรก from = to = ราคาเปิด สำหรับ gap)
+	for i := 0; i < len(path); i++ {
+		target := path[i]
+		for {
+			order, price := bt.nextOrderEvent(current, target)
+			if order == nil {
+				break
+			}
+			current = price
+
+			if order.Type == StopLimitOrder && order.Status == OrderPending {
+				order.Status = OrderTriggered
+				order.TriggeredAt = bt.currentTime
+				bt.logf("⚡ Order #%d TRIGGERED ที่ $%.4f → limit $%.4f\n", order.ID, price, order.Price)
+				continue
+			}
+			bt.fillOrder(order, price)
+		}
+		current = target
+		bt.updateAnchors(current)
+	}
+}
+
+// nextOrderEvent order ที่ทำงานเป็นอันดับแรกเมื่อราคาเคลื่อนจาก from ไป to (ใกล้ from ที่สุด, เสมอกันใช้ order ที่ส่งก่อน)
+func (bt *Backtester) nextOrderEvent(from, to float64) (*Order, float64) {
+	var next *Order
+	var nextPrice float64
+	for _, order := range bt.orders {
+		if !order.Active() || order.createdIndex >= bt.currentIndex {
+			continue
+		}
+		price, ok := order.fillableAt(from, to)
+		if !ok {
+			continue
+		}
+		if next == nil || math.Abs(price-from) < math.Abs(nextPrice-from) {
+			next, nextPrice = order, price
+		}
+	}
+	return next, nextPrice
+}
+
+// updateAnchors เลื่อน trailing stop ทุกตัวตามราคาที่ผ่าน
+func (bt *Backtester) updateAnchors(price float64) {
+	for _, order := range bt.orders {
+		if order.Active() && order.createdIndex < bt.currentIndex {
+			order.updateAnchor(price)
+		}
+	}
+}
+
+// cancelOpenOrders ยกเลิก order ที่เหลือทั้งหมด (ตอนจบ backtest)
+func (bt *Backtester) cancelOpenOrders(reason string) {
+	for _, order := range bt.orders {
+		if order.Active() {
+			bt.finishOrder(order, OrderCanceled, reason)
+		}
+	}
+}
+
+// orderHistory สำเนาของ order ทั้งหมดสำหรับผลลัพธ์
+func (bt *Backtester) orderHistory() []Order {
+	if len(bt.orders) == 0 {
+		return nil
+	}
+	history := make([]Order, len(bt.orders))
+	for i, order := range bt.orders {
+		history[i] = *order
+	}
+	return history
+}
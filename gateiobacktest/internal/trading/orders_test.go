@@ -0,0 +1,274 @@
+package trading
+
+import (
+	"testing"
+	"time"
+)
+
+// orderStrategy ส่ง order จาก OnBar ตาม index ของแท่ง และไม่มีสัญญาณเข้า/ออกเอง
+type orderStrategy struct {
+	scriptedStrategy
+	bars map[int]func(bt *Backtester)
+}
+
+func (s *orderStrategy) OnBar(bt *Backtester) {
+	if place := s.bars[bt.currentIndex]; place != nil {
+		place(bt)
+	}
+}
+
+// mustPlace ส่ง order และหยุด test ถ้าคำสั่งไม่ผ่านการตรวจ
+func mustPlace(t *testing.T, bt *Backtester, req OrderRequest) *Order {
+	t.Helper()
+	order, err := bt.PlaceOrder(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func TestOrderFillsFollowOHLCPath(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		bar             OHLCV
+		filled, refused int // index ของ order (0 = limit 99, 1 = stop 101)
+		price           float64
+	}{
+		// แท่งเขียว O→L→H→C: ราคาลงแตะ limit ก่อนขึ้นไปแตะ stop
+		{"green", candle(100, 102, 98, 101.5), 0, 1, 99},
+		// แท่งแดง O→H→L→C: stop ทำงานก่อน
+		{"red", candle(100, 102, 98, 99), 1, 0, 101},
+		// gap เปิดต่ำกว่า limit: fill ที่ราคาเปิด
+		{"gap", candle(97, 97.5, 96, 97), 0, -1, 97},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var orders []*Order
+			strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+				0: func(bt *Backtester) {
+					orders = append(orders,
+						mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: LimitOrder, Quantity: 1, Price: 99}),
+						mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: StopMarketOrder, Quantity: 1, StopPrice: 101}))
+				},
+			}}
+			runScripted(t, hourly(candle(100, 100, 100, 100), tc.bar, candle(100, 100, 100, 100)), strategy, nil)
+
+			filled := orders[tc.filled]
+			if filled.Status != OrderFilled || filled.FillPrice != tc.price {
+				t.Fatalf("order #%d: %s @ %v, ต้องการ FILLED @ %v", filled.ID, filled.Status, filled.FillPrice, tc.price)
+			}
+			if tc.refused >= 0 {
+				// backtester ไม่เพิ่มขนาด position ด้วย order
+				if refused := orders[tc.refused]; refused.Status != OrderRejected {
+					t.Fatalf("order #%d: %s, ต้องการ REJECTED", refused.ID, refused.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestStopLimitTriggersThenFills(t *testing.T) {
+	data := hourly(
+		candle(100, 100, 100, 100),
+		candle(100, 103, 100, 102.5), // แตะ stop 102 แต่ไม่กลับลงมาถึง limit 101.5
+		candle(102.5, 102.5, 101, 101.2),
+	)
+	var order *Order
+	strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+		0: func(bt *Backtester) {
+			order = mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: StopLimitOrder, Quantity: 1, StopPrice: 102, Price: 101.5})
+		},
+		1: func(bt *Backtester) {
+			if order.Status != OrderTriggered {
+				t.Fatalf("หลังแท่งที่แตะ stop: %s, ต้องการ TRIGGERED", order.Status)
+			}
+		},
+	}}
+	runScripted(t, data, strategy, nil)
+
+	if order.Status != OrderFilled || order.FillPrice != 101.5 {
+		t.Fatalf("%s @ %v, ต้องการ FILLED @ 101.5", order.Status, order.FillPrice)
+	}
+	if !order.TriggeredAt.Equal(time.Unix(data[1].Timestamp, 0)) || !order.FilledAt.Equal(time.Unix(data[2].Timestamp, 0)) {
+		t.Fatalf("trigger %v fill %v", order.TriggeredAt, order.FilledAt)
+	}
+}
+
+func TestTrailingStopFollowsHigh(t *testing.T) {
+	data := hourly(
+		candle(100, 100, 100, 100),
+		candle(100, 105, 100, 104.5), // จุดสูงสุดใหม่ 105 → stop 103
+		candle(104.5, 104.5, 102, 102.5),
+		candle(102.5, 102.5, 102.5, 102.5),
+	)
+	var trail *Order
+	strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+		0: func(bt *Backtester) {
+			mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: MarketOrder, Quantity: 1})
+			trail = mustPlace(t, bt, OrderRequest{Side: SellOrder, Type: TrailingStopOrder, TrailAmount: 2, ReduceOnly: true})
+		},
+	}}
+	result := runScripted(t, data, strategy, nil)
+
+	if trail.Status != OrderFilled || trail.FillPrice != 103 || trail.FillQuantity != 1 {
+		t.Fatalf("trailing stop %s @ %v × %v, ต้องการ FILLED @ 103 × 1", trail.Status, trail.FillPrice, trail.FillQuantity)
+	}
+	if len(result.Trades) != 1 || result.Trades[0].ExitPrice != 103 || result.Trades[0].ExitReason != string(TrailingStopOrder) {
+		t.Fatalf("trades %+v", result.Trades)
+	}
+}
+
+func TestOrderTimeInForce(t *testing.T) {
+	data := hourly(
+		candle(100, 100, 100, 100),
+		candle(100, 100.5, 99.5, 100),
+		candle(100, 100.5, 99.5, 100),
+		candle(100, 100.5, 85, 100), // แตะ 90 หลัง GTD หมดอายุแล้ว
+	)
+	var missed, hit, gtd *Order
+	strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+		0: func(bt *Backtester) {
+			missed = mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: LimitOrder, Quantity: 1, Price: 99, TimeInForce: IOC})
+			gtd = mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: LimitOrder, Quantity: 1, Price: 90,
+				TimeInForce: GTD, ExpireAt: bt.currentTime.Add(2 * time.Hour)})
+			hit = mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: LimitOrder, Quantity: 1, Price: 101, TimeInForce: IOC})
+		},
+	}}
+	runScripted(t, data, strategy, nil)
+
+	if missed.Status != OrderCanceled {
+		t.Fatalf("IOC ที่ไม่ถึง limit: %s, ต้องการ CANCELED", missed.Status)
+	}
+	if hit.Status != OrderFilled || hit.FillPrice != 100 {
+		t.Fatalf("IOC ที่ถึง limit: %s @ %v, ต้องการ FILLED @ 100", hit.Status, hit.FillPrice)
+	}
+	if gtd.Status != OrderExpired {
+		t.Fatalf("GTD: %s, ต้องการ EXPIRED", gtd.Status)
+	}
+}
+
+func TestReplaceAndCancelOrder(t *testing.T) {
+	data := hourly(
+		candle(100, 100, 100, 100),
+		candle(100, 100.5, 99.5, 100),
+		candle(100, 100.5, 98, 99.5),
+	)
+	var original, replaced *Order
+	strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+		0: func(bt *Backtester) {
+			original = mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: LimitOrder, Quantity: 1, Price: 95, StopLoss: 90})
+		},
+		1: func(bt *Backtester) {
+			var err error
+			if replaced, err = bt.ReplaceOrder(original.ID, 99, 0, 2); err != nil {
+				t.Fatal(err)
+			}
+			if err := bt.CancelOrder(original.ID); err == nil {
+				t.Fatal("ยกเลิก order ที่ถูกแทนที่แล้วต้องคืน error")
+			}
+		},
+	}}
+	runScripted(t, data, strategy, nil)
+
+	if original.Status != OrderCanceled {
+		t.Fatalf("order เดิม: %s, ต้องการ CANCELED", original.Status)
+	}
+	if replaced.Status != OrderFilled || replaced.FillPrice != 99 || replaced.FillQuantity != 2 || replaced.StopLoss != 90 {
+		t.Fatalf("order ใหม่: %s @ %v × %v SL %v", replaced.Status, replaced.FillPrice, replaced.FillQuantity, replaced.StopLoss)
+	}
+}
+
+func TestReduceOnlyCanceledWhenPositionCloses(t *testing.T) {
+	data := hourly(
+		candle(100, 100, 100, 100),
+		candle(100, 100.5, 94, 95),
+		candle(95, 111, 95, 110),
+	)
+	var takeProfit, stop, orphan *Order
+	strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+		0: func(bt *Backtester) {
+			// reduce-only ตอนไม่มี position ไม่มีอะไรให้ลด
+			orphan = mustPlace(t, bt, OrderRequest{Side: SellOrder, Type: MarketOrder, ReduceOnly: true})
+			mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: MarketOrder, Quantity: 1})
+			takeProfit = mustPlace(t, bt, OrderRequest{Side: SellOrder, Type: LimitOrder, Price: 110, ReduceOnly: true})
+			stop = mustPlace(t, bt, OrderRequest{Side: SellOrder, Type: StopMarketOrder, StopPrice: 96, ReduceOnly: true})
+		},
+	}}
+	result := runScripted(t, data, strategy, nil)
+
+	if orphan.Status != OrderCanceled {
+		t.Fatalf("reduce-only ที่ไม่มี position: %s, ต้องการ CANCELED", orphan.Status)
+	}
+	if stop.Status != OrderFilled || stop.FillPrice != 96 {
+		t.Fatalf("stop: %s @ %v, ต้องการ FILLED @ 96", stop.Status, stop.FillPrice)
+	}
+	if takeProfit.Status != OrderCanceled || takeProfit.StatusReason != "position ถูกปิดแล้ว" {
+		t.Fatalf("take profit: %s (%s), ต้องการ CANCELED เมื่อ position ถูกปิด", takeProfit.Status, takeProfit.StatusReason)
+	}
+	if len(result.Trades) != 1 {
+		t.Fatalf("ได้ %d เทรด, ต้องการ 1", len(result.Trades))
+	}
+}
+
+func TestOrderEntryRespectsEntryLimit(t *testing.T) {
+	data := hourly(candle(100, 100, 100, 100), candle(100, 100.5, 97, 100), candle(100, 100, 100, 100))
+	var limitPrices []float64
+	var blocked, capped *Order
+	strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+		0: func(bt *Backtester) {
+			blocked = mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: MarketOrder, Quantity: 1})
+			capped = mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: LimitOrder, Quantity: 50, Price: 98})
+		},
+	}}
+	runScripted(t, data, strategy, func(bt *Backtester) {
+		// ห้ามเปิดที่แท่งแรก จากนั้นปล่อยผ่านทั้งขนาด
+		bt.entryLimit = func(bt *Backtester, signal *EntrySignal, quantity, price float64) float64 {
+			limitPrices = append(limitPrices, price)
+			if bt.currentIndex == 0 {
+				return 0
+			}
+			return quantity
+		}
+	})
+
+	if blocked.Status != OrderRejected {
+		t.Fatalf("order ที่ entryLimit คืน 0: %s, ต้องการ REJECTED", blocked.Status)
+	}
+	// entryLimit เห็นราคา fill ไม่ใช่ราคาปิด และขนาดถูกจำกัดด้วยเงินทุน $1000
+	if len(limitPrices) != 2 || limitPrices[1] != 98 {
+		t.Fatalf("ราคาที่ส่งให้ entryLimit %v, ต้องการ [100 98]", limitPrices)
+	}
+	if capped.Status != OrderFilled || !approx(capped.FillQuantity, 1000/98.0) {
+		t.Fatalf("order ที่เกินเงินทุน: %s × %v, ต้องการ FILLED × %v", capped.Status, capped.FillQuantity, 1000/98.0)
+	}
+}
+
+func TestGateStopLossOrderReason(t *testing.T) {
+	// เข้า LONG ที่ 100 แล้วตั้ง stop แบบ Gate.io (limit ขาย reduce-only) ที่ 95: fill ที่ราคาเปิดแท่งถัดไป
+	data := hourly(candle(100, 100, 100, 100), candle(100, 100, 100, 100), candle(99, 99, 98, 98))
+	for _, tc := range []struct{ reason, want string }{
+		{"", "GATE_STOP_LOSS"},
+		{"t-stop-5pct", "t-stop-5pct"},
+	} {
+		var order *Order
+		strategy := &orderStrategy{
+			scriptedStrategy: scriptedStrategy{entries: map[int]*EntrySignal{0: {Side: "LONG", Reason: "test"}}, quantity: 1},
+			bars: map[int]func(bt *Backtester){
+				1: func(bt *Backtester) {
+					var err error
+					if order, err = bt.PlaceGateStopLossOrder(95, tc.reason); err != nil {
+						t.Fatal(err)
+					}
+				},
+			},
+		}
+		result := runScripted(t, data, strategy, nil)
+		if order.Reason != tc.want || len(result.Trades) != 1 || result.Trades[0].ExitReason != tc.want || result.Trades[0].ExitPrice != 99 {
+			t.Fatalf("reason %q: order %q เทรด %+v, ต้องการปิดด้วย %q ที่ 99", tc.reason, order.Reason, result.Trades, tc.want)
+		}
+	}
+
+	bt := newQuietBacktester(t, "TEST_USDT", 1000)
+	if _, err := bt.PlaceGateStopLossOrder(95, ""); err == nil {
+		t.Fatal("ไม่มี position ต้องคืน error")
+	}
+}
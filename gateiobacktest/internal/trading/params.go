@@ -0,0 +1,140 @@
+package trading
+
+import "fmt"
+
+// StrategyParams พารามิเตอร์ที่ปรับได้ของกลยุทธ์ Pivot Point SuperTrend + EMA
+type StrategyParams struct {
+	PivotPeriod   int     `json:"pivot_period" yaml:"pivot_period"`
+	ATRPeriod     int     `json:"atr_period" yaml:"atr_period"`
+	ATRFactor     float64 `json:"atr_factor" yaml:"atr_factor"`
+	EMAPeriod     int     `json:"ema_period" yaml:"ema_period"`
+	ATRMultiplier float64 `json:"atr_multiplier" yaml:"atr_multiplier"` // ระยะ Stop Loss = ATR x multiplier
+	RiskReward    float64 `json:"risk_reward" yaml:"risk_reward"`       // ระยะ Take Profit = ระยะ SL x RiskReward
+	MaxLookback   int     `json:"max_lookback" yaml:"max_lookback"`     // จำนวนแท่งย้อนหลังที่ใช้วิเคราะห์ (อย่างน้อยเท่า EMA period)
+
+	// เกณฑ์สัญญาณแข็งแกร่ง (isStrongSignal)
+	MinConfidence float64 `json:"min_confidence" yaml:"min_confidence"`   // confidence ขั้นต่ำของ SuperTrend (%)
+	MinRiskReward float64 `json:"min_risk_reward" yaml:"min_risk_reward"` // R:R ขั้นต่ำจากการวิเคราะห์
+	MinConditions int     `json:"min_conditions" yaml:"min_conditions"`   // จำนวนเงื่อนไขที่ต้องผ่านจาก 5 ข้อ
+}
+
+// ParamNames ชื่อพารามิเตอร์ที่ใช้กับ Set/Get (เช่นใน parameter space ของ optimizer)
+var ParamNames = []string{
+	"pivot_period", "atr_period", "atr_factor", "ema_period", "atr_multiplier", "risk_reward",
+	"max_lookback", "min_confidence", "min_risk_reward", "min_conditions",
+}
+
+// DefaultStrategyParams ค่าเริ่มต้นตาม Pine Script และ calculateRiskReward / isStrongSignal เดิม
+func DefaultStrategyParams() StrategyParams {
+	return StrategyParams{
+		PivotPeriod:   2,
+		ATRPeriod:     10,
+		ATRFactor:     3.0,
+		EMAPeriod:     100,
+		ATRMultiplier: 1.5,
+		RiskReward:    2.5,
+		MaxLookback:   100,
+		MinConfidence: 70.0,
+		MinRiskReward: 1.5,
+		MinConditions: 4,
+	}
+}
+
+// Set กำหนดค่าพารามิเตอร์ตามชื่อ
+func (p *StrategyParams) Set(name string, value float64) error {
+	switch name {
+	case "pivot_period":
+		p.PivotPeriod = int(value)
+	case "atr_period":
+		p.ATRPeriod = int(value)
+	case "atr_factor":
+		p.ATRFactor = value
+	case "ema_period":
+		p.EMAPeriod = int(value)
+	case "atr_multiplier":
+		p.ATRMultiplier = value
+	case "risk_reward":
+		p.RiskReward = value
+	case "max_lookback":
+		p.MaxLookback = int(value)
+	case "min_confidence":
+		p.MinConfidence = value
+	case "min_risk_reward":
+		p.MinRiskReward = value
+	case "min_conditions":
+		p.MinConditions = int(value)
+	default:
+		return fmt.Errorf("ไม่รู้จักพารามิเตอร์: %s", name)
+	}
+	return nil
+}
+
+// Get อ่านค่าพารามิเตอร์ตามชื่อ
+func (p StrategyParams) Get(name string) (float64, error) {
+	switch name {
+	case "pivot_period":
+		return float64(p.PivotPeriod), nil
+	case "atr_period":
+		return float64(p.ATRPeriod), nil
+	case "atr_factor":
+		return p.ATRFactor, nil
+	case "ema_period":
+		return float64(p.EMAPeriod), nil
+	case "atr_multiplier":
+		return p.ATRMultiplier, nil
+	case "risk_reward":
+		return p.RiskReward, nil
+	case "max_lookback":
+		return float64(p.MaxLookback), nil
+	case "min_confidence":
+		return p.MinConfidence, nil
+	case "min_risk_reward":
+		return p.MinRiskReward, nil
+	case "min_conditions":
+		return float64(p.MinConditions), nil
+	}
+	return 0, fmt.Errorf("ไม่รู้จักพารามิเตอร์: %s", name)
+}
+
+// Validate ตรวจสอบว่าพารามิเตอร์ใช้งานได้
+func (p StrategyParams) Validate() error {
+	if p.PivotPeriod < 1 || p.ATRPeriod < 1 || p.EMAPeriod < 1 {
+		return fmt.Errorf("period ต้องมากกว่า 0 (pivot=%d, atr=%d, ema=%d)", p.PivotPeriod, p.ATRPeriod, p.EMAPeriod)
+	}
+	if p.ATRFactor <= 0 || p.ATRMultiplier <= 0 || p.RiskReward <= 0 {
+		return fmt.Errorf("atr_factor, atr_multiplier และ risk_reward ต้องมากกว่า 0")
+	}
+	if p.MaxLookback < 1 {
+		return fmt.Errorf("max_lookback ต้องมากกว่า 0 (ได้ %d)", p.MaxLookback)
+	}
+	if p.MinConditions < 0 || p.MinConditions > 5 {
+		return fmt.Errorf("min_conditions ต้องอยู่ระหว่าง 0-5 (ได้ %d)", p.MinConditions)
+	}
+	return nil
+}
+
+// NewIndicatorsWithParams สร้าง Indicators ด้วยพารามิเตอร์ที่กำหนด
+func NewIndicatorsWithParams(params StrategyParams) *Indicators {
+	return &Indicators{
+		pivotPeriod: params.PivotPeriod,
+		atrPeriod:   params.ATRPeriod,
+		atrFactor:   params.ATRFactor,
+		emaPeriod:   params.EMAPeriod,
+	}
+}
+
+// SetStrategyParams ใช้พารามิเตอร์กับ indicators และการคำนวณ SL/TP ของ backtester
+func (bt *Backtester) SetStrategyParams(params StrategyParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	bt.params = params
+	bt.indicators = NewIndicatorsWithParams(params)
+	bt.stream = nil
+	return nil
+}
+
+// StrategyParams คืนพารามิเตอร์ที่ backtester ใช้อยู่
+func (bt *Backtester) StrategyParams() StrategyParams {
+	return bt.params
+}
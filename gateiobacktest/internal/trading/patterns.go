@@ -0,0 +1,348 @@
+package trading
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// CandlePattern ชื่อรูปแบบแท่งเทียน
+type CandlePattern string
+
+const (
+	PatternBullishEngulfing   CandlePattern = "bullish_engulfing"
+	PatternBearishEngulfing   CandlePattern = "bearish_engulfing"
+	PatternHammer             CandlePattern = "hammer"
+	PatternShootingStar       CandlePattern = "shooting_star"
+	PatternDoji               CandlePattern = "doji"
+	PatternBullishPinBar      CandlePattern = "bullish_pin_bar"
+	PatternBearishPinBar      CandlePattern = "bearish_pin_bar"
+	PatternInsideBar          CandlePattern = "inside_bar"
+	PatternOutsideBar         CandlePattern = "outside_bar"
+	PatternMorningStar        CandlePattern = "morning_star"
+	PatternEveningStar        CandlePattern = "evening_star"
+	PatternThreeWhiteSoldiers CandlePattern = "three_white_soldiers"
+	PatternThreeBlackCrows    CandlePattern = "three_black_crows"
+)
+
+const (
+	dojiBodyRatio      = 0.1 // body ไม่เกิน 10% ของช่วงแท่ง
+	hammerWickRatio    = 0.6 // ไส้หลักอย่างน้อย 60% ของช่วงแท่งและ 2 เท่าของ body
+	hammerOppositeWick = 0.15
+	pinBarWickRatio    = 2.0 / 3
+	pinBarBodyRatio    = 1.0 / 3
+	starBodyRatio      = 0.5 // แท่งกลางของ morning/evening star มี body ไม่เกินครึ่งของแท่งแรก
+	soldierBodyRatio   = 0.5 // three soldiers/crows: body อย่างน้อยครึ่งของช่วงแท่ง
+	patternTrendBars   = 3   // จำนวนแท่งที่ใช้ดูเทรนด์ก่อนหน้าของ hammer/shooting star
+	patternRangeBars   = 10  // จำนวนแท่งก่อนรูปแบบที่ใช้หาช่วงแท่งเฉลี่ย
+	patternShapeWeight = 0.7 // น้ำหนักของรูปทรงใน strength (ที่เหลือคือขนาดเทียบช่วงแท่งเฉลี่ย)
+)
+
+// PatternMatch รูปแบบแท่งเทียนที่ตรวจพบ
+// Index คือแท่งสุดท้ายของรูปแบบ (แท่งที่รูปแบบสมบูรณ์) จึงใช้ได้ทันทีตอนปิดแท่งนั้นโดยไม่มี lookahead
+// Strength 0-1 = 70% จากรูปทรง (เช่นสัดส่วนไส้หรือการกลืน body) + 30% จากขนาดแท่งเทียบ 2 เท่าของช่วงแท่งเฉลี่ย 10 แท่งก่อนหน้า
+type PatternMatch struct {
+	Pattern   CandlePattern `json:"pattern"`
+	Index     int           `json:"index"`
+	Direction int           `json:"direction"` // 1 = bullish, -1 = bearish, 0 = เป็นกลาง
+	Strength  float64       `json:"strength"`
+}
+
+// DirectionName ทิศของรูปแบบเป็นข้อความ
+func (m PatternMatch) DirectionName() string {
+	switch m.Direction {
+	case 1:
+		return "bullish"
+	case -1:
+		return "bearish"
+	}
+	return "neutral"
+}
+
+// CandlePatterns รายการรูปแบบที่ตรวจพบ เรียงตาม Index
+type CandlePatterns []PatternMatch
+
+// Has มีรูปแบบนี้อยู่ในรายการหรือไม่
+func (p CandlePatterns) Has(pattern CandlePattern) bool {
+	for _, m := range p {
+		if m.Pattern == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// Strongest รูปแบบที่แข็งแรงที่สุดในทิศที่กำหนด (0 = ทุกทิศ) ที่ strength อย่างน้อย minStrength
+func (p CandlePatterns) Strongest(direction int, minStrength float64) (PatternMatch, bool) {
+	best, found := PatternMatch{}, false
+	for _, m := range p {
+		if direction != 0 && m.Direction != direction {
+			continue
+		}
+		if m.Strength < minStrength || (found && m.Strength <= best.Strength) {
+			continue
+		}
+		best, found = m, true
+	}
+	return best, found
+}
+
+// DetectCandlePatterns หารูปแบบแท่งเทียนทุกแท่ง ผลที่แท่ง i ใช้เฉพาะข้อมูลถึงแท่ง i
+func DetectCandlePatterns(data []OHLCV) CandlePatterns {
+	var out CandlePatterns
+	for i := range data {
+		out = append(out, DetectCandlePatternsAt(data, i)...)
+	}
+	return out
+}
+
+// DetectCandlePatternsAt รูปแบบที่สมบูรณ์ที่แท่ง i (ใช้ data[:i+1] เท่านั้น)
+func DetectCandlePatternsAt(data []OHLCV, i int) CandlePatterns {
+	if i < 0 || i >= len(data) {
+		return nil
+	}
+	data = data[:i+1]
+
+	var out CandlePatterns
+	add := func(pattern CandlePattern, first, direction int, shape float64) {
+		out = append(out, PatternMatch{
+			Pattern:   pattern,
+			Index:     i,
+			Direction: direction,
+			Strength:  patternStrength(data, first, shape),
+		})
+	}
+
+	cur := candleShape(data[i])
+	if cur.rangeHL <= 0 {
+		return nil
+	}
+
+	// แท่งเดียว
+	if cur.body <= dojiBodyRatio*cur.rangeHL {
+		add(PatternDoji, i, 0, 1-cur.body/(dojiBodyRatio*cur.rangeHL))
+	}
+	trend := priorTrend(data, i)
+	if cur.lower >= hammerWickRatio*cur.rangeHL && cur.lower >= 2*cur.body &&
+		cur.upper <= hammerOppositeWick*cur.rangeHL && trend < 0 {
+		add(PatternHammer, i, 1, cur.lower/cur.rangeHL)
+	}
+	if cur.upper >= hammerWickRatio*cur.rangeHL && cur.upper >= 2*cur.body &&
+		cur.lower <= hammerOppositeWick*cur.rangeHL && trend > 0 {
+		add(PatternShootingStar, i, -1, cur.upper/cur.rangeHL)
+	}
+	if i < 1 {
+		return out
+	}
+
+	// สองแท่ง
+	prevCandle, curCandle := data[i-1], data[i]
+	prev := candleShape(prevCandle)
+	if cur.body <= pinBarBodyRatio*cur.rangeHL {
+		if cur.lower >= pinBarWickRatio*cur.rangeHL && curCandle.Low < prevCandle.Low {
+			add(PatternBullishPinBar, i, 1, cur.lower/cur.rangeHL)
+		}
+		if cur.upper >= pinBarWickRatio*cur.rangeHL && curCandle.High > prevCandle.High {
+			add(PatternBearishPinBar, i, -1, cur.upper/cur.rangeHL)
+		}
+	}
+	if prev.rangeHL > 0 {
+		if curCandle.High < prevCandle.High && curCandle.Low > prevCandle.Low {
+			add(PatternInsideBar, i-1, 0, 1-cur.rangeHL/prev.rangeHL)
+		}
+		if curCandle.High > prevCandle.High && curCandle.Low < prevCandle.Low {
+			add(PatternOutsideBar, i, candleDirection(curCandle), 1-prev.rangeHL/cur.rangeHL)
+		}
+	}
+	if prev.body > 0 && cur.body > prev.body {
+		if isRed(prevCandle) && isGreen(curCandle) && curCandle.Open <= prevCandle.Close && curCandle.Close >= prevCandle.Open {
+			add(PatternBullishEngulfing, i-1, 1, 1-prev.body/cur.body)
+		}
+		if isGreen(prevCandle) && isRed(curCandle) && curCandle.Open >= prevCandle.Close && curCandle.Close <= prevCandle.Open {
+			add(PatternBearishEngulfing, i-1, -1, 1-prev.body/cur.body)
+		}
+	}
+	if i < 2 {
+		return out
+	}
+
+	// สามแท่ง
+	first, star := data[i-2], data[i-1]
+	firstShape := candleShape(first)
+	if firstShape.body >= 0.5*firstShape.rangeHL && prev.body <= starBodyRatio*firstShape.body {
+		mid := (first.Open + first.Close) / 2
+		half := firstShape.body / 2
+		if isRed(first) && isGreen(curCandle) && math.Max(star.Open, star.Close) <= mid && curCandle.Close > mid {
+			add(PatternMorningStar, i-2, 1, (curCandle.Close-mid)/half)
+		}
+		if isGreen(first) && isRed(curCandle) && math.Min(star.Open, star.Close) >= mid && curCandle.Close < mid {
+			add(PatternEveningStar, i-2, -1, (mid-curCandle.Close)/half)
+		}
+	}
+	if direction := threeCandleRun(data[i-2 : i+1]); direction != 0 {
+		shape := 0.0
+		for _, c := range data[i-2 : i+1] {
+			s := candleShape(c)
+			shape += s.body / s.rangeHL / 3
+		}
+		pattern := PatternThreeWhiteSoldiers
+		if direction < 0 {
+			pattern = PatternThreeBlackCrows
+		}
+		add(pattern, i-2, direction, shape)
+	}
+
+	return out
+}
+
+// candleParts ส่วนประกอบของแท่งเทียน
+type candleParts struct {
+	body, upper, lower, rangeHL float64
+}
+
+func candleShape(c OHLCV) candleParts {
+	return candleParts{
+		body:    math.Abs(c.Close - c.Open),
+		upper:   c.High - math.Max(c.Open, c.Close),
+		lower:   math.Min(c.Open, c.Close) - c.Low,
+		rangeHL: c.High - c.Low,
+	}
+}
+
+// candleDirection 1 = แท่งเขียว, -1 = แท่งแดง, 0 = ปิดเท่าเปิด
+func candleDirection(c OHLCV) int {
+	switch {
+	case isGreen(c):
+		return 1
+	case isRed(c):
+		return -1
+	}
+	return 0
+}
+
+// priorTrend ทิศของราคาปิดใน patternTrendBars แท่งก่อนแท่ง i (0 = ข้อมูลไม่พอหรือไม่เปลี่ยน)
+func priorTrend(data []OHLCV, i int) int {
+	if i-1-patternTrendBars < 0 {
+		return 0
+	}
+	change := data[i-1].Close - data[i-1-patternTrendBars].Close
+	switch {
+	case change > 0:
+		return 1
+	case change < 0:
+		return -1
+	}
+	return 0
+}
+
+// threeCandleRun 1 = three white soldiers, -1 = three black crows, 0 = ไม่ใช่
+// ทั้งสามแท่งสีเดียวกัน body ใหญ่ ปิดต่อเนื่อง และแต่ละแท่งเปิดภายใน body ของแท่งก่อนหน้า
+func threeCandleRun(candles []OHLCV) int {
+	direction := candleDirection(candles[0])
+	if direction == 0 {
+		return 0
+	}
+	for k, c := range candles {
+		s := candleShape(c)
+		if candleDirection(c) != direction || s.body < soldierBodyRatio*s.rangeHL {
+			return 0
+		}
+		if k == 0 {
+			continue
+		}
+		prev := candles[k-1]
+		low, high := math.Min(prev.Open, prev.Close), math.Max(prev.Open, prev.Close)
+		if c.Open < low || c.Open > high || float64(direction)*(c.Close-prev.Close) <= 0 {
+			return 0
+		}
+	}
+	return direction
+}
+
+// patternStrength รวมคะแนนรูปทรงกับขนาดของรูปแบบ (แท่ง first ถึงแท่งสุดท้าย) เทียบช่วงแท่งเฉลี่ยก่อนหน้า
+func patternStrength(data []OHLCV, first int, shape float64) float64 {
+	high, low := data[first].High, data[first].Low
+	for _, c := range data[first:] {
+		high, low = math.Max(high, c.High), math.Min(low, c.Low)
+	}
+	size := 0.5
+	start := first - patternRangeBars
+	if start < 0 {
+		start = 0
+	}
+	if first > start {
+		avg := 0.0
+		for _, c := range data[start:first] {
+			avg += c.High - c.Low
+		}
+		avg /= float64(first - start)
+		if avg > 0 {
+			size = clamp01((high - low) / (2 * avg))
+		}
+	}
+	strength := patternShapeWeight*clamp01(shape) + (1-patternShapeWeight)*size
+	return math.Round(strength*100) / 100
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// candlePatterns รูปแบบที่สมบูรณ์ที่แท่งปัจจุบันของ backtest
+func (bt *Backtester) candlePatterns() CandlePatterns {
+	return DetectCandlePatternsAt(bt.ohlcvData, bt.currentIndex)
+}
+
+// candlePatternMinStrength strength ขั้นต่ำของรูปแบบที่กลยุทธ์ candle-patterns ใช้เข้าเทรด
+const candlePatternMinStrength = 0.6
+
+// CandlePatternSignal เข้าตามรูปแบบกลับตัวที่แข็งแรงที่สุดของแท่งปัจจุบัน (strength ≥ 0.6)
+// ถ้ามีทั้งสองทิศในแท่งเดียวกัน (เช่น outside bar กับ pin bar) ใช้ทิศที่แข็งแรงกว่า
+func CandlePatternSignal(bt *Backtester) (string, string) {
+	if bt.currentIndex < patternTrendBars+1 {
+		return "", ""
+	}
+	patterns := bt.candlePatterns()
+	bull, hasBull := patterns.Strongest(1, candlePatternMinStrength)
+	bear, hasBear := patterns.Strongest(-1, candlePatternMinStrength)
+	switch {
+	case hasBull && (!hasBear || bull.Strength > bear.Strength):
+		return "LONG", fmt.Sprintf("Candle Pattern %s (%.2f)", bull.Pattern, bull.Strength)
+	case hasBear && (!hasBull || bear.Strength > bull.Strength):
+		return "SHORT", fmt.Sprintf("Candle Pattern %s (%.2f)", bear.Pattern, bear.Strength)
+	}
+	return "", ""
+}
+
+// promptPatternFact ข้อเท็จจริงของรูปแบบแท่งเทียนสำหรับใส่ใน prompt ของ AI
+type promptPatternFact struct {
+	Pattern   CandlePattern `json:"pattern"`
+	BarsAgo   int           `json:"bars_ago"` // 0 = แท่งล่าสุด
+	Direction string        `json:"direction"`
+	Strength  float64       `json:"strength"`
+}
+
+// formatPatternFacts รูปแบบแท่งเทียนใน lookback แท่งล่าสุดเป็น JSON บรรทัดละรูปแบบ (ใหม่สุดก่อน)
+func formatPatternFacts(ohlcv []OHLCV, lookback int) string {
+	last := len(ohlcv) - 1
+	var lines []string
+	for i := last; i >= 0 && i > last-lookback; i-- {
+		for _, m := range DetectCandlePatternsAt(ohlcv, i) {
+			fact, err := json.Marshal(promptPatternFact{
+				Pattern:   m.Pattern,
+				BarsAgo:   last - m.Index,
+				Direction: m.DirectionName(),
+				Strength:  m.Strength,
+			})
+			if err != nil {
+				continue
+			}
+			lines = append(lines, string(fact))
+		}
+	}
+	if len(lines) == 0 {
+		return "ไม่พบรูปแบบแท่งเทียน\n"
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
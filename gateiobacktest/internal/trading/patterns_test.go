@@ -0,0 +1,102 @@
+package trading
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func candle(open, high, low, close float64) OHLCV {
+	return OHLCV{Open: open, High: high, Low: low, Close: close, Volume: 1000}
+}
+
+// ขาลง 4 แท่งก่อน hammer และขาขึ้น 4 แท่งก่อน shooting star
+var (
+	fallingCandles = []OHLCV{
+		candle(13.5, 13.6, 12.9, 13), candle(13, 13.1, 11.9, 12), candle(12, 12.1, 10.9, 11), candle(11, 11.1, 9.9, 10),
+	}
+	risingCandles = []OHLCV{
+		candle(9.5, 10.1, 9.4, 10), candle(10, 11.1, 9.9, 11), candle(11, 12.1, 10.9, 12), candle(12, 13.1, 11.9, 13),
+	}
+)
+
+var candlePatternCases = []struct {
+	pattern   CandlePattern
+	direction int
+	candles   []OHLCV
+}{
+	{PatternBullishEngulfing, 1, []OHLCV{candle(10, 10.2, 8.9, 9), candle(8.8, 10.6, 8.7, 10.5)}},
+	{PatternBearishEngulfing, -1, []OHLCV{candle(9, 10.1, 8.9, 10), candle(10.2, 10.3, 8.5, 8.6)}},
+	{PatternHammer, 1, append(append([]OHLCV{}, fallingCandles...), candle(9.9, 10.05, 8, 10))},
+	{PatternShootingStar, -1, append(append([]OHLCV{}, risingCandles...), candle(13.1, 15, 12.95, 13))},
+	{PatternDoji, 0, []OHLCV{candle(10, 11, 9, 10.05)}},
+	{PatternBullishPinBar, 1, []OHLCV{candle(10, 10.5, 9.5, 9.8), candle(9.8, 10, 8, 9.9)}},
+	{PatternBearishPinBar, -1, []OHLCV{candle(10, 10.5, 9.5, 10.2), candle(10.2, 12, 10, 10.1)}},
+	{PatternInsideBar, 0, []OHLCV{candle(10, 12, 8, 11), candle(10.5, 11, 9, 10.8)}},
+	{PatternOutsideBar, 1, []OHLCV{candle(10, 11, 9, 10.5), candle(10.4, 11.5, 8.5, 11.2)}},
+	{PatternMorningStar, 1, []OHLCV{candle(12, 12.1, 9.9, 10), candle(10.5, 10.8, 9.5, 10.6), candle(10.6, 11.8, 10.5, 11.7)}},
+	{PatternEveningStar, -1, []OHLCV{candle(10, 12.1, 9.9, 12), candle(11.5, 12.5, 11.2, 11.4), candle(11.4, 11.5, 10.1, 10.2)}},
+	{PatternThreeWhiteSoldiers, 1, []OHLCV{candle(10, 11.05, 9.95, 11), candle(10.5, 12.05, 10.45, 12), candle(11.5, 13.05, 11.45, 13)}},
+	{PatternThreeBlackCrows, -1, []OHLCV{candle(13, 13.05, 11.95, 12), candle(12.5, 12.55, 10.95, 11), candle(11.5, 11.55, 9.95, 10)}},
+}
+
+func TestDetectCandlePatterns(t *testing.T) {
+	for _, tc := range candlePatternCases {
+		t.Run(string(tc.pattern), func(t *testing.T) {
+			last := len(tc.candles) - 1
+			var match *PatternMatch
+			for _, m := range DetectCandlePatternsAt(tc.candles, last) {
+				if m.Pattern == tc.pattern {
+					match = &m
+				}
+			}
+			if match == nil {
+				t.Fatalf("ไม่พบ %s ที่แท่ง %d: %+v", tc.pattern, last, DetectCandlePatternsAt(tc.candles, last))
+			}
+			if match.Index != last || match.Direction != tc.direction {
+				t.Fatalf("%s: index %d direction %d, ต้องการ index %d direction %d",
+					tc.pattern, match.Index, match.Direction, last, tc.direction)
+			}
+			if match.Strength <= 0 || match.Strength > 1 {
+				t.Fatalf("%s: strength %v อยู่นอกช่วง (0, 1]", tc.pattern, match.Strength)
+			}
+		})
+	}
+}
+
+func TestHammerNeedsPriorDowntrend(t *testing.T) {
+	data := append(append([]OHLCV{}, risingCandles...), candle(12.9, 13.05, 11, 13))
+	if DetectCandlePatternsAt(data, len(data)-1).Has(PatternHammer) {
+		t.Fatal("hammer หลังขาขึ้นไม่ควรถูกนับ")
+	}
+}
+
+func TestDetectCandlePatternsNoLookahead(t *testing.T) {
+	data := syntheticCandles(400, 25)
+	all := DetectCandlePatterns(data)
+	if len(all) == 0 {
+		t.Fatal("ไม่พบรูปแบบใดในข้อมูลสังเคราะห์")
+	}
+	for _, cut := range []int{50, 123, 399} {
+		var want CandlePatterns
+		for _, m := range all {
+			if m.Index <= cut {
+				want = append(want, m)
+			}
+		}
+		if got := DetectCandlePatterns(data[:cut+1]); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ผลถึงแท่ง %d ขึ้นกับแท่งหลังจากนั้น", cut)
+		}
+	}
+}
+
+func TestFormatPatternFacts(t *testing.T) {
+	data := []OHLCV{candle(10, 10.2, 8.9, 9), candle(8.8, 10.6, 8.7, 10.5), candle(10.5, 10.7, 10.3, 10.6)}
+	facts := formatPatternFacts(data, 10)
+	if !strings.Contains(facts, `{"pattern":"bullish_engulfing","bars_ago":1,"direction":"bullish","strength":`) {
+		t.Fatalf("ไม่มี bullish engulfing ใน facts:\n%s", facts)
+	}
+	if facts := formatPatternFacts(data[:1], 10); facts != "ไม่พบรูปแบบแท่งเทียน\n" {
+		t.Fatalf("facts ของแท่งธรรมดา: %q", facts)
+	}
+}
@@ -0,0 +1,162 @@
+package trading
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// modelFixtureDir fixture ที่สร้างเองด้วยโมเดล Python ใน gen_fixtures.py ซึ่งอ่านจาก pivot_supertrend.pine ทีละแท่ง
+// test ในไฟล์นี้ยืนยันเพียงว่าโค้ด Go ตรงกับโมเดลนั้น ไม่ใช่ค่าที่ export จาก TradingView
+// (คอลัมน์ตรงกับ Export chart data จึงเพิ่มไฟล์ที่ export จากกราฟจริงเข้า modelFixtures ได้)
+const modelFixtureDir = "testdata/pivot_supertrend"
+
+// modelTolerance ความคลาดเคลื่อนสัมพัทธ์ที่ยอมรับได้
+const modelTolerance = 1e-9
+
+// modelColumns คอลัมน์ผลลัพธ์ของโมเดลที่ตรวจ ตามลำดับในรายงาน
+var modelColumns = []string{"center", "up", "dn", "trend", "supertrend"}
+
+var modelFixtures = []struct {
+	file   string
+	params StrategyParams
+}{
+	{"prd2_factor3_atr10.csv", StrategyParams{PivotPeriod: 2, ATRFactor: 3, ATRPeriod: 10, EMAPeriod: 100}},
+	{"prd3_factor2.5_atr14.csv", StrategyParams{PivotPeriod: 3, ATRFactor: 2.5, ATRPeriod: 14, EMAPeriod: 100}},
+	{"prd1_factor2_atr5.csv", StrategyParams{PivotPeriod: 1, ATRFactor: 2, ATRPeriod: 5, EMAPeriod: 100}},
+}
+
+// modelFixture แท่งเทียนและค่าที่โมเดลใน gen_fixtures.py คำนวณ (NaN = na)
+type modelFixture struct {
+	data     []OHLCV
+	expected map[string][]float64
+}
+
+func loadModelFixture(t *testing.T, file string) modelFixture {
+	t.Helper()
+	f, err := os.Open(filepath.Join(modelFixtureDir, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, rows := records[0], records[1:]
+
+	columns := make(map[string][]float64)
+	for col, name := range header {
+		values := make([]float64, len(rows))
+		for i, row := range rows {
+			values[i] = math.NaN()
+			if row[col] == "" {
+				continue
+			}
+			if values[i], err = strconv.ParseFloat(row[col], 64); err != nil {
+				t.Fatalf("%s: %s แถว %d: %v", file, name, i+2, err)
+			}
+		}
+		columns[name] = values
+	}
+
+	fixture := modelFixture{data: make([]OHLCV, len(rows)), expected: make(map[string][]float64)}
+	for i := range rows {
+		fixture.data[i] = OHLCV{
+			Timestamp: int64(columns["time"][i]),
+			Open:      columns["open"][i],
+			High:      columns["high"][i],
+			Low:       columns["low"][i],
+			Close:     columns["close"][i],
+			Volume:    columns["Volume"][i],
+		}
+	}
+	for _, name := range modelColumns {
+		values, ok := columns[name]
+		if !ok {
+			t.Fatalf("%s: ไม่มีคอลัมน์ %s", file, name)
+		}
+		fixture.expected[name] = values
+	}
+	return fixture
+}
+
+// modelDivergence แท่งแรกที่ผลต่างจากโมเดลใน gen_fixtures.py พร้อมค่าทุกคอลัมน์ของแท่งนั้น
+type modelDivergence struct {
+	bar       int
+	timestamp int64
+	columns   []string // คอลัมน์ที่ไม่ตรง
+	got, want map[string]float64
+}
+
+func (d *modelDivergence) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ต่างจากโมเดลใน gen_fixtures.py ตั้งแต่แท่ง %d (time %d) ที่คอลัมน์ %s\n",
+		d.bar, d.timestamp, strings.Join(d.columns, ", "))
+	for _, name := range modelColumns {
+		mark := " "
+		for _, c := range d.columns {
+			if c == name {
+				mark = "✗"
+			}
+		}
+		fmt.Fprintf(&b, "  %s %-10s ได้ %-22v ต้องการ %v\n", mark, name, d.got[name], d.want[name])
+	}
+	return b.String()
+}
+
+// compareWithModel เทียบผลทุกแท่งกับ fixture (na ของ Pine เท่ากับ 0) คืน nil เมื่อตรงทั้งหมด
+func compareWithModel(fixture modelFixture, series pivotSuperTrendSeries) *modelDivergence {
+	got := map[string][]float64{
+		"center":     series.centerLine,
+		"up":         series.upperBand,
+		"dn":         series.lowerBand,
+		"supertrend": series.superTrend,
+		"trend":      make([]float64, len(series.trend)),
+	}
+	for i, trend := range series.trend {
+		got["trend"][i] = float64(trend)
+	}
+
+	for i := range fixture.data {
+		var columns []string
+		for _, name := range modelColumns {
+			want := fixture.expected[name][i]
+			if math.IsNaN(want) {
+				want = 0
+			}
+			if math.Abs(got[name][i]-want) > modelTolerance*math.Max(1, math.Abs(want)) {
+				columns = append(columns, name)
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		d := &modelDivergence{bar: i, timestamp: fixture.data[i].Timestamp, columns: columns,
+			got: make(map[string]float64), want: make(map[string]float64)}
+		for _, name := range modelColumns {
+			d.got[name], d.want[name] = got[name][i], fixture.expected[name][i]
+		}
+		return d
+	}
+	return nil
+}
+
+func TestPivotSuperTrendMatchesFixtureModel(t *testing.T) {
+	for _, fx := range modelFixtures {
+		t.Run(fx.file, func(t *testing.T) {
+			fixture := loadModelFixture(t, fx.file)
+			series := NewIndicatorsWithParams(fx.params).pivotSuperTrend(fixture.data)
+			if d := compareWithModel(fixture, series); d != nil {
+				t.Fatal(d)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+package trading
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// PortfolioConfig การตั้งค่า portfolio backtest
+type PortfolioConfig struct {
+	InitialCapital    float64 `json:"initial_capital"`
+	MaxPositions      int     `json:"max_positions"`       // จำนวน position ที่เปิดพร้อมกันได้สูงสุด (0 = ไม่จำกัด)
+	MaxSymbolExposure float64 `json:"max_symbol_exposure"` // notional สูงสุดต่อเหรียญเทียบกับ equity (0.5 = 50%, 0 = ไม่จำกัด)
+}
+
+// SymbolStats สรุปผลรายเหรียญภายใน portfolio
+type SymbolStats struct {
+	Symbol        string  `json:"symbol"`
+	TotalTrades   int     `json:"total_trades"`
+	WinningTrades int     `json:"winning_trades"`
+	WinRate       float64 `json:"win_rate"`
+	NetPnL        float64 `json:"net_pnl"`
+}
+
+// PortfolioResult ผลการ backtest ทั้งพอร์ต
+type PortfolioResult struct {
+	Symbols        []string                      `json:"symbols"`
+	StartDate      time.Time                     `json:"start_date"`
+	EndDate        time.Time                     `json:"end_date"`
+	InitialCapital float64                       `json:"initial_capital"`
+	FinalCapital   float64                       `json:"final_capital"`
+	TotalReturn    float64                       `json:"total_return"`
+	TotalReturnPct float64                       `json:"total_return_pct"`
+	TotalTrades    int                           `json:"total_trades"`
+	WinningTrades  int                           `json:"winning_trades"`
+	LosingTrades   int                           `json:"losing_trades"`
+	WinRate        float64                       `json:"win_rate"`
+	MaxDrawdown    float64                       `json:"max_drawdown"`
+	MaxDrawdownPct float64                       `json:"max_drawdown_pct"`
+	MaxConcurrent  int                           `json:"max_concurrent"`
+	SkippedEntries int                           `json:"skipped_entries"` // สัญญาณที่ถูกตัดเพราะข้อจำกัดของพอร์ต
+	Metrics        PerformanceMetrics            `json:"metrics"`
+	SymbolStats    []SymbolStats                 `json:"symbol_stats"`
+	Correlation    map[string]map[string]float64 `json:"correlation"` // correlation ของผลตอบแทนรายวันระหว่างเหรียญ
+	EquityCurve    []EquityPoint                 `json:"equity_curve"`
+	Trades         []BacktestTrade               `json:"trades"`
+	Orders         []Order                       `json:"orders,omitempty"` // order ของทุกเหรียญรวมที่ถูกยกเลิกตอนจบ
+	DailyReturns   []DailyReturn                 `json:"daily_returns"`
+}
+
+// portfolioSlot เหรียญหนึ่งใน portfolio พร้อม backtester และกลยุทธ์ของตัวเอง
+type portfolioSlot struct {
+	bt       *Backtester
+	strategy Strategy
+	next     int // index แท่งถัดไปที่ยังไม่ได้ประมวลผล
+}
+
+// PortfolioBacktester backtest หลายเหรียญบนนาฬิกาเดียวกันด้วยบัญชีเดียว
+// (แบบเดียวกับที่ TradingBot.scanForNewOpportunities เทรดจริง)
+type PortfolioBacktester struct {
+	config PortfolioConfig
+	slots  []*portfolioSlot
+
+	cash           float64 // เงินทุนที่ realize แล้ว (ใช้ร่วมกันทุกเหรียญ)
+	skippedEntries int
+	log            io.Writer // ปลายทาง log ของพอร์ตและทุกเหรียญ (nil = os.Stdout)
+
+	// ผลตอบแทนรายวันของแต่ละเหรียญ (สำหรับ correlation) และ PnL สะสมของวันก่อนหน้า
+	dailyReturns map[string][]float64
+	lastPnL      map[string]float64
+}
+
+// NewPortfolioBacktester สร้าง portfolio backtester ใหม่
+func NewPortfolioBacktester(config PortfolioConfig) *PortfolioBacktester {
+	return &PortfolioBacktester{
+		config:       config,
+		cash:         config.InitialCapital,
+		dailyReturns: make(map[string][]float64),
+		lastPnL:      make(map[string]float64),
+	}
+}
+
+// SetLogOutput กำหนดปลายทาง log ของพอร์ตและทุกเหรียญ (nil = os.Stdout)
+func (p *PortfolioBacktester) SetLogOutput(w io.Writer) {
+	p.log = w
+	for _, slot := range p.slots {
+		slot.bt.SetLogOutput(w)
+	}
+}
+
+// AddSymbol เพิ่มเหรียญพร้อมข้อมูลราคาและกลยุทธ์ (แต่ละเหรียญต้องใช้ instance ของกลยุทธ์แยกกัน)
+func (p *PortfolioBacktester) AddSymbol(symbol string, data []OHLCV, strategy Strategy) (*Backtester, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาสำหรับ %s", symbol)
+	}
+
+	sorted := make([]OHLCV, len(data))
+	copy(sorted, data)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	bt, err := NewBacktesterSimple(symbol, 0, p.config.InitialCapital)
+	if err != nil {
+		return nil, err
+	}
+	bt.SetLogOutput(p.log)
+	bt.LoadOHLCVData(sorted,
+		time.Unix(sorted[0].Timestamp, 0),
+		time.Unix(sorted[len(sorted)-1].Timestamp, 0))
+	bt.entryLimit = p.limitEntry
+
+	p.slots = append(p.slots, &portfolioSlot{bt: bt, strategy: strategy})
+	return bt, nil
+}
+
+// Run รันทุกเหรียญตามเวลาที่เรียงรวมกัน
+func (p *PortfolioBacktester) Run() (*PortfolioResult, error) {
+	if len(p.slots) == 0 {
+		return nil, fmt.Errorf("ไม่มีเหรียญใน portfolio")
+	}
+
+	writeLog(p.log, "🚀 เริ่มต้น Portfolio Backtest %d เหรียญ\n", len(p.slots))
+	writeLog(p.log, "💰 เงินทุนเริ่มต้น: $%.2f | Max Positions: %d | Max Exposure/เหรียญ: %.0f%%\n",
+		p.config.InitialCapital, p.config.MaxPositions, p.config.MaxSymbolExposure*100)
+
+	for _, slot := range p.slots {
+		slot.bt.fees = slot.strategy.Fees(slot.bt)
+		slot.bt.haltErr = nil
+	}
+
+	result := &PortfolioResult{
+		InitialCapital: p.config.InitialCapital,
+		Correlation:    make(map[string]map[string]float64),
+	}
+
+	peak := p.config.InitialCapital
+	var lastDay string
+
+	for _, ts := range p.clock() {
+		for _, slot := range p.slots {
+			if slot.next >= len(slot.bt.ohlcvData) || slot.bt.ohlcvData[slot.next].Timestamp != ts {
+				continue
+			}
+
+			slot.bt.currentIndex = slot.next
+			slot.next++
+			if slot.bt.currentIndex < slot.strategy.WarmupBars() {
+				continue
+			}
+
+			p.step(slot, func() { slot.bt.stepBar(slot.strategy) })
+			if slot.bt.haltErr != nil {
+				return nil, fmt.Errorf("%s: %w", slot.bt.symbol, slot.bt.haltErr)
+			}
+		}
+
+		now := time.Unix(ts, 0).UTC()
+		equity := p.equity()
+		open := p.openPositions()
+		result.EquityCurve = append(result.EquityCurve, EquityPoint{Time: now, Equity: equity, OpenPositions: open})
+
+		if open > result.MaxConcurrent {
+			result.MaxConcurrent = open
+		}
+		if equity > peak {
+			peak = equity
+		}
+		if drawdown := peak - equity; drawdown > result.MaxDrawdown {
+			result.MaxDrawdown = drawdown
+			result.MaxDrawdownPct = drawdown / peak * 100
+		}
+
+		// ปิดวันตามปฏิทิน (UTC) เพื่อเก็บผลตอบแทนรายวันของพอร์ตและรายเหรียญ
+		if day := now.Format("2006-01-02"); day != lastDay {
+			p.recordDay(result, now, equity)
+			lastDay = day
+		}
+	}
+
+	// ปิด position และยกเลิก order ที่เหลือตอนจบ backtest
+	for _, slot := range p.slots {
+		if slot.bt.position != nil {
+			p.step(slot, func() { slot.bt.closePosition(endOfDataReason(slot.strategy)) })
+		}
+		slot.bt.cancelOpenOrders("END_OF_BACKTEST")
+	}
+
+	p.finish(result)
+	return result, nil
+}
+
+// step ประมวลผลเหรียญหนึ่งโดยใช้เงินทุนร่วมของพอร์ต
+func (p *PortfolioBacktester) step(slot *portfolioSlot, fn func()) {
+	slot.bt.currentCapital = p.cash
+	fn()
+	p.cash = slot.bt.currentCapital
+}
+
+// clock รวม timestamp ของทุกเหรียญเป็นนาฬิกาเดียวเรียงตามเวลา
+func (p *PortfolioBacktester) clock() []int64 {
+	seen := make(map[int64]bool)
+	var timestamps []int64
+	for _, slot := range p.slots {
+		for _, candle := range slot.bt.ohlcvData {
+			if !seen[candle.Timestamp] {
+				seen[candle.Timestamp] = true
+				timestamps = append(timestamps, candle.Timestamp)
+			}
+		}
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	return timestamps
+}
+
+// equity เงินทุนที่ realize แล้ว + กำไร/ขาดทุนที่ยังไม่ปิดของทุกเหรียญ
+func (p *PortfolioBacktester) equity() float64 {
+	equity := p.cash
+	for _, slot := range p.slots {
+		equity += slot.bt.UnrealizedPnL()
+	}
+	return equity
+}
+
+// openPositions จำนวน position ที่เปิดอยู่ทั้งพอร์ต
+func (p *PortfolioBacktester) openPositions() int {
+	count := 0
+	for _, slot := range p.slots {
+		if slot.bt.position != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// limitEntry บังคับข้อจำกัดของพอร์ตก่อนเปิด position (0 = ห้ามเปิด)
+// นอกจากจำนวน position และ exposure ต่อเหรียญ เงินทุนที่ทุก position ใช้รวมกันต้องไม่เกิน equity ของพอร์ต
+func (p *PortfolioBacktester) limitEntry(bt *Backtester, signal *EntrySignal, quantity, price float64) float64 {
+	// การเพิ่มไม้ (pyramiding) ไม่เพิ่มจำนวน position และไม่นับเป็นสัญญาณที่ถูกข้าม แต่ exposure ของเหรียญนับรวม position เดิม
+	adding := bt.position != nil
+	held := 0.0
+	if adding {
+		held = bt.position.EntryPrice * bt.position.Quantity
+	}
+	skip := func() {
+		if !adding {
+			p.skippedEntries++
+		}
+	}
+	if !adding && p.config.MaxPositions > 0 && p.openPositions() >= p.config.MaxPositions {
+		skip()
+		bt.logf("⛔ %s: ครบ %d positions แล้ว ข้ามสัญญาณ %s\n", bt.symbol, p.config.MaxPositions, signal.Side)
+		return 0
+	}
+
+	// ใช้เงินทุนล่าสุดของเหรียญที่กำลังประมวลผล (p.cash ยังไม่ถูก sync ระหว่าง step)
+	equity, used := bt.currentCapital, 0.0
+	for _, slot := range p.slots {
+		equity += slot.bt.UnrealizedPnL()
+		if slot.bt.position != nil {
+			used += positionCapital(slot.bt.position)
+		}
+	}
+
+	if p.config.MaxSymbolExposure > 0 {
+		maxNotional := equity*p.config.MaxSymbolExposure - held
+		if maxNotional <= 0 {
+			skip()
+			bt.logf("⛔ %s: exposure ครบ %.0f%% ของพอร์ตแล้ว ข้ามสัญญาณ %s\n", bt.symbol, p.config.MaxSymbolExposure*100, signal.Side)
+			return 0
+		}
+		if quantity*price > maxNotional {
+			quantity = maxNotional / price
+			bt.logf("⚠️ %s: จำกัด exposure ไม่เกิน %.0f%% ของพอร์ต ($%.2f)\n",
+				bt.symbol, p.config.MaxSymbolExposure*100, maxNotional)
+		}
+	}
+
+	// เงินทุนที่เหลือจาก position อื่น: margin เมื่อจำลอง futures, notional เต็มเมื่อไม่มี leverage
+	leverage := 1.0
+	if bt.futures != nil {
+		leverage = signal.Leverage
+		if leverage <= 0 {
+			leverage = bt.futures.Leverage
+		}
+	}
+	maxNotional := (equity - used) * leverage
+	if maxNotional <= 0 {
+		skip()
+		bt.logf("⛔ %s: เงินทุนของพอร์ตถูกใช้กับ position อื่นหมดแล้ว ข้ามสัญญาณ %s\n", bt.symbol, signal.Side)
+		return 0
+	}
+	if quantity*price > maxNotional {
+		quantity = maxNotional / price
+		bt.logf("⚠️ %s: จำกัดขนาดตามเงินทุนที่เหลือของพอร์ต ($%.2f จาก equity $%.2f)\n",
+			bt.symbol, equity-used, equity)
+	}
+
+	return quantity
+}
+
+// positionCapital เงินทุนที่ position ใช้: margin เมื่อจำลอง futures มิฉะนั้น notional ตอนเข้า
+func positionCapital(pos *BacktestPosition) float64 {
+	if pos.Margin > 0 {
+		return pos.Margin
+	}
+	return pos.EntryPrice * pos.Quantity
+}
+
+// recordDay บันทึกผลตอบแทนรายวันของพอร์ตและของแต่ละเหรียญ
+// ผลตอบแทนของเหรียญ = PnL ของวันหารด้วย equity ของเหรียญเมื่อวันก่อน (เงินทุนเริ่มต้น + PnL สะสมของเหรียญ)
+func (p *PortfolioBacktester) recordDay(result *PortfolioResult, now time.Time, equity float64) {
+	result.DailyReturns = appendDailyReturn(result.DailyReturns, now, equity, p.config.InitialCapital)
+
+	for _, slot := range p.slots {
+		symbol := slot.bt.symbol
+		pnl := slot.bt.UnrealizedPnL()
+		for _, trade := range slot.bt.trades {
+			pnl += trade.NetPnL
+		}
+
+		dailyReturn := 0.0
+		if prev := p.config.InitialCapital + p.lastPnL[symbol]; prev > 0 {
+			dailyReturn = (pnl - p.lastPnL[symbol]) / prev
+		}
+		p.dailyReturns[symbol] = append(p.dailyReturns[symbol], dailyReturn)
+		p.lastPnL[symbol] = pnl
+	}
+}
+
+// finish สรุปผลรวมของพอร์ต สถิติรายเหรียญ และ correlation
+func (p *PortfolioBacktester) finish(result *PortfolioResult) {
+	for _, slot := range p.slots {
+		bt := slot.bt
+		positions := PositionTrades(bt.trades)
+		stats := SymbolStats{Symbol: bt.symbol, TotalTrades: len(positions)}
+		for _, trade := range positions {
+			stats.NetPnL += trade.NetPnL
+			if trade.NetPnL > 0 {
+				stats.WinningTrades++
+			}
+		}
+		if stats.TotalTrades > 0 {
+			stats.WinRate = float64(stats.WinningTrades) / float64(stats.TotalTrades) * 100
+		}
+
+		result.Symbols = append(result.Symbols, bt.symbol)
+		result.SymbolStats = append(result.SymbolStats, stats)
+		result.Trades = append(result.Trades, bt.trades...)
+		result.Orders = append(result.Orders, bt.orderHistory()...)
+		result.WinningTrades += stats.WinningTrades
+	}
+
+	sort.Slice(result.Trades, func(i, j int) bool {
+		return result.Trades[i].ExitTime.Before(result.Trades[j].ExitTime)
+	})
+
+	positions := PositionTrades(result.Trades)
+	result.TotalTrades = len(positions)
+	result.LosingTrades = result.TotalTrades - result.WinningTrades
+	if result.TotalTrades > 0 {
+		result.WinRate = float64(result.WinningTrades) / float64(result.TotalTrades) * 100
+	}
+
+	result.FinalCapital = p.cash
+	result.TotalReturn = p.cash - p.config.InitialCapital
+	result.TotalReturnPct = result.TotalReturn / p.config.InitialCapital * 100
+	result.SkippedEntries = p.skippedEntries
+
+	inMarket := 0
+	for _, point := range result.EquityCurve {
+		if point.OpenPositions > 0 {
+			inMarket++
+		}
+	}
+	exposure := 0.0
+	if len(result.EquityCurve) > 0 {
+		exposure = float64(inMarket) / float64(len(result.EquityCurve)) * 100
+	}
+	result.Metrics = CalculateMetrics(positions, result.DailyReturns, result.MaxDrawdownPct, exposure)
+	if len(result.EquityCurve) > 0 {
+		result.StartDate = result.EquityCurve[0].Time
+		result.EndDate = result.EquityCurve[len(result.EquityCurve)-1].Time
+	}
+
+	for _, a := range result.Symbols {
+		result.Correlation[a] = make(map[string]float64)
+		for _, b := range result.Symbols {
+			result.Correlation[a][b] = correlation(p.dailyReturns[a], p.dailyReturns[b])
+		}
+	}
+}
+
+// PrintSummary แสดงสรุปผล portfolio backtest
+func (r *PortfolioResult) PrintSummary() {
+	fmt.Printf("\n📊 ===== ผลการ Portfolio Backtest =====\n")
+	fmt.Printf("📅 ช่วงเวลา: %s ถึง %s\n", r.StartDate.Format("2006-01-02"), r.EndDate.Format("2006-01-02"))
+	fmt.Printf("💰 เงินทุน: $%.2f → $%.2f (%.2f%%)\n", r.InitialCapital, r.FinalCapital, r.TotalReturnPct)
+	fmt.Printf("📉 Max Drawdown: $%.2f (%.2f%%)\n", r.MaxDrawdown, r.MaxDrawdownPct)
+	fmt.Printf("🔢 เทรดทั้งหมด: %d | Win Rate: %.2f%% | เปิดพร้อมกันสูงสุด: %d | สัญญาณที่ถูกข้าม: %d\n",
+		r.TotalTrades, r.WinRate, r.MaxConcurrent, r.SkippedEntries)
+	r.Metrics.Print()
+
+	fmt.Printf("\n%-12s %8s %10s %12s\n", "Symbol", "Trades", "WinRate", "NetPnL")
+	for _, stats := range r.SymbolStats {
+		fmt.Printf("%-12s %8d %9.2f%% %12.2f\n", stats.Symbol, stats.TotalTrades, stats.WinRate, stats.NetPnL)
+	}
+
+	fmt.Printf("\n🔗 Correlation ของผลตอบแทนรายวัน\n%-12s", "")
+	for _, symbol := range r.Symbols {
+		fmt.Printf(" %10s", symbol)
+	}
+	fmt.Println()
+	for _, a := range r.Symbols {
+		fmt.Printf("%-12s", a)
+		for _, b := range r.Symbols {
+			fmt.Printf(" %10.2f", r.Correlation[a][b])
+		}
+		fmt.Println()
+	}
+}
+
+// correlation Pearson correlation ของสอง series (0 ถ้าข้อมูลไม่พอหรือไม่มีความแปรปรวน)
+func correlation(a, b []float64) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if n < 2 {
+		return 0
+	}
+
+	var meanA, meanB float64
+	for i := 0; i < n; i++ {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= float64(n)
+	meanB /= float64(n)
+
+	var cov, varA, varB float64
+	for i := 0; i < n; i++ {
+		da := a[i] - meanA
+		db := b[i] - meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+
+	if varA == 0 || varB == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varA*varB)
+}
@@ -0,0 +1,292 @@
+package trading
+
+import (
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+// holdStrategy เข้า LONG ทุกครั้งที่ว่างด้วย notional sizePct ของเงินทุน แล้วปิดหลังถือครบ hold แท่ง 15m
+type holdStrategy struct {
+	sizePct  float64
+	hold     int
+	leverage float64
+	onBar    func(bt *Backtester)
+}
+
+func (s *holdStrategy) Name() string                 { return "hold" }
+func (s *holdStrategy) WarmupBars() int              { return 1 }
+func (s *holdStrategy) Fees(bt *Backtester) FeeModel { return FeeModel{} }
+
+func (s *holdStrategy) OnBar(bt *Backtester) {
+	if s.onBar != nil {
+		s.onBar(bt)
+	}
+}
+
+func (s *holdStrategy) ExitSignal(bt *Backtester, pos *BacktestPosition) string {
+	if bt.currentTime.Sub(pos.EntryTime) >= time.Duration(s.hold)*15*time.Minute {
+		return "HOLD_DONE"
+	}
+	return ""
+}
+
+func (s *holdStrategy) EntrySignal(bt *Backtester) *EntrySignal {
+	return &EntrySignal{Side: "LONG", Leverage: s.leverage, Reason: "test"}
+}
+
+func (s *holdStrategy) PositionSize(bt *Backtester, signal *EntrySignal) float64 {
+	return bt.currentCapital * s.sizePct / bt.currentPrice
+}
+
+// portfolioNotional notional และ margin ของ position ที่เปิดอยู่ทั้งพอร์ต
+func portfolioNotional(p *PortfolioBacktester) (notional, margin float64) {
+	for _, slot := range p.slots {
+		if pos := slot.bt.position; pos != nil {
+			notional += pos.EntryPrice * pos.Quantity
+			margin += positionCapital(pos)
+		}
+	}
+	return notional, margin
+}
+
+// flatCandles แท่งเทียนราคาคงที่ (equity ไม่เปลี่ยนตามราคา จึงเทียบเงินทุนที่ใช้กับ equity ได้ตรงๆ)
+func flatCandles(n int) []OHLCV {
+	data := make([]OHLCV, n)
+	for i := range data {
+		data[i] = OHLCV{Timestamp: 1_700_000_000 + int64(i)*900, Open: 100, High: 100, Low: 100, Close: 100, Volume: 1000}
+	}
+	return data
+}
+
+func newTestPortfolio(t *testing.T, config PortfolioConfig, symbols int, data func(i int) []OHLCV, strategy func() Strategy) *PortfolioBacktester {
+	t.Helper()
+	p := NewPortfolioBacktester(config)
+	p.SetLogOutput(io.Discard)
+	for i := 0; i < symbols; i++ {
+		if _, err := p.AddSymbol(string(rune('A'+i))+"_USDT", data(i), strategy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func synthetic(i int) []OHLCV {
+	return syntheticCandles(300, int64(100+i))
+}
+
+func TestPortfolioCapitalSharedAcrossSymbols(t *testing.T) {
+	var p *PortfolioBacktester
+	check := func(bt *Backtester) {
+		if notional, _ := portfolioNotional(p); notional > p.equity()*1.0001 {
+			t.Fatalf("%s: notional ที่เปิดรวม $%.2f เกิน equity $%.2f", bt.symbol, notional, p.equity())
+		}
+	}
+	p = newTestPortfolio(t, PortfolioConfig{InitialCapital: 1000, MaxSymbolExposure: 0.9}, 4,
+		func(int) []OHLCV { return flatCandles(100) },
+		func() Strategy { return &holdStrategy{sizePct: 0.9, hold: 5, onBar: check} })
+
+	result, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SkippedEntries == 0 {
+		t.Fatal("ต้องมีสัญญาณที่ถูกข้ามเมื่อเงินทุนถูกใช้หมด")
+	}
+}
+
+func TestPortfolioMarginLimitWithLeverage(t *testing.T) {
+	var p *PortfolioBacktester
+	check := func(bt *Backtester) {
+		if _, margin := portfolioNotional(p); margin > p.equity()*1.0001 {
+			t.Fatalf("%s: margin รวม $%.2f เกิน equity $%.2f", bt.symbol, margin, p.equity())
+		}
+	}
+	p = newTestPortfolio(t, PortfolioConfig{InitialCapital: 1000}, 4,
+		func(int) []OHLCV { return flatCandles(100) },
+		func() Strategy { return &holdStrategy{sizePct: 2.5, hold: 5, leverage: 5, onBar: check} })
+	for _, slot := range p.slots {
+		slot.bt.SetFuturesAccount(DefaultFuturesAccount())
+	}
+
+	result, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MaxConcurrent != 2 {
+		t.Fatalf("margin 5x ของ 250%% ต่อเหรียญต้องเปิดได้ 2 เหรียญ (ได้ %d)", result.MaxConcurrent)
+	}
+}
+
+func TestPortfolioMaxPositions(t *testing.T) {
+	p := newTestPortfolio(t, PortfolioConfig{InitialCapital: 1000, MaxPositions: 2}, 4, synthetic,
+		func() Strategy { return &holdStrategy{sizePct: 0.1, hold: 3} })
+	result, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MaxConcurrent != 2 {
+		t.Fatalf("เปิดพร้อมกันสูงสุด %d, ต้องการ 2", result.MaxConcurrent)
+	}
+	if result.SkippedEntries == 0 {
+		t.Fatal("ต้องมีสัญญาณที่ถูกข้ามเพราะครบ MaxPositions")
+	}
+}
+
+func TestPortfolioSharedClock(t *testing.T) {
+	full := syntheticCandles(200, 7)
+	// เหรียญที่สองเริ่มช้ากว่าและมีแท่งหายบางช่วง
+	var sparse []OHLCV
+	for i, candle := range syntheticCandles(200, 8)[50:] {
+		if i%10 != 3 {
+			sparse = append(sparse, candle)
+		}
+	}
+
+	p := NewPortfolioBacktester(PortfolioConfig{InitialCapital: 1000})
+	p.SetLogOutput(io.Discard)
+	seen := map[string][]int64{}
+	record := func(bt *Backtester) {
+		seen[bt.symbol] = append(seen[bt.symbol], bt.ohlcvData[bt.currentIndex].Timestamp)
+		// ทุกเหรียญที่ถูกประมวลผลในรอบเดียวกันต้องอยู่ที่เวลาเดียวกัน
+		for _, slot := range p.slots {
+			if slot.bt != bt && slot.bt.currentTime.After(bt.currentTime) {
+				t.Fatalf("%s อยู่ที่ %v ก่อน %s ที่ %v", bt.symbol, bt.currentTime, slot.bt.symbol, slot.bt.currentTime)
+			}
+		}
+	}
+	if _, err := p.AddSymbol("FULL_USDT", full, &holdStrategy{sizePct: 0.1, hold: 4, onBar: record}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.AddSymbol("SPARSE_USDT", sparse, &holdStrategy{sizePct: 0.1, hold: 4, onBar: record}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.EquityCurve) != len(full) {
+		t.Fatalf("equity curve %d จุด, ต้องการ %d (union ของ timestamp)", len(result.EquityCurve), len(full))
+	}
+	if len(seen["FULL_USDT"]) != len(full)-1 || len(seen["SPARSE_USDT"]) != len(sparse)-1 {
+		t.Fatalf("ประมวลผล %d/%d แท่ง, ต้องการ %d/%d (ไม่รวม warmup)",
+			len(seen["FULL_USDT"]), len(seen["SPARSE_USDT"]), len(full)-1, len(sparse)-1)
+	}
+}
+
+func TestPortfolioHaltError(t *testing.T) {
+	errMiss := errors.New("AI cache miss")
+	p := newTestPortfolio(t, PortfolioConfig{InitialCapital: 1000}, 2, synthetic, func() Strategy {
+		return &holdStrategy{sizePct: 0.1, hold: 3, onBar: func(bt *Backtester) {
+			if bt.currentIndex == 20 {
+				bt.halt(errMiss)
+			}
+		}}
+	})
+	if _, err := p.Run(); !errors.Is(err, errMiss) {
+		t.Fatalf("Run คืน %v, ต้องการ %v", err, errMiss)
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	a := []float64{1, -2, 3, 0.5, -1}
+	neg := make([]float64, len(a))
+	scaled := make([]float64, len(a))
+	for i, v := range a {
+		neg[i] = -v
+		scaled[i] = 3*v + 2
+	}
+
+	for _, tc := range []struct {
+		name string
+		b    []float64
+		want float64
+	}{
+		{"self", a, 1},
+		{"scaled", scaled, 1},
+		{"negated", neg, -1},
+		{"constant", []float64{2, 2, 2, 2, 2}, 0},
+		{"too short", []float64{1}, 0},
+	} {
+		if got := correlation(a, tc.b); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%s: correlation %v, ต้องการ %v", tc.name, got, tc.want)
+		}
+	}
+	// ตามมือ: ส่วนเบี่ยงเบน (-1, 0, 1) กับ (-1, 1, 0) → cov 1, var 2 และ 2 → 0.5
+	if got := correlation([]float64{1, 2, 3}, []float64{1, 3, 2}); math.Abs(got-0.5) > 1e-12 {
+		t.Errorf("correlation([1 2 3], [1 3 2]) = %v, ต้องการ 0.5", got)
+	}
+}
+
+func TestPortfolioCorrelationMatrix(t *testing.T) {
+	data := syntheticCandles(600, 3)
+	p := NewPortfolioBacktester(PortfolioConfig{InitialCapital: 10000})
+	p.SetLogOutput(io.Discard)
+	for _, symbol := range []string{"A_USDT", "B_USDT"} {
+		if _, err := p.AddSymbol(symbol, data, &holdStrategy{sizePct: 0.1, hold: 8}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ขนาดไม้ต่างกันเล็กน้อยเพราะ B คำนวณจากเงินทุนหลัง A ในแท่งเดียวกัน
+	if got := result.Correlation["A_USDT"]["B_USDT"]; got < 0.999 {
+		t.Fatalf("เหรียญที่ข้อมูลและกลยุทธ์เหมือนกันต้อง correlation ≈ 1 (ได้ %v)", got)
+	}
+}
+
+func TestPortfolioCorrelationUsesReturns(t *testing.T) {
+	// ถือ 5 หน่วยจาก 100 ขณะราคาขึ้นวันละ 10%: PnL รายวัน $50 แล้ว $55 แต่ผลตอบแทนเทียบ equity ของเหรียญคือ 5% ทั้งสองวัน
+	data := fundingCandles(4, 24*time.Hour)
+	for i, price := range []float64{100, 110, 121, 121} {
+		data[i].Open, data[i].High, data[i].Low, data[i].Close = price, price, price, price
+	}
+	p := NewPortfolioBacktester(PortfolioConfig{InitialCapital: 1000})
+	p.SetLogOutput(io.Discard)
+	if _, err := p.AddSymbol("A_USDT", data, &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "LONG", Reason: "test"}},
+		quantity: 5,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []float64{0, 0.05, 55.0 / 1050, 0}
+	got := p.dailyReturns["A_USDT"]
+	if len(got) != len(want) {
+		t.Fatalf("ได้ %d วัน, ต้องการ %d", len(got), len(want))
+	}
+	for i := range want {
+		if !approx(got[i], want[i]) {
+			t.Fatalf("ผลตอบแทนวันที่ %d = %v, ต้องการ %v (ทั้งหมด %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestPortfolioCancelsOpenOrdersAtEnd(t *testing.T) {
+	// limit ซื้อที่ 50 ไม่มีวันถูก fill บนราคาคงที่ 100: ต้องถูกยกเลิกตอนจบเหมือน RunStrategy
+	p := NewPortfolioBacktester(PortfolioConfig{InitialCapital: 1000})
+	p.SetLogOutput(io.Discard)
+	strategy := &orderStrategy{bars: map[int]func(bt *Backtester){
+		0: func(bt *Backtester) {
+			mustPlace(t, bt, OrderRequest{Side: BuyOrder, Type: LimitOrder, Quantity: 1, Price: 50})
+		},
+	}}
+	if _, err := p.AddSymbol("A_USDT", flatCandles(10), strategy); err != nil {
+		t.Fatal(err)
+	}
+	result, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Orders) != 1 || result.Orders[0].Status != OrderCanceled || result.Orders[0].StatusReason != "END_OF_BACKTEST" {
+		t.Fatalf("order %+v, ต้องการ CANCELED ด้วย END_OF_BACKTEST", result.Orders)
+	}
+}
@@ -0,0 +1,217 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TradeLeg ไม้ย่อยของ position ที่ถูก pyramid
+type TradeLeg struct {
+	Level      int       `json:"level"`
+	EntryTime  time.Time `json:"entry_time"`
+	EntryPrice float64   `json:"entry_price"`
+	Quantity   float64   `json:"quantity"`
+	PnL        float64   `json:"pnl"` // PnL ก่อนค่าธรรมเนียมของไม้นี้
+	PnLPct     float64   `json:"pnl_pct"`
+}
+
+// NewPositionManagerWithLimits สร้าง PositionManager ด้วยค่าที่กำหนดเอง
+func NewPositionManagerWithLimits(maxPositions int, pyramidPnLMin, totalRisk float64) *PositionManager {
+	return &PositionManager{
+		maxPositions:  maxPositions,
+		pyramidPnLMin: pyramidPnLMin,
+		totalRisk:     totalRisk,
+	}
+}
+
+// PyramidConfig การตั้งค่าการเพิ่มไม้ใน config (risk.pyramid) ระบุแล้วถือว่าเปิด pyramiding
+type PyramidConfig struct {
+	MaxLegs      int     `json:"max_legs" yaml:"max_legs"`             // จำนวนไม้สูงสุดรวมไม้แรก
+	MinProfitPct float64 `json:"min_profit_pct" yaml:"min_profit_pct"` // กำไรขั้นต่ำ (%) ของไม้ล่าสุดก่อนเพิ่มไม้
+	TotalRisk    float64 `json:"total_risk" yaml:"total_risk"`         // ความเสี่ยงรวมถ้าโดน SL ทุกไม้ (0.06 = 6% ของเงินทุน)
+}
+
+// DefaultPyramidConfig ค่าเดียวกับ NewPositionManager: 3 ไม้, กำไร 1% ก่อนเพิ่ม, ความเสี่ยงรวม 6%
+func DefaultPyramidConfig() PyramidConfig {
+	return PyramidConfig{MaxLegs: 3, MinProfitPct: 1.0, TotalRisk: 0.06}
+}
+
+// Validate ตรวจสอบว่า config ของ pyramiding ใช้งานได้
+func (c PyramidConfig) Validate() error {
+	if c.MaxLegs < 2 {
+		return fmt.Errorf("pyramid max_legs ต้องอย่างน้อย 2 (รวมไม้แรก)")
+	}
+	if c.MinProfitPct < 0 {
+		return fmt.Errorf("pyramid min_profit_pct ต้องไม่ติดลบ")
+	}
+	if c.TotalRisk <= 0 || c.TotalRisk > 1 {
+		return fmt.Errorf("pyramid total_risk ต้องอยู่ระหว่าง 0-1")
+	}
+	return nil
+}
+
+// config การตั้งค่าที่สร้าง PositionManager นี้ได้ สำหรับบันทึกใน config ของผลลัพธ์
+func (pm *PositionManager) config() *PyramidConfig {
+	return &PyramidConfig{MaxLegs: pm.maxPositions, MinProfitPct: pm.pyramidPnLMin, TotalRisk: pm.totalRisk}
+}
+
+// SetPositionManager เปลี่ยนตัวจัดการ position (ค่า limit ของ pyramiding)
+func (bt *Backtester) SetPositionManager(pm *PositionManager) {
+	bt.positionManager = pm
+}
+
+// SetPyramiding เปิด/ปิดการเพิ่มไม้ (pyramiding) ตามกติกาของ PositionManager
+func (bt *Backtester) SetPyramiding(enabled bool) {
+	bt.pyramiding = enabled
+}
+
+// canAddLeg ตรวจว่าเพิ่มไม้ได้หรือไม่: ยังไม่ครบจำนวนไม้ และไม้ล่าสุดกำไรถึง pyramidPnLMin (%)
+func (pm *PositionManager) canAddLeg(legs int, lastLegPnLPct float64) bool {
+	return legs < pm.maxPositions && lastLegPnLPct >= pm.pyramidPnLMin
+}
+
+// positionRisk ความเสี่ยงรวมของ position ถ้าราคาวิ่งไปโดน Stop Loss (ไม่ติดลบ)
+func positionRisk(side string, entryPrice, stopLoss, quantity float64) float64 {
+	if stopLoss <= 0 {
+		return math.Inf(1)
+	}
+	if side == "LONG" {
+		return math.Max(0, (entryPrice-stopLoss)*quantity)
+	}
+	return math.Max(0, (stopLoss-entryPrice)*quantity)
+}
+
+// startLegs เริ่มบันทึกไม้แรกของ position ที่เพิ่งเปิด
+func (bt *Backtester) startLegs() {
+	bt.legs = []*StructuredPosition{{
+		BacktestPosition: &BacktestPosition{
+			Symbol:     bt.position.Symbol,
+			Side:       bt.position.Side,
+			EntryTime:  bt.position.EntryTime,
+			EntryPrice: bt.position.EntryPrice,
+			Quantity:   bt.position.Quantity,
+			StopLoss:   bt.position.StopLoss,
+			TakeProfit: bt.position.TakeProfit,
+		},
+		PositionLevel: 1,
+		BaseQuantity:  bt.position.Quantity,
+		ProfitTarget:  bt.position.TakeProfit,
+	}}
+}
+
+// checkPyramid เพิ่มไม้เมื่อไม้ล่าสุดมีกำไรถึงเกณฑ์ โดยความเสี่ยงรวมไม่เกิน totalRisk ของเงินทุน
+// ไม้ที่เพิ่มผ่าน entryLimit (ข้อจำกัดของ portfolio) และจำกัดด้วยเงินทุนที่ยังว่างเหมือนการเปิด position ใหม่
+// (spot ใช้ notional เต็ม, futures ใช้ margin ที่ leverage ของ position)
+func (bt *Backtester) checkPyramid() {
+	if !bt.pyramiding || bt.position == nil || len(bt.legs) == 0 {
+		return
+	}
+
+	pm := bt.positionManager
+	pos := bt.position
+	last := bt.legs[len(bt.legs)-1]
+
+	lastPnLPct := (bt.currentPrice - last.EntryPrice) / last.EntryPrice * 100
+	if pos.Side == "SHORT" {
+		lastPnLPct = -lastPnLPct
+	}
+	if !pm.canAddLeg(len(bt.legs), lastPnLPct) {
+		return
+	}
+
+	// ไม้ถัดไปเล็กลงตามระดับ (ระดับ 2 = 1/2, ระดับ 3 = 1/3 ของไม้แรก)
+	level := len(bt.legs) + 1
+	base := bt.legs[0].BaseQuantity
+	quantity := base / float64(level)
+
+	// จำกัดความเสี่ยงรวม (ถ้าโดน SL ทุกไม้) ไม่เกิน totalRisk ของเงินทุน
+	maxRisk := bt.currentCapital * pm.totalRisk
+	currentRisk := positionRisk(pos.Side, pos.EntryPrice, pos.StopLoss, pos.Quantity)
+	legRisk := positionRisk(pos.Side, bt.currentPrice, pos.StopLoss, 1)
+	if legRisk > 0 && currentRisk+legRisk*quantity > maxRisk {
+		quantity = (maxRisk - currentRisk) / legRisk
+	}
+	if quantity <= 0 || math.IsInf(quantity, 0) || math.IsNaN(quantity) {
+		return
+	}
+
+	signal := &EntrySignal{
+		Side:       pos.Side,
+		StopLoss:   pos.StopLoss,
+		TakeProfit: pos.TakeProfit,
+		Leverage:   pos.Leverage,
+		Reason:     fmt.Sprintf("PYRAMID_%d", level),
+	}
+	quantity = bt.affordableQuantity(bt.applyEntryLimit(signal, quantity, bt.currentPrice), bt.currentPrice)
+	margin := 0.0
+	if bt.futures != nil && pos.Leverage > 0 && quantity > 0 {
+		margin = bt.currentPrice * quantity / pos.Leverage
+		if free := bt.currentCapital - pos.Margin; margin > free {
+			quantity = math.Max(0, free) * pos.Leverage / bt.currentPrice
+			margin = math.Max(0, free)
+			bt.logf("⚠️ Margin ไม่พอ ลดขนาดไม้ระดับ %d เหลือ %.6f\n", level, quantity)
+		}
+	}
+	if quantity <= 0 {
+		return
+	}
+
+	// ราคาเข้าเฉลี่ยแบบถ่วงน้ำหนัก
+	totalQuantity := pos.Quantity + quantity
+	pos.EntryPrice = (pos.EntryPrice*pos.Quantity + bt.currentPrice*quantity) / totalQuantity
+	pos.Quantity = totalQuantity
+
+	if margin > 0 {
+		pos.Margin += margin
+		pos.LiquidationPrice = bt.liquidationPrice(pos)
+	}
+	if bt.fees.ChargeEntryOnOpen {
+		bt.currentCapital -= bt.currentPrice * quantity * bt.fees.EntryRate
+	}
+
+	bt.legs = append(bt.legs, &StructuredPosition{
+		BacktestPosition: &BacktestPosition{
+			Symbol:     pos.Symbol,
+			Side:       pos.Side,
+			EntryTime:  bt.currentTime,
+			EntryPrice: bt.currentPrice,
+			Quantity:   quantity,
+			StopLoss:   pos.StopLoss,
+			TakeProfit: pos.TakeProfit,
+		},
+		PositionLevel: level,
+		BaseQuantity:  base,
+		ProfitTarget:  pos.TakeProfit,
+	})
+
+	bt.logf("🔺 Pyramid ระดับ %d: %s เพิ่ม %.6f ที่ $%.2f, ราคาเฉลี่ย $%.2f, รวม %.6f\n",
+		level, pos.Side, quantity, bt.currentPrice, pos.EntryPrice, pos.Quantity)
+}
+
+// closeLegs คำนวณ PnL ของแต่ละไม้ที่ราคาปิด และล้างรายการไม้
+func (bt *Backtester) closeLegs(exitPrice float64) []TradeLeg {
+	if len(bt.legs) < 2 {
+		bt.legs = nil
+		return nil
+	}
+
+	legs := make([]TradeLeg, 0, len(bt.legs))
+	for _, leg := range bt.legs {
+		pnl := (exitPrice - leg.EntryPrice) * leg.Quantity
+		if leg.Side == "SHORT" {
+			pnl = -pnl
+		}
+		legs = append(legs, TradeLeg{
+			Level:      leg.PositionLevel,
+			EntryTime:  leg.EntryTime,
+			EntryPrice: leg.EntryPrice,
+			Quantity:   leg.Quantity,
+			PnL:        pnl,
+			PnLPct:     pnl / (leg.EntryPrice * leg.Quantity) * 100,
+		})
+	}
+
+	bt.legs = nil
+	return legs
+}
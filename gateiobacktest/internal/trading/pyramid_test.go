@@ -0,0 +1,167 @@
+package trading
+
+import (
+	"io"
+	"testing"
+)
+
+// pyramidCandles ราคาขึ้น 100 → 102 → 104 → 110: ไม้ล่าสุดกำไรเกิน 1% ที่แท่ง 1 และ 2 แล้วกลยุทธ์ออกที่แท่ง 3
+func pyramidCandles() []OHLCV {
+	return hourly(candle(100, 100, 100, 100), candle(102, 102, 102, 102), candle(104, 104, 104, 104), candle(110, 110, 110, 110))
+}
+
+// runPyramid เข้า LONG ขนาด quantity (SL ที่ stopLoss) ที่แท่งแรก เปิด pyramiding ตาม config แล้วออกที่แท่งสุดท้าย
+func runPyramid(t *testing.T, quantity, stopLoss float64, config PyramidConfig, setup func(bt *Backtester)) BacktestTrade {
+	t.Helper()
+	strategy := &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "LONG", StopLoss: stopLoss, Reason: "test"}},
+		quantity: quantity,
+		exits:    map[int]string{3: "EXIT"},
+	}
+	result := runScripted(t, pyramidCandles(), strategy, func(bt *Backtester) {
+		bt.SetPositionManager(NewPositionManagerWithLimits(config.MaxLegs, config.MinProfitPct, config.TotalRisk))
+		bt.SetPyramiding(true)
+		if setup != nil {
+			setup(bt)
+		}
+	})
+	if len(result.Trades) != 1 {
+		t.Fatalf("ได้ %d เทรด, ต้องการ 1", len(result.Trades))
+	}
+	return result.Trades[0]
+}
+
+func TestPyramidLegSizingAndAverageEntry(t *testing.T) {
+	// ไม้ 1: 5 @ 100, ไม้ 2: 5/2 @ 102, ไม้ 3: 5/3 @ 104 (notional รวม $928 ไม่เกินเงินทุน)
+	trade := runPyramid(t, 5, 90, PyramidConfig{MaxLegs: 3, MinProfitPct: 1, TotalRisk: 1}, nil)
+
+	quantities, prices := []float64{5, 2.5, 5.0 / 3}, []float64{100, 102, 104}
+	if len(trade.Legs) != 3 {
+		t.Fatalf("ได้ %d ไม้, ต้องการ 3", len(trade.Legs))
+	}
+	total, cost, pnl := 0.0, 0.0, 0.0
+	for i, leg := range trade.Legs {
+		wantPnL := (110 - prices[i]) * quantities[i]
+		if leg.Level != i+1 || !approx(leg.Quantity, quantities[i]) || leg.EntryPrice != prices[i] || !approx(leg.PnL, wantPnL) ||
+			!approx(leg.PnLPct, (110-prices[i])/prices[i]*100) {
+			t.Fatalf("ไม้ %d: %+v, ต้องการ %v @ %v PnL %v", i+1, leg, quantities[i], prices[i], wantPnL)
+		}
+		total += quantities[i]
+		cost += quantities[i] * prices[i]
+		pnl += leg.PnL
+	}
+
+	// ราคาเข้าเฉลี่ยถ่วงน้ำหนัก และ PnL ของเทรดเท่ากับผลรวม PnL รายไม้
+	if !approx(trade.Quantity, total) || !approx(trade.EntryPrice, cost/total) || !approx(trade.PnL, pnl) || !approx(pnl, 80) {
+		t.Fatalf("เทรด %v @ %v PnL %v, ต้องการ %v @ %v PnL %v (รวมรายไม้ %v)", trade.Quantity, trade.EntryPrice, trade.PnL, total, cost/total, 80.0, pnl)
+	}
+}
+
+func TestPyramidTotalRiskCap(t *testing.T) {
+	// 5 @ 100 SL 98 เสี่ยง $10, ไม้ใหม่ที่ 102 เสี่ยง $4 ต่อหน่วย: เพดาน 1.5% ของ $1000 = $15 เหลือที่ให้เพิ่ม 1.25 หน่วย
+	trade := runPyramid(t, 5, 98, PyramidConfig{MaxLegs: 2, MinProfitPct: 1, TotalRisk: 0.015}, nil)
+	if len(trade.Legs) != 2 || !approx(trade.Legs[1].Quantity, 1.25) {
+		t.Fatalf("ไม้ %+v, ต้องการไม้ที่สองขนาด 1.25", trade.Legs)
+	}
+	if risk := positionRisk("LONG", trade.EntryPrice, 98, trade.Quantity); !approx(risk, 15) {
+		t.Fatalf("ความเสี่ยงรวม $%v, ต้องการ $15", risk)
+	}
+
+	// ไม่มี Stop Loss: ความเสี่ยงไม่จำกัดจึงไม่เพิ่มไม้
+	if trade := runPyramid(t, 5, 0, PyramidConfig{MaxLegs: 3, MinProfitPct: 1, TotalRisk: 1}, nil); len(trade.Legs) != 0 {
+		t.Fatalf("ไม่มี SL แต่ได้ %d ไม้", len(trade.Legs))
+	}
+}
+
+func TestPyramidCapitalLimits(t *testing.T) {
+	config := PyramidConfig{MaxLegs: 2, MinProfitPct: 1, TotalRisk: 1}
+
+	// spot: ไม้แรกใช้ $900 จาก $1000 ไม้ที่สองจึงได้แค่ $100
+	trade := runPyramid(t, 9, 90, config, nil)
+	if len(trade.Legs) != 2 || !approx(trade.Legs[1].Quantity, 100.0/102) {
+		t.Fatalf("spot: ไม้ %+v, ต้องการไม้ที่สองขนาด %v", trade.Legs, 100.0/102)
+	}
+
+	// futures 2x: ไม้แรก margin $900 ไม้ที่สองจึงใช้ margin ได้ $100 = notional $200
+	trade = runPyramid(t, 18, 90, config, func(bt *Backtester) {
+		bt.SetFuturesAccount(FuturesAccount{MarginMode: IsolatedMargin, Leverage: 2, MaintenanceMarginRate: 0.005})
+	})
+	if len(trade.Legs) != 2 || !approx(trade.Legs[1].Quantity, 200.0/102) || !approx(trade.Margin, 1000) {
+		t.Fatalf("futures: ไม้ %+v margin %v, ต้องการไม้ที่สองขนาด %v และ margin $1000", trade.Legs, trade.Margin, 200.0/102)
+	}
+
+	// entryLimit (ข้อจำกัดของ portfolio) เห็นไม้ที่เพิ่มและปฏิเสธได้
+	var added []*EntrySignal
+	trade = runPyramid(t, 5, 90, config, func(bt *Backtester) {
+		bt.entryLimit = func(bt *Backtester, signal *EntrySignal, quantity, price float64) float64 {
+			if bt.position != nil {
+				added = append(added, signal)
+				return 0
+			}
+			return quantity
+		}
+	})
+	if len(trade.Legs) != 0 || len(added) == 0 || added[0].Reason != "PYRAMID_2" {
+		t.Fatalf("entryLimit ปฏิเสธแต่ได้ %d ไม้ (เรียก %d ครั้ง)", len(trade.Legs), len(added))
+	}
+}
+
+func TestPyramidPortfolioExposure(t *testing.T) {
+	// exposure ต่อเหรียญ 60%: ไม้แรก $500 ไม้ที่สองที่ 102 ได้ไม่เกิน 60% ของ equity $1010 ลบ $500 ที่ถืออยู่
+	p := NewPortfolioBacktester(PortfolioConfig{InitialCapital: 1000, MaxPositions: 1, MaxSymbolExposure: 0.6})
+	p.SetLogOutput(io.Discard)
+	bt, err := p.AddSymbol("A_USDT", pyramidCandles(), &scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "LONG", StopLoss: 90, Reason: "test"}},
+		quantity: 5,
+		exits:    map[int]string{3: "EXIT"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bt.SetPositionManager(NewPositionManagerWithLimits(2, 1, 1))
+	bt.SetPyramiding(true)
+
+	result, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// MaxPositions 1 ไม่กันการเพิ่มไม้ของ position เดิม และไม่นับเป็นสัญญาณที่ถูกข้าม
+	legs := bt.trades[0].Legs
+	if len(legs) != 2 || !approx(legs[1].Quantity, (1010*0.6-500)/102) || result.SkippedEntries != 0 {
+		t.Fatalf("ไม้ %+v ข้าม %d, ต้องการไม้ที่สองขนาด %v", legs, result.SkippedEntries, (1010*0.6-500)/102)
+	}
+}
+
+func TestPyramidFromConfig(t *testing.T) {
+	config := DefaultStrategyConfig()
+	config.Symbols = []string{"TEST_USDT"}
+	config.Risk.Pyramid = &PyramidConfig{MaxLegs: 3, MinProfitPct: 1, TotalRisk: 1}
+
+	bt := newQuietBacktester(t, "TEST_USDT", 0)
+	bt.LoadHistoricalData(pyramidCandles())
+	if err := bt.ApplyConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	result, err := bt.RunStrategy(&scriptedStrategy{
+		entries:  map[int]*EntrySignal{0: {Side: "LONG", StopLoss: 90, Reason: "test"}},
+		quantity: 5,
+		exits:    map[int]string{3: "EXIT"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Trades[0].Legs) != 3 {
+		t.Fatalf("ได้ %d ไม้, ต้องการ 3", len(result.Trades[0].Legs))
+	}
+	if recorded := result.Config.Risk.Pyramid; recorded == nil || *recorded != *config.Risk.Pyramid {
+		t.Fatalf("result.Config.Risk.Pyramid %+v, ต้องการ %+v", recorded, config.Risk.Pyramid)
+	}
+
+	config.Risk.Pyramid = &PyramidConfig{MaxLegs: 1, TotalRisk: 0.06}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Validate ต้องไม่ผ่านเมื่อ max_legs น้อยกว่า 2")
+	}
+	if got := DefaultPyramidConfig(); *NewPositionManager().config() != got {
+		t.Fatalf("DefaultPyramidConfig %+v ต่างจาก NewPositionManager %+v", got, *NewPositionManager().config())
+	}
+}
@@ -0,0 +1,152 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StrategyInfo ข้อมูลกลยุทธ์ใน registry สำหรับเรียกใช้ตามชื่อ (เช่นจาก CLI)
+type StrategyInfo struct {
+	Name        string          // ชื่อที่ใช้เรียก เช่น "pivot-supertrend"
+	Description string          // คำอธิบายสั้นๆ
+	New         func() Strategy // สร้าง instance ใหม่ทุกครั้ง (กลยุทธ์มี state ต่อการรัน)
+	Tunable     bool            // อ่านค่าจาก StrategyParams จึง optimize ได้
+	RiskSized   bool            // ขนาด position ตาม risk.risk_per_trade
+	NeedsAI     bool            // ต้องมี AI client หรือ AI cache
+}
+
+var strategyRegistry = map[string]StrategyInfo{}
+
+// RegisterStrategy เพิ่มกลยุทธ์เข้า registry (ชื่อซ้ำหรือไม่มี factory ถือเป็นข้อผิดพลาดของโปรแกรม)
+func RegisterStrategy(info StrategyInfo) {
+	if info.Name == "" || info.New == nil {
+		panic("RegisterStrategy: ต้องมีชื่อและ factory")
+	}
+	if _, exists := strategyRegistry[info.Name]; exists {
+		panic(fmt.Sprintf("RegisterStrategy: ชื่อกลยุทธ์ซ้ำ %q", info.Name))
+	}
+	strategyRegistry[info.Name] = info
+}
+
+// LookupStrategy หากลยุทธ์ตามชื่อ
+func LookupStrategy(name string) (StrategyInfo, error) {
+	info, ok := strategyRegistry[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return StrategyInfo{}, fmt.Errorf("ไม่รู้จักกลยุทธ์: %s (ดูรายชื่อด้วย list-strategies)", name)
+	}
+	return info, nil
+}
+
+// Strategies รายชื่อกลยุทธ์ทั้งหมดเรียงตามชื่อ
+func Strategies() []StrategyInfo {
+	list := make([]StrategyInfo, 0, len(strategyRegistry))
+	for _, info := range strategyRegistry {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func init() {
+	RegisterStrategy(StrategyInfo{
+		Name:        "pivot-supertrend",
+		Description: "Pivot Point SuperTrend + EMA100 พร้อม trailing stop (กลยุทธ์หลัก)",
+		New:         func() Strategy { return NewPivotSuperTrendStrategy() },
+		Tunable:     true,
+		RiskSized:   true,
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "ai-confirm",
+		Description: "Pivot SuperTrend ที่ให้ AI ยืนยันก่อนเข้าเทรด",
+		New:         func() Strategy { return NewAIConfirmStrategy() },
+		Tunable:     true,
+		RiskSized:   true,
+		NeedsAI:     true,
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "aggressive",
+		Description: "LONG ตาม SuperTrend + Volume + Momentum, R:R 1:4",
+		New:         func() Strategy { return NewAggressiveStrategy() },
+		Tunable:     true,
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "triple-ema-1h",
+		Description: "Triple EMA(9,21,50) + RSI + Volume, 5x leverage สำหรับ 1H",
+		New:         func() Strategy { return NewTripleEMA1HStrategy() },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "triple-ema-15m",
+		Description: "Triple EMA + RSI + MACD สำหรับ 15m",
+		New:         func() Strategy { return NewTripleEMA15mStrategy() },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "enhanced-triple-ema-1h",
+		Description: "Triple EMA(9,21,50) แบบให้คะแนน confidence ≥ 80%, SL 2xATR, R:R 1:2, 5x leverage",
+		New:         func() Strategy { return NewEMAScoreStrategy(EMAScoreConfidence) },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "real-market-ema",
+		Description: "EMA(9,21,50) + RSI + Volume ผ่านอย่างน้อย 4 ใน 5 เงื่อนไข, SL 2xATR, R:R 1:2",
+		New:         func() Strategy { return NewEMAScoreStrategy(EMAScoreChecklist) },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "pivot-supertrend-rsi",
+		Description: "Pivot SuperTrend พลิกเทรนด์ + แท่งก่อนหน้าตามทิศ + EMA100, ออกด้วย RSI 70/30",
+		New:         func() Strategy { return NewPivotRSIStrategy(false) },
+		Tunable:     true,
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "pivot-supertrend-trend",
+		Description: "Pivot SuperTrend ตามเทรนด์ + แท่งก่อนหน้าตามทิศและอยู่ฝั่ง EMA100, ออกด้วย RSI 70/30",
+		New:         func() Strategy { return NewPivotRSIStrategy(true) },
+		Tunable:     true,
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "simple-supertrend",
+		Description: "SuperTrend(10, 2) พลิกเทรนด์ + EMA21 + แท่งปัจจุบันตามทิศ",
+		New:         func() Strategy { return NewSimpleSuperTrendStrategy() },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "simplified-supertrend",
+		Description: "SuperTrend(10, 2.5) ตามเทรนด์ + แท่งก่อนหน้าตามทิศ + EMA50",
+		New:         func() Strategy { return NewSimplifiedSuperTrendStrategy() },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "multi-ema-supertrend",
+		Description: "SuperTrend(14, 2.5) + EMA100 พร้อมออกเมื่อเทรนด์กลับหรือหลุด EMA100 2%",
+		New:         func() Strategy { return NewMultiEMASuperTrendStrategy() },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "volume-breakout-ema",
+		Description: "Volume breakout ≥ 2x + EMA(9,21,50) เรียงตัว, ออกเมื่อ EMA ตัดกลับ",
+		New:         func() Strategy { return NewVolumeBreakoutEMAStrategy() },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "rsi-bounce",
+		Description: "RSI ตีกลับจาก oversold/overbought, SL 1% TP 2%",
+		New:         func() Strategy { return NewSimpleSignalStrategy("RSI Bounce Strategy", RSIBounceSignal) },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "volume-breakout",
+		Description: "Volume > 2x ค่าเฉลี่ย 10 แท่ง + ราคาเปลี่ยน > 1%, SL 1% TP 2%",
+		New:         func() Strategy { return NewSimpleSignalStrategy("Volume Breakout Strategy", VolumeBreakoutSignal) },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "price-action",
+		Description: "Engulfing และ Hammer/Doji, SL 1% TP 2%",
+		New:         func() Strategy { return NewSimpleSignalStrategy("Price Action Strategy", PriceActionSignal) },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "candle-patterns",
+		Description: "รูปแบบแท่งเทียนกลับตัวที่แข็งแรงที่สุด (strength ≥ 0.6), SL 1% TP 2%",
+		New:         func() Strategy { return NewSimpleSignalStrategy("Candle Pattern Strategy", CandlePatternSignal) },
+	})
+	RegisterStrategy(StrategyInfo{
+		Name:        "mean-reversion",
+		Description: "ราคาห่าง MA20 เกิน 2% แล้วเทรดกลับหาค่าเฉลี่ย, SL 1% TP 2%",
+		New:         func() Strategy { return NewSimpleSignalStrategy("Mean Reversion Strategy", MeanReversionSignal) },
+	})
+}
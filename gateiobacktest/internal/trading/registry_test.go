@@ -0,0 +1,47 @@
+package trading
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegisteredStrategiesRun(t *testing.T) {
+	// ทุกกลยุทธ์ใน registry สร้างได้จาก factory และรันจบบนข้อมูลสังเคราะห์ชุดเดียวกัน
+	// กลยุทธ์ที่ใช้ AI อ่านคำตัดสินจาก aiReplayFixture (บันทึกไว้สำหรับ SOL_USDT บนข้อมูลชุดนี้)
+	traded := 0
+	for _, info := range Strategies() {
+		t.Run(info.Name, func(t *testing.T) {
+			strategy := info.New()
+			if strategy == nil || strategy.Name() == "" {
+				t.Fatalf("factory คืนกลยุทธ์ %v ที่ไม่มีชื่อ", strategy)
+			}
+
+			bt, requests := newReplayBacktester(t, "SOL_USDT")
+			config := DefaultStrategyConfig()
+			config.Strategy = info.Name
+			config.Symbols = []string{"SOL_USDT"}
+			if err := bt.ApplyConfig(config); err != nil {
+				t.Fatal(err)
+			}
+			result, err := bt.RunStrategy(strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *requests != 0 || result.Config == nil || result.Config.Strategy != info.Name {
+				t.Fatalf("requests %d config %+v, ต้องการไม่เรียก network และบันทึกชื่อกลยุทธ์ %s", *requests, result.Config, info.Name)
+			}
+			if math.IsNaN(result.FinalCapital) || math.IsInf(result.FinalCapital, 0) || result.FinalCapital <= 0 ||
+				result.TotalTrades != len(result.Trades) || result.WinningTrades+result.LosingTrades != result.TotalTrades {
+				t.Fatalf("ผลลัพธ์ไม่สอดคล้อง: เงินทุน %v เทรด %d (ชนะ %d แพ้ %d, รายการ %d)",
+					result.FinalCapital, result.TotalTrades, result.WinningTrades, result.LosingTrades, len(result.Trades))
+			}
+			if len(result.Trades) > 0 {
+				traded++
+			}
+		})
+	}
+	if traded == 0 {
+		t.Fatal("ไม่มีกลยุทธ์ไหนเทรดเลย ข้อมูลทดสอบไม่ครอบคลุม")
+	}
+}
@@ -0,0 +1,501 @@
+package trading
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// ขนาดกราฟในรายงาน (หน่วย SVG)
+const (
+	reportChartWidth  = 1100.0
+	reportChartHeight = 280.0
+	reportPadLeft     = 70.0
+	reportPadRight    = 20.0
+	reportPadTop      = 15.0
+	reportPadBottom   = 30.0
+	reportMaxPoints   = 2000 // จำนวนจุดสูงสุดต่อเส้น (ลดจุดเมื่อข้อมูลยาวเกิน)
+)
+
+// reportPoint จุดบนกราฟเวลา
+type reportPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// reportChart แปลงเวลา/ค่า เป็นพิกัด SVG
+type reportChart struct {
+	height     float64
+	start, end time.Time
+	min, max   float64
+}
+
+func newReportChart(height float64, start, end time.Time, min, max float64) *reportChart {
+	if !end.After(start) {
+		end = start.Add(time.Hour)
+	}
+	if max <= min {
+		pad := math.Max(math.Abs(max)*0.01, 1)
+		min, max = min-pad, max+pad
+	}
+	return &reportChart{height: height, start: start, end: end, min: min, max: max}
+}
+
+func (c *reportChart) x(t time.Time) float64 {
+	span := c.end.Sub(c.start).Seconds()
+	return reportPadLeft + t.Sub(c.start).Seconds()/span*(reportChartWidth-reportPadLeft-reportPadRight)
+}
+
+func (c *reportChart) y(v float64) float64 {
+	plot := c.height - reportPadTop - reportPadBottom
+	return reportPadTop + (c.max-v)/(c.max-c.min)*plot
+}
+
+// open เปิด SVG พร้อมกรอบ เส้นกริด และป้ายแกน
+func (c *reportChart) open(b *strings.Builder, yFormat string) {
+	fmt.Fprintf(b, `<svg viewBox="0 0 %.0f %.0f" xmlns="http://www.w3.org/2000/svg">`, reportChartWidth, c.height)
+	fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#fff" stroke="#ddd"/>`,
+		reportPadLeft, reportPadTop, reportChartWidth-reportPadLeft-reportPadRight, c.height-reportPadTop-reportPadBottom)
+
+	for i := 0; i <= 4; i++ {
+		v := c.min + (c.max-c.min)*float64(i)/4
+		y := c.y(v)
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#eee"/>`,
+			reportPadLeft, y, reportChartWidth-reportPadRight, y)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" class="axis" text-anchor="end">%s</text>`,
+			reportPadLeft-6, y+4, fmt.Sprintf(yFormat, v))
+	}
+	for i := 0; i <= 4; i++ {
+		t := c.start.Add(time.Duration(float64(c.end.Sub(c.start)) * float64(i) / 4))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" class="axis" text-anchor="middle">%s</text>`,
+			c.x(t), c.height-10, t.UTC().Format("2006-01-02"))
+	}
+}
+
+// polyline วาดเส้น (และพื้นที่ใต้เส้นถึง base ถ้ากำหนด fill)
+func (c *reportChart) polyline(b *strings.Builder, points []reportPoint, stroke, fill string, base float64) {
+	if len(points) == 0 {
+		return
+	}
+	var path strings.Builder
+	for _, p := range points {
+		fmt.Fprintf(&path, "%.1f,%.1f ", c.x(p.Time), c.y(p.Value))
+	}
+	if fill != "" {
+		first, last := points[0], points[len(points)-1]
+		fmt.Fprintf(b, `<polygon points="%.1f,%.1f %s%.1f,%.1f" fill="%s" stroke="none"/>`,
+			c.x(first.Time), c.y(base), path.String(), c.x(last.Time), c.y(base), fill)
+	}
+	fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.2"/>`, path.String(), stroke)
+}
+
+// downsample ลดจำนวนจุดโดยเก็บจุดแรกและจุดสุดท้ายไว้เสมอ
+func downsample(points []reportPoint) []reportPoint {
+	if len(points) <= reportMaxPoints {
+		return points
+	}
+	stride := float64(len(points)-1) / float64(reportMaxPoints-1)
+	out := make([]reportPoint, 0, reportMaxPoints)
+	for i := 0; i < reportMaxPoints; i++ {
+		out = append(out, points[int(math.Round(float64(i)*stride))])
+	}
+	return out
+}
+
+// reportEquity equity ที่ใช้ในรายงาน: รายแท่งถ้ามี ไม่เช่นนั้นใช้รายวัน (ผลลัพธ์ JSON รุ่นเก่า)
+func reportEquity(result *BacktestResult) []reportPoint {
+	points := make([]reportPoint, 0, len(result.EquityCurve))
+	for _, p := range result.EquityCurve {
+		points = append(points, reportPoint{Time: p.Time, Value: p.Equity})
+	}
+	if len(points) == 0 {
+		for _, d := range result.DailyReturns {
+			points = append(points, reportPoint{Time: d.Date, Value: d.Equity})
+		}
+	}
+	return points
+}
+
+func seriesRange(points []reportPoint) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		min = math.Min(min, p.Value)
+		max = math.Max(max, p.Value)
+	}
+	return min, max
+}
+
+// equitySVG กราฟ equity เทียบเส้นเงินทุนเริ่มต้น
+func equitySVG(result *BacktestResult, equity []reportPoint) string {
+	if len(equity) == 0 {
+		return ""
+	}
+	points := downsample(equity)
+	min, max := seriesRange(points)
+	min = math.Min(min, result.InitialCapital)
+	max = math.Max(max, result.InitialCapital)
+
+	c := newReportChart(reportChartHeight, points[0].Time, points[len(points)-1].Time, min, max)
+	var b strings.Builder
+	c.open(&b, "$%.0f")
+	y := c.y(result.InitialCapital)
+	fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#999" stroke-dasharray="4 3"/>`,
+		reportPadLeft, y, reportChartWidth-reportPadRight, y)
+	c.polyline(&b, points, "#1f77b4", "rgba(31,119,180,0.12)", min)
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// underwaterSVG กราฟ drawdown (% จากจุดสูงสุดก่อนหน้า)
+func underwaterSVG(equity []reportPoint) string {
+	if len(equity) == 0 {
+		return ""
+	}
+	drawdown := make([]reportPoint, len(equity))
+	peak := equity[0].Value
+	for i, p := range equity {
+		peak = math.Max(peak, p.Value)
+		dd := 0.0
+		if peak > 0 {
+			dd = (p.Value - peak) / peak * 100
+		}
+		drawdown[i] = reportPoint{Time: p.Time, Value: dd}
+	}
+	points := downsample(drawdown)
+	min, _ := seriesRange(drawdown)
+
+	c := newReportChart(reportChartHeight*0.7, points[0].Time, points[len(points)-1].Time, min, 0)
+	var b strings.Builder
+	c.open(&b, "%.1f%%")
+	c.polyline(&b, points, "#d62728", "rgba(214,39,40,0.25)", 0)
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// priceSVG กราฟราคาปิดพร้อมจุดเข้า/ออก และเส้น SL/TP ระหว่างถือ position
+func priceSVG(result *BacktestResult, candles []OHLCV) string {
+	var prices []reportPoint
+	for _, candle := range candles {
+		t := time.Unix(candle.Timestamp, 0)
+		if t.Before(result.StartDate) || (!result.EndDate.IsZero() && t.After(result.EndDate)) {
+			continue
+		}
+		prices = append(prices, reportPoint{Time: t, Value: candle.Close})
+	}
+	if len(prices) == 0 {
+		return ""
+	}
+	points := downsample(prices)
+
+	min, max := seriesRange(prices)
+	c := newReportChart(reportChartHeight*1.4, prices[0].Time, prices[len(prices)-1].Time, min, max)
+	clamp := func(v float64) float64 { return math.Max(c.min, math.Min(c.max, v)) }
+
+	var b strings.Builder
+	c.open(&b, "%.4g")
+	c.polyline(&b, points, "#555", "", 0)
+
+	for _, trade := range result.Trades {
+		x1, x2 := c.x(trade.EntryTime), c.x(trade.ExitTime)
+		if trade.StopLoss > 0 {
+			y := c.y(clamp(trade.StopLoss))
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#d62728" stroke-dasharray="3 2"/>`, x1, y, x2, y)
+		}
+		if trade.TakeProfit > 0 {
+			y := c.y(clamp(trade.TakeProfit))
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#2ca02c" stroke-dasharray="3 2"/>`, x1, y, x2, y)
+		}
+
+		// จุดเข้า: สามเหลี่ยมชี้ขึ้น (LONG) / ชี้ลง (SHORT)
+		ey := c.y(trade.EntryPrice)
+		entry := fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f", x1, ey-6, x1-5, ey+4, x1+5, ey+4)
+		color := "#2ca02c"
+		if trade.Side == "SHORT" {
+			entry = fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f", x1, ey+6, x1-5, ey-4, x1+5, ey-4)
+			color = "#d62728"
+		}
+		fmt.Fprintf(&b, `<polygon points="%s" fill="%s"><title>#%d %s @ %.4f (%s)</title></polygon>`,
+			entry, color, trade.ID, trade.Side, trade.EntryPrice, html.EscapeString(trade.EntryReason))
+
+		// จุดออก: วงกลมเขียว (กำไร) / แดง (ขาดทุน)
+		exitColor := "#2ca02c"
+		if trade.NetPnL <= 0 {
+			exitColor = "#d62728"
+		}
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="4" fill="#fff" stroke="%s" stroke-width="2"><title>#%d ออก @ %.4f (%s) $%.2f</title></circle>`,
+			x2, c.y(trade.ExitPrice), exitColor, trade.ID, trade.ExitPrice, html.EscapeString(trade.ExitReason), trade.NetPnL)
+	}
+
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// monthlyReturns ผลตอบแทนรายเดือน (%) จาก equity ปิดวัน โดยเทียบกับ equity ปิดเดือนก่อนหน้า
+func monthlyReturns(result *BacktestResult) (years []int, returns map[int]map[time.Month]float64) {
+	returns = make(map[int]map[time.Month]float64)
+	prev := result.InitialCapital
+	var monthEnd float64
+	var current time.Time
+
+	flush := func() {
+		if current.IsZero() || prev <= 0 {
+			return
+		}
+		year := current.Year()
+		if _, ok := returns[year]; !ok {
+			returns[year] = make(map[time.Month]float64)
+			years = append(years, year)
+		}
+		returns[year][current.Month()] = (monthEnd - prev) / prev * 100
+		prev = monthEnd
+	}
+
+	for _, day := range result.DailyReturns {
+		d := day.Date.UTC()
+		if !current.IsZero() && (d.Year() != current.Year() || d.Month() != current.Month()) {
+			flush()
+		}
+		current = d
+		monthEnd = day.Equity
+	}
+	flush()
+
+	return years, returns
+}
+
+// heatmapSVG ตารางสีผลตอบแทนรายเดือน (แถว = ปี, คอลัมน์ = เดือน)
+func heatmapSVG(result *BacktestResult) string {
+	years, returns := monthlyReturns(result)
+	if len(years) == 0 {
+		return ""
+	}
+
+	maxAbs := 0.0
+	for _, months := range returns {
+		for _, r := range months {
+			maxAbs = math.Max(maxAbs, math.Abs(r))
+		}
+	}
+
+	const cellW, cellH, left, top = 80.0, 32.0, 60.0, 24.0
+	width := left + cellW*12 + 10
+	height := top + cellH*float64(len(years)) + 10
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %.0f %.0f" xmlns="http://www.w3.org/2000/svg">`, width, height)
+	for m := 0; m < 12; m++ {
+		fmt.Fprintf(&b, `<text x="%.1f" y="16" class="axis" text-anchor="middle">%s</text>`,
+			left+cellW*float64(m)+cellW/2, time.Month(m + 1).String()[:3])
+	}
+	for row, year := range years {
+		y := top + cellH*float64(row)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" class="axis" text-anchor="end">%d</text>`, left-8, y+cellH/2+4, year)
+		for m := 0; m < 12; m++ {
+			x := left + cellW*float64(m)
+			r, ok := returns[year][time.Month(m+1)]
+			if !ok {
+				fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#f5f5f5" stroke="#fff"/>`,
+					x, y, cellW, cellH)
+				continue
+			}
+			alpha := 0.1
+			if maxAbs > 0 {
+				alpha = 0.1 + 0.8*math.Abs(r)/maxAbs
+			}
+			fill := fmt.Sprintf("rgba(44,160,44,%.2f)", alpha)
+			if r < 0 {
+				fill = fmt.Sprintf("rgba(214,39,40,%.2f)", alpha)
+			}
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="#fff"/>`,
+				x, y, cellW, cellH, fill)
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" class="cell" text-anchor="middle">%.2f%%</text>`,
+				x+cellW/2, y+cellH/2+4, r)
+		}
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// reportData ข้อมูลสำหรับ template
+type reportData struct {
+	Result     *BacktestResult
+	Generated  string
+	Equity     template.HTML
+	Underwater template.HTML
+	Price      template.HTML
+	Heatmap    template.HTML
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04") },
+	"unix":     func(t time.Time) int64 { return t.Unix() },
+	"duration": func(d time.Duration) string { return d.Round(time.Minute).String() },
+	"seconds":  func(d time.Duration) int64 { return int64(d.Seconds()) },
+	"pnlClass": func(v float64) string {
+		if v > 0 {
+			return "win"
+		}
+		return "loss"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="th">
+<head>
+<meta charset="utf-8">
+<title>Backtest {{.Result.Symbol}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Tahoma, sans-serif; margin: 24px; color: #222; background: #fafafa; }
+h1 { margin-bottom: 4px; }
+h2 { margin-top: 32px; font-size: 18px; }
+.sub { color: #777; margin-top: 0; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; }
+.card { background: #fff; border: 1px solid #e3e3e3; border-radius: 6px; padding: 10px 14px; min-width: 130px; }
+.card b { display: block; font-size: 18px; margin-top: 2px; }
+svg { width: 100%; height: auto; background: #fff; border: 1px solid #e3e3e3; border-radius: 6px; }
+svg .axis { font-size: 11px; fill: #666; }
+svg .cell { font-size: 12px; fill: #222; }
+table { border-collapse: collapse; width: 100%; background: #fff; font-size: 13px; }
+th, td { border: 1px solid #e3e3e3; padding: 4px 8px; text-align: right; white-space: nowrap; }
+th { background: #f0f0f0; cursor: pointer; user-select: none; }
+th.asc::after { content: " ▲"; }
+th.desc::after { content: " ▼"; }
+td.text { text-align: left; }
+.win { color: #2ca02c; }
+.loss { color: #d62728; }
+.empty { color: #999; }
+</style>
+</head>
+<body>
+<h1>📊 Backtest {{.Result.Symbol}}</h1>
+<p class="sub">{{date .Result.StartDate}} → {{date .Result.EndDate}} (UTC) · สร้างเมื่อ {{.Generated}}</p>
+
+{{with .Result}}
+<div class="cards">
+<div class="card">เงินทุนเริ่มต้น<b>${{printf "%.2f" .InitialCapital}}</b></div>
+<div class="card">เงินทุนสุดท้าย<b>${{printf "%.2f" .FinalCapital}}</b></div>
+<div class="card">ผลตอบแทน<b class="{{pnlClass .TotalReturnPct}}">{{printf "%.2f" .TotalReturnPct}}%</b></div>
+<div class="card">Max Drawdown<b class="loss">{{printf "%.2f" .MaxDrawdownPct}}%</b></div>
+<div class="card">จำนวนเทรด<b>{{.TotalTrades}}</b></div>
+<div class="card">อัตราชนะ<b>{{printf "%.2f" .WinRate}}%</b></div>
+<div class="card">Sharpe<b>{{printf "%.2f" .Metrics.SharpeRatio}}</b></div>
+<div class="card">Sortino<b>{{printf "%.2f" .Metrics.SortinoRatio}}</b></div>
+<div class="card">Profit Factor<b>{{printf "%.2f" .Metrics.ProfitFactor}}</b></div>
+<div class="card">Expectancy<b>${{printf "%.2f" .Metrics.Expectancy}}</b></div>
+<div class="card">Exposure<b>{{printf "%.2f" .Metrics.ExposurePct}}%</b></div>
+<div class="card">Funding<b>${{printf "%.2f" .TotalFunding}}</b></div>
+</div>
+{{end}}
+
+<h2>📈 Equity Curve</h2>
+{{if .Equity}}{{.Equity}}{{else}}<p class="empty">ไม่มีข้อมูล equity</p>{{end}}
+
+<h2>📉 Drawdown (Underwater)</h2>
+{{if .Underwater}}{{.Underwater}}{{else}}<p class="empty">ไม่มีข้อมูล equity</p>{{end}}
+
+<h2>🕯️ ราคาและจุดเข้า/ออก</h2>
+{{if .Price}}{{.Price}}<p class="sub">▲ เข้า LONG · ▼ เข้า SHORT · ○ จุดออก (เขียว = กำไร, แดง = ขาดทุน) · เส้นประ = SL (แดง) / TP (เขียว)</p>{{else}}<p class="empty">ไม่มีข้อมูลราคา</p>{{end}}
+
+<h2>🗓️ ผลตอบแทนรายเดือน</h2>
+{{if .Heatmap}}{{.Heatmap}}{{else}}<p class="empty">ไม่มีข้อมูลรายวัน</p>{{end}}
+
+<h2>🔍 รายการเทรด</h2>
+{{if .Result.Trades}}
+<table id="trades">
+<thead><tr>
+<th data-type="num">#</th><th data-type="text">Side</th><th data-type="num">เข้า</th><th data-type="num">ออก</th>
+<th data-type="num">ราคาเข้า</th><th data-type="num">ราคาออก</th><th data-type="num">SL</th><th data-type="num">TP</th>
+<th data-type="num">จำนวน</th><th data-type="num">Net PnL</th><th data-type="num">PnL %</th>
+<th data-type="num">MAE %</th><th data-type="num">MFE %</th><th data-type="num">ระยะเวลา</th><th data-type="text">เหตุผลออก</th>
+</tr></thead>
+<tbody>
+{{range .Result.Trades}}<tr>
+<td data-v="{{.ID}}">{{.ID}}</td>
+<td class="text">{{.Side}}</td>
+<td data-v="{{unix .EntryTime}}">{{date .EntryTime}}</td>
+<td data-v="{{unix .ExitTime}}">{{date .ExitTime}}</td>
+<td data-v="{{.EntryPrice}}">{{printf "%.4f" .EntryPrice}}</td>
+<td data-v="{{.ExitPrice}}">{{printf "%.4f" .ExitPrice}}</td>
+<td data-v="{{.StopLoss}}">{{printf "%.4f" .StopLoss}}</td>
+<td data-v="{{.TakeProfit}}">{{printf "%.4f" .TakeProfit}}</td>
+<td data-v="{{.Quantity}}">{{printf "%.6f" .Quantity}}</td>
+<td data-v="{{.NetPnL}}" class="{{pnlClass .NetPnL}}">{{printf "%.2f" .NetPnL}}</td>
+<td data-v="{{.PnLPct}}" class="{{pnlClass .PnLPct}}">{{printf "%.2f" .PnLPct}}</td>
+<td data-v="{{.MAEPct}}">{{printf "%.2f" .MAEPct}}</td>
+<td data-v="{{.MFEPct}}">{{printf "%.2f" .MFEPct}}</td>
+<td data-v="{{seconds .Duration}}">{{duration .Duration}}</td>
+<td class="text">{{.ExitReason}}</td>
+</tr>{{end}}
+</tbody>
+</table>
+<script>
+(function () {
+  var table = document.getElementById("trades");
+  var headers = table.tHead.rows[0].cells;
+  for (var i = 0; i < headers.length; i++) {
+    headers[i].addEventListener("click", sortBy.bind(null, i));
+  }
+  function sortBy(col) {
+    var th = headers[col];
+    var asc = !th.classList.contains("asc");
+    for (var i = 0; i < headers.length; i++) headers[i].classList.remove("asc", "desc");
+    th.classList.add(asc ? "asc" : "desc");
+    var numeric = th.dataset.type === "num";
+    var body = table.tBodies[0];
+    var rows = Array.prototype.slice.call(body.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[col], y = b.cells[col];
+      var cmp = numeric
+        ? parseFloat(x.dataset.v) - parseFloat(y.dataset.v)
+        : x.textContent.localeCompare(y.textContent);
+      return asc ? cmp : -cmp;
+    });
+    rows.forEach(function (row) { body.appendChild(row); });
+  }
+})();
+</script>
+{{else}}<p class="empty">ไม่มีการเทรดในช่วงเวลาที่ทดสอบ</p>{{end}}
+</body>
+</html>
+`))
+
+// GenerateHTMLReport สร้างรายงาน backtest เป็น HTML ไฟล์เดียว (กราฟเป็น SVG ในตัว เปิดดูได้แบบออฟไลน์)
+// candles ใช้วาดกราฟราคา ถ้าเป็น nil จะข้ามกราฟราคา
+func GenerateHTMLReport(result *BacktestResult, candles []OHLCV) ([]byte, error) {
+	if result == nil {
+		return nil, fmt.Errorf("ไม่มีผล backtest สำหรับสร้างรายงาน")
+	}
+
+	equity := reportEquity(result)
+	data := reportData{
+		Result:     result,
+		Generated:  time.Now().Format("2006-01-02 15:04:05"),
+		Equity:     template.HTML(equitySVG(result, equity)),
+		Underwater: template.HTML(underwaterSVG(equity)),
+		Price:      template.HTML(priceSVG(result, candles)),
+		Heatmap:    template.HTML(heatmapSVG(result)),
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("ไม่สามารถสร้างรายงาน HTML ได้: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SaveHTMLReport สร้างรายงาน HTML และบันทึกลงไฟล์
+func SaveHTMLReport(filename string, result *BacktestResult, candles []OHLCV) error {
+	report, err := GenerateHTMLReport(result, candles)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, report, 0644); err != nil {
+		return fmt.Errorf("ไม่สามารถบันทึกรายงานได้: %v", err)
+	}
+
+	fmt.Printf("📄 บันทึกรายงาน HTML: %s\n", filename)
+	return nil
+}
@@ -0,0 +1,45 @@
+package trading
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportPriceChartUsesTradedBars(t *testing.T) {
+	// ข้อมูลปี 2023 แต่ backtester ถูกสร้างด้วย days (startDate/endDate อิง time.Now) เหมือน CLI
+	data := syntheticCandles(1500, 12)
+	bt := newQuietBacktester(t, "TEST_USDT", 365)
+	bt.LoadHistoricalData(data)
+	result, err := bt.RunStrategy(NewPivotSuperTrendStrategy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Trades) == 0 {
+		t.Fatal("ต้องมีเทรดเพื่อตรวจจุดเข้า/ออกบนกราฟ")
+	}
+
+	warmup := NewPivotSuperTrendStrategy().WarmupBars()
+	first, last := time.Unix(data[warmup].Timestamp, 0), time.Unix(data[len(data)-1].Timestamp, 0)
+	if !result.StartDate.Equal(first) || !result.EndDate.Equal(last) {
+		t.Fatalf("ช่วงเวลา %v ถึง %v, ต้องการ %v ถึง %v", result.StartDate, result.EndDate, first, last)
+	}
+
+	html, err := GenerateHTMLReport(result, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(html)
+	if strings.Contains(page, "ไม่มีข้อมูลราคา") {
+		t.Fatal("กราฟราคาว่าง")
+	}
+	price := priceSVG(result, data)
+	for _, marker := range []string{"<polyline", "<polygon", "<circle", `stroke="#d62728" stroke-dasharray`, `stroke="#2ca02c" stroke-dasharray`} {
+		if !strings.Contains(price, marker) {
+			t.Errorf("กราฟราคาไม่มี %s", marker)
+		}
+	}
+	if got, want := strings.Count(price, "<polygon"), len(result.Trades); got != want {
+		t.Errorf("จุดเข้า %d จุด, ต้องการ %d ตามจำนวนเทรด", got, want)
+	}
+}
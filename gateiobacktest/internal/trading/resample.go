@@ -0,0 +1,168 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseInterval แปลง interval แบบ Gate.io ("1m", "15m", "1h", "4h", "1d", "7d", "1w") เป็น time.Duration
+func ParseInterval(interval string) (time.Duration, error) {
+	interval = strings.TrimSpace(strings.ToLower(interval))
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("interval ไม่ถูกต้อง: %q", interval)
+	}
+
+	n, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("interval ไม่ถูกต้อง: %q", interval)
+	}
+
+	unit := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}[interval[len(interval)-1]]
+	if unit == 0 {
+		return 0, fmt.Errorf("ไม่รู้จักหน่วยของ interval: %q", interval)
+	}
+
+	return time.Duration(n) * unit, nil
+}
+
+// Resample รวมแท่งเทียน (เรียงตามเวลา) เป็น timeframe ที่ใหญ่ขึ้น
+// แท่งใหม่เริ่มที่ขอบเวลา UTC ของ interval (เช่น 4h เริ่ม 00:00, 04:00, ... และ 1d เริ่ม 00:00 UTC)
+// ส่วน interval ที่เป็นจำนวนสัปดาห์ ("1w", "7d") เริ่มวันจันทร์ 00:00 UTC
+// Open = Open แท่งแรก, High/Low = สูงสุด/ต่ำสุด, Close = Close แท่งสุดท้าย, Volume = ผลรวม
+// แท่งสุดท้ายอาจยังไม่ครบ interval ถ้าข้อมูลจบกลางช่วง
+func Resample(data []OHLCV, interval string) ([]OHLCV, error) {
+	duration, err := ParseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	return resampleDuration(data, duration), nil
+}
+
+// resampleDuration รวมแท่งเทียนตามช่วงเวลา duration
+func resampleDuration(data []OHLCV, duration time.Duration) []OHLCV {
+	seconds := int64(duration / time.Second)
+	var result []OHLCV
+
+	for _, candle := range data {
+		bucket := bucketStart(candle.Timestamp, seconds)
+
+		if n := len(result); n > 0 && result[n-1].Timestamp == bucket {
+			bar := &result[n-1]
+			if candle.High > bar.High {
+				bar.High = candle.High
+			}
+			if candle.Low < bar.Low {
+				bar.Low = candle.Low
+			}
+			bar.Close = candle.Close
+			bar.Volume += candle.Volume
+			continue
+		}
+
+		result = append(result, OHLCV{
+			Timestamp: bucket,
+			Open:      candle.Open,
+			High:      candle.High,
+			Low:       candle.Low,
+			Close:     candle.Close,
+			Volume:    candle.Volume,
+		})
+	}
+
+	return result
+}
+
+// weekAnchor วันจันทร์ 00:00 UTC แรกหลัง epoch (1970-01-01 เป็นวันพฤหัสบดี)
+const weekAnchor = 4 * 24 * 60 * 60
+
+// bucketStart เวลาเริ่มของช่วงยาว seconds ที่ timestamp อยู่ (ช่วงรายสัปดาห์นับจากวันจันทร์)
+func bucketStart(timestamp, seconds int64) int64 {
+	anchor := int64(0)
+	if seconds%(7*24*60*60) == 0 {
+		anchor = weekAnchor
+	}
+	return timestamp - floorMod(timestamp-anchor, seconds)
+}
+
+// floorMod เศษที่ไม่ติดลบ (รองรับ timestamp ก่อน 1970)
+func floorMod(a, b int64) int64 {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
+
+// timeframeFeed แท่งเทียน timeframe ใหญ่ที่ resample จากข้อมูลของ backtester
+type timeframeFeed struct {
+	duration time.Duration
+	bars     []OHLCV
+
+	// แหล่งข้อมูลที่ใช้ resample (สร้างใหม่เมื่อข้อมูลของ backtester เปลี่ยน)
+	sourceLen   int
+	sourceFirst int64
+}
+
+// baseDuration ความยาวแท่งของข้อมูลหลัก (ระยะห่างที่น้อยที่สุดระหว่างแท่งติดกัน)
+func (bt *Backtester) baseDuration() time.Duration {
+	return dataInterval(bt.ohlcvData)
+}
+
+// dataInterval ระยะห่างที่เล็กที่สุดระหว่างแท่งใน 100 แท่งแรก (0 = ข้อมูลไม่พอ)
+func dataInterval(data []OHLCV) time.Duration {
+	var smallest int64
+	for i := 1; i < len(data) && i <= 100; i++ {
+		if gap := data[i].Timestamp - data[i-1].Timestamp; gap > 0 && (smallest == 0 || gap < smallest) {
+			smallest = gap
+		}
+	}
+	return time.Duration(smallest) * time.Second
+}
+
+// HigherTimeframe แท่งเทียน timeframe ใหญ่ที่ "ปิดแล้ว" ณ แท่งปัจจุบันของ backtester
+// แท่งที่ยังไม่ปิดจะไม่ถูกส่งคืน จึงไม่มี look-ahead (เช่น ตอน 1h แท่ง 13:00 ของวันนี้ ได้ 1d ถึงเมื่อวาน)
+func (bt *Backtester) HigherTimeframe(interval string) ([]OHLCV, error) {
+	if len(bt.ohlcvData) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาสำหรับ resample")
+	}
+
+	duration, err := ParseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	base := bt.baseDuration()
+	if duration < base {
+		return nil, fmt.Errorf("timeframe %s เล็กกว่าข้อมูลหลัก (%v)", interval, base)
+	}
+
+	if bt.timeframes == nil {
+		bt.timeframes = make(map[time.Duration]*timeframeFeed)
+	}
+	feed := bt.timeframes[duration]
+	if feed == nil || feed.sourceLen != len(bt.ohlcvData) || feed.sourceFirst != bt.ohlcvData[0].Timestamp {
+		feed = &timeframeFeed{
+			duration:    duration,
+			bars:        resampleDuration(bt.ohlcvData, duration),
+			sourceLen:   len(bt.ohlcvData),
+			sourceFirst: bt.ohlcvData[0].Timestamp,
+		}
+		bt.timeframes[duration] = feed
+	}
+
+	// แท่งปัจจุบันปิดที่ timestamp + base จึงรวมเฉพาะแท่งใหญ่ที่ปิดไม่เกินเวลานั้น
+	closedAt := bt.ohlcvData[bt.currentIndex].Timestamp + int64(base/time.Second)
+	span := int64(duration / time.Second)
+	n := sort.Search(len(feed.bars), func(i int) bool {
+		return feed.bars[i].Timestamp+span > closedAt
+	})
+	return feed.bars[:n], nil
+}
@@ -0,0 +1,139 @@
+package trading
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// quarterHours แท่ง 15m จาก syntheticCandles ที่เริ่ม 2023-11-14 22:00 UTC (วันอังคาร) ทำให้แท่ง 4h และ 1d แรกไม่ครบช่วง
+func quarterHours(n int) []OHLCV {
+	data := syntheticCandles(n, 5)
+	for i := range data {
+		data[i].Timestamp = 1_699_920_000 + 22*3600 + int64(i)*900
+	}
+	return data
+}
+
+// groupBars รวมแท่งตามเวลาเริ่มช่วงที่ได้จาก start (คำนวณแยกจาก Resample ด้วย package time)
+func groupBars(data []OHLCV, start func(t time.Time) time.Time) []OHLCV {
+	var bars []OHLCV
+	for _, c := range data {
+		key := start(time.Unix(c.Timestamp, 0).UTC()).Unix()
+		if n := len(bars); n > 0 && bars[n-1].Timestamp == key {
+			bar := &bars[n-1]
+			bar.High = math.Max(bar.High, c.High)
+			bar.Low = math.Min(bar.Low, c.Low)
+			bar.Close = c.Close
+			bar.Volume += c.Volume
+			continue
+		}
+		bars = append(bars, OHLCV{Timestamp: key, Open: c.Open, High: c.High, Low: c.Low, Close: c.Close, Volume: c.Volume})
+	}
+	return bars
+}
+
+func TestResampleQuarterHours(t *testing.T) {
+	data := quarterHours(4 * 24 * 3)
+	monday := func(t time.Time) time.Time {
+		day := t.Truncate(24 * time.Hour)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	for _, tc := range []struct {
+		interval string
+		start    func(t time.Time) time.Time
+		bars     int
+	}{
+		{"1h", func(t time.Time) time.Time { return t.Truncate(time.Hour) }, 72},
+		{"4h", func(t time.Time) time.Time { return t.Truncate(4 * time.Hour) }, 19},
+		{"1d", func(t time.Time) time.Time { return t.Truncate(24 * time.Hour) }, 4},
+		{"1w", monday, 1},
+		{"7d", monday, 1},
+	} {
+		t.Run(tc.interval, func(t *testing.T) {
+			got, err := Resample(data, tc.interval)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := groupBars(data, tc.start)
+			if len(got) != tc.bars || len(want) != tc.bars {
+				t.Fatalf("ได้ %d แท่ง (อ้างอิง %d), ต้องการ %d", len(got), len(want), tc.bars)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("แท่ง %d: %+v, ต้องการ %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResampleWeeksStartMonday(t *testing.T) {
+	data := quarterHours(4 * 24 * 21)
+	for _, interval := range []string{"1w", "7d"} {
+		bars, err := Resample(data, interval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 2023-11-14 (อังคาร) ถึง 2023-12-05: สัปดาห์ที่เริ่ม 13, 20, 27 พ.ย. และ 4 ธ.ค.
+		if len(bars) != 4 {
+			t.Fatalf("%s: ได้ %d แท่ง, ต้องการ 4", interval, len(bars))
+		}
+		for i, bar := range bars {
+			start := time.Unix(bar.Timestamp, 0).UTC()
+			if want := time.Date(2023, 11, 13+7*i, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+				t.Fatalf("%s แท่ง %d เริ่ม %v, ต้องการวันจันทร์ %v", interval, i, start, want)
+			}
+		}
+	}
+}
+
+func TestHigherTimeframeReturnsClosedBarsOnly(t *testing.T) {
+	data := quarterHours(4 * 24 * 3)
+	bt := newQuietBacktester(t, "TEST_USDT", 0)
+	bt.LoadHistoricalData(data)
+
+	for _, interval := range []string{"1h", "4h", "1d"} {
+		full, err := Resample(data, interval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		span, _ := ParseInterval(interval)
+		for i := range data {
+			bt.currentIndex = i
+			bars, err := bt.HigherTimeframe(interval)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// แท่ง 15m ปัจจุบันปิดที่ timestamp + 15m: นับเฉพาะแท่งใหญ่ที่ปิดไม่เกินเวลานั้น
+			closedAt := data[i].Timestamp + 900
+			want := 0
+			for want < len(full) && full[want].Timestamp+int64(span/time.Second) <= closedAt {
+				want++
+			}
+			if len(bars) != want {
+				t.Fatalf("%s ที่แท่ง %d (%s): ได้ %d แท่งที่ปิดแล้ว, ต้องการ %d",
+					interval, i, time.Unix(data[i].Timestamp, 0).UTC().Format("01-02 15:04"), len(bars), want)
+			}
+			if want > 0 && bars[want-1] != full[want-1] {
+				t.Fatalf("%s ที่แท่ง %d: แท่งล่าสุด %+v ไม่ตรงกับแท่งที่ครบช่วง %+v", interval, i, bars[want-1], full[want-1])
+			}
+		}
+	}
+
+	// ตัวอย่างเจาะจง: 13:30 ยังไม่มีแท่ง 1h ของ 13:00 แต่ 13:45 มีแล้ว
+	at := func(hhmm string) int {
+		ts, _ := time.Parse("2006-01-02 15:04", "2023-11-15 "+hhmm)
+		return int((ts.Unix() - data[0].Timestamp) / 900)
+	}
+	bt.currentIndex = at("13:30")
+	before, _ := bt.HigherTimeframe("1h")
+	bt.currentIndex = at("13:45")
+	after, _ := bt.HigherTimeframe("1h")
+	if last := time.Unix(before[len(before)-1].Timestamp, 0).UTC(); last.Hour() != 12 {
+		t.Fatalf("ที่ 13:30 แท่ง 1h ล่าสุดเริ่ม %v, ต้องการ 12:00", last)
+	}
+	if last := time.Unix(after[len(after)-1].Timestamp, 0).UTC(); last.Hour() != 13 {
+		t.Fatalf("ที่ 13:45 แท่ง 1h ล่าสุดเริ่ม %v, ต้องการ 13:00", last)
+	}
+}
@@ -0,0 +1,303 @@
+package trading
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// resultsSchema ตารางของ results store (runs 1 แถวต่อการรัน, trades 1 แถวต่อเทรด)
+const resultsSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at       TEXT NOT NULL,
+	strategy         TEXT NOT NULL,
+	symbol           TEXT NOT NULL,
+	timeframe        TEXT NOT NULL,
+	start_date       TEXT NOT NULL,
+	end_date         TEXT NOT NULL,
+	data_fingerprint TEXT NOT NULL,
+	bars             INTEGER NOT NULL,
+	initial_capital  REAL NOT NULL,
+	final_capital    REAL NOT NULL,
+	total_return_pct REAL NOT NULL,
+	total_trades     INTEGER NOT NULL,
+	winning_trades   INTEGER NOT NULL,
+	losing_trades    INTEGER NOT NULL,
+	win_rate         REAL NOT NULL,
+	max_drawdown     REAL NOT NULL,
+	max_drawdown_pct REAL NOT NULL,
+	total_funding    REAL NOT NULL,
+	metrics          TEXT NOT NULL,
+	config           TEXT NOT NULL,
+	note             TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS trades (
+	run_id      INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	seq         INTEGER NOT NULL,
+	side        TEXT NOT NULL,
+	entry_time  TEXT NOT NULL,
+	exit_time   TEXT NOT NULL,
+	net_pnl     REAL NOT NULL,
+	exit_reason TEXT NOT NULL,
+	data        TEXT NOT NULL,
+	PRIMARY KEY (run_id, seq)
+);
+CREATE INDEX IF NOT EXISTS runs_strategy_symbol ON runs(strategy, symbol);
+`
+
+// ResultsStore ฐานข้อมูล SQLite เก็บประวัติผล backtest (config, fingerprint ของข้อมูล, ตัวชี้วัด และเทรด)
+type ResultsStore struct {
+	db *sql.DB
+}
+
+// StoredRun สรุปการรันหนึ่งครั้งใน results store
+type StoredRun struct {
+	ID              int64     `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	Strategy        string    `json:"strategy"`
+	Symbol          string    `json:"symbol"`
+	Timeframe       string    `json:"timeframe"`
+	DataFingerprint string    `json:"data_fingerprint"` // sha256 ของแท่งเทียนที่ใช้ (16 ตัวอักษรแรก)
+	Bars            int       `json:"bars"`
+	Note            string    `json:"note"`
+
+	// Result ผลลัพธ์ที่บันทึก (ไม่รวม equity curve, ผลตอบแทนรายวัน และ orders; Trades โหลดเฉพาะ LoadRun)
+	Result *BacktestResult `json:"result"`
+}
+
+// RunFilter เงื่อนไขการค้นหาการรัน (ค่าว่าง = ไม่กรอง)
+type RunFilter struct {
+	Strategy string
+	Symbol   string
+	Limit    int // 0 = ทั้งหมด
+}
+
+// OpenResultsStore เปิด (หรือสร้าง) results store ที่ไฟล์ SQLite
+func OpenResultsStore(filename string) (*ResultsStore, error) {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถเปิด results store %s: %v", filename, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ไม่สามารถเปิด results store %s: %v", filename, err)
+	}
+	if _, err := db.Exec(resultsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ไม่สามารถสร้างตารางใน %s: %v", filename, err)
+	}
+	return &ResultsStore{db: db}, nil
+}
+
+// Close ปิดฐานข้อมูล
+func (s *ResultsStore) Close() error {
+	return s.db.Close()
+}
+
+// DataFingerprint sha256 ของแท่งเทียน (16 ตัวอักษรแรก) ใช้ตรวจว่าสองการรันใช้ข้อมูลชุดเดียวกันหรือไม่
+func DataFingerprint(data []OHLCV) string {
+	hash := sha256.New()
+	var buf [48]byte
+	for _, candle := range data {
+		binary.LittleEndian.PutUint64(buf[0:], uint64(candle.Timestamp))
+		binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(candle.Open))
+		binary.LittleEndian.PutUint64(buf[16:], math.Float64bits(candle.High))
+		binary.LittleEndian.PutUint64(buf[24:], math.Float64bits(candle.Low))
+		binary.LittleEndian.PutUint64(buf[32:], math.Float64bits(candle.Close))
+		binary.LittleEndian.PutUint64(buf[40:], math.Float64bits(candle.Volume))
+		hash.Write(buf[:])
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// SaveRun บันทึกผลการรันพร้อม fingerprint ของข้อมูลที่ใช้ คืน id ของการรัน
+// ผลลัพธ์ต้องมี config ที่ระบุกลยุทธ์ (แนบโดย RunStrategy) ส่วน timeframe ที่ว่างใช้ระยะห่างของแท่งใน data
+func (s *ResultsStore) SaveRun(result *BacktestResult, data []OHLCV, note string) (int64, error) {
+	if result.Config == nil || result.Config.Strategy == "" {
+		return 0, fmt.Errorf("ผลลัพธ์ของ %s ไม่ระบุกลยุทธ์ใน config จึงบันทึกลง results store ไม่ได้", result.Symbol)
+	}
+	config := *result.Config
+	if config.Timeframe == "" {
+		config.Timeframe = formatInterval(dataInterval(data))
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return 0, fmt.Errorf("ไม่สามารถแปลง config เป็น JSON: %v", err)
+	}
+	metricsJSON, err := json.Marshal(result.Metrics)
+	if err != nil {
+		return 0, fmt.Errorf("ไม่สามารถแปลงตัวชี้วัดเป็น JSON: %v", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT INTO runs (
+		created_at, strategy, symbol, timeframe, start_date, end_date, data_fingerprint, bars,
+		initial_capital, final_capital, total_return_pct, total_trades, winning_trades, losing_trades,
+		win_rate, max_drawdown, max_drawdown_pct, total_funding, metrics, config, note
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC().Format(time.RFC3339), config.Strategy, result.Symbol, config.Timeframe,
+		result.StartDate.UTC().Format(time.RFC3339), result.EndDate.UTC().Format(time.RFC3339),
+		DataFingerprint(data), len(data),
+		result.InitialCapital, result.FinalCapital, result.TotalReturnPct, result.TotalTrades,
+		result.WinningTrades, result.LosingTrades, result.WinRate, result.MaxDrawdown, result.MaxDrawdownPct,
+		result.TotalFunding, string(metricsJSON), string(configJSON), note)
+	if err != nil {
+		return 0, fmt.Errorf("ไม่สามารถบันทึกการรัน: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO trades (run_id, seq, side, entry_time, exit_time, net_pnl, exit_reason, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for i, trade := range result.Trades {
+		tradeJSON, err := json.Marshal(trade)
+		if err != nil {
+			return 0, fmt.Errorf("ไม่สามารถแปลงเทรดเป็น JSON: %v", err)
+		}
+		if _, err := stmt.Exec(id, i, trade.Side, trade.EntryTime.UTC().Format(time.RFC3339),
+			trade.ExitTime.UTC().Format(time.RFC3339), trade.NetPnL, trade.ExitReason, string(tradeJSON)); err != nil {
+			return 0, fmt.Errorf("ไม่สามารถบันทึกเทรด: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ไม่สามารถบันทึกการรัน: %v", err)
+	}
+	return id, nil
+}
+
+// runColumns คอลัมน์ของ runs ที่ scanRun อ่าน
+const runColumns = `id, created_at, strategy, symbol, timeframe, start_date, end_date, data_fingerprint, bars,
+	initial_capital, final_capital, total_return_pct, total_trades, winning_trades, losing_trades,
+	win_rate, max_drawdown, max_drawdown_pct, total_funding, metrics, config, note`
+
+// scanRun อ่านแถวของ runs เป็น StoredRun
+func scanRun(row interface{ Scan(...any) error }) (*StoredRun, error) {
+	run := &StoredRun{Result: &BacktestResult{}}
+	r := run.Result
+	var createdAt, startDate, endDate, metricsJSON, configJSON string
+	err := row.Scan(&run.ID, &createdAt, &run.Strategy, &run.Symbol, &run.Timeframe, &startDate, &endDate,
+		&run.DataFingerprint, &run.Bars, &r.InitialCapital, &r.FinalCapital, &r.TotalReturnPct, &r.TotalTrades,
+		&r.WinningTrades, &r.LosingTrades, &r.WinRate, &r.MaxDrawdown, &r.MaxDrawdownPct, &r.TotalFunding,
+		&metricsJSON, &configJSON, &run.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	run.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	r.Symbol = run.Symbol
+	r.StartDate, _ = time.Parse(time.RFC3339, startDate)
+	r.EndDate, _ = time.Parse(time.RFC3339, endDate)
+	r.TotalReturn = r.FinalCapital - r.InitialCapital
+	if err := json.Unmarshal([]byte(metricsJSON), &r.Metrics); err != nil {
+		return nil, fmt.Errorf("ตัวชี้วัดของการรัน #%d เสียหาย: %v", run.ID, err)
+	}
+	r.Config = &StrategyConfig{}
+	if err := json.Unmarshal([]byte(configJSON), r.Config); err != nil {
+		return nil, fmt.Errorf("config ของการรัน #%d เสียหาย: %v", run.ID, err)
+	}
+	return run, nil
+}
+
+// ListRuns รายการการรันล่าสุดก่อน (ไม่โหลดเทรด)
+func (s *ResultsStore) ListRuns(filter RunFilter) ([]*StoredRun, error) {
+	query := "SELECT " + runColumns + " FROM runs"
+	var where []string
+	var args []any
+	if filter.Strategy != "" {
+		where = append(where, "strategy = ?")
+		args = append(args, filter.Strategy)
+	}
+	if filter.Symbol != "" {
+		where = append(where, "symbol = ?")
+		args = append(args, filter.Symbol)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถอ่านรายการการรัน: %v", err)
+	}
+	defer rows.Close()
+
+	var runs []*StoredRun
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// LoadRun โหลดการรันพร้อมเทรดทั้งหมด
+func (s *ResultsStore) LoadRun(id int64) (*StoredRun, error) {
+	run, err := scanRun(s.db.QueryRow("SELECT "+runColumns+" FROM runs WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("ไม่พบการรัน #%d", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query("SELECT data FROM trades WHERE run_id = ? ORDER BY seq", id)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถอ่านเทรดของการรัน #%d: %v", id, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var trade BacktestTrade
+		if err := json.Unmarshal([]byte(data), &trade); err != nil {
+			return nil, fmt.Errorf("เทรดของการรัน #%d เสียหาย: %v", id, err)
+		}
+		run.Result.Trades = append(run.Result.Trades, trade)
+	}
+	return run, rows.Err()
+}
+
+// DeleteRun ลบการรันและเทรดของการรันนั้น
+func (s *ResultsStore) DeleteRun(id int64) error {
+	res, err := s.db.Exec("DELETE FROM runs WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถลบการรัน #%d: %v", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("ไม่พบการรัน #%d", id)
+	}
+	return nil
+}
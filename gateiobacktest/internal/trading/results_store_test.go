@@ -0,0 +1,104 @@
+package trading
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// storedPivotRun รัน pivot-supertrend ด้วย config ที่กำหนดบนข้อมูลสังเคราะห์ 15m
+func storedPivotRun(t *testing.T, data []OHLCV, atrFactor float64) *BacktestResult {
+	t.Helper()
+	bt := newQuietBacktester(t, "SOL_USDT", 0)
+	bt.LoadHistoricalData(data)
+
+	config := DefaultStrategyConfig()
+	config.Timeframe = ""
+	config.Params.ATRFactor = atrFactor
+	if err := bt.ApplyConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	result, err := bt.RunStrategy(NewPivotSuperTrendStrategy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestResultsStoreSaveLoadDiff(t *testing.T) {
+	store, err := OpenResultsStore(filepath.Join(t.TempDir(), "results.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	data := syntheticCandles(800, 21)
+	base, tuned := storedPivotRun(t, data, 3), storedPivotRun(t, data, 2)
+	if len(base.Trades) == 0 {
+		t.Fatal("ข้อมูลสังเคราะห์ต้องมีเทรด")
+	}
+
+	idA, err := store.SaveRun(base, data, "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	idB, err := store.SaveRun(tuned, data, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := store.LoadRun(idA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Strategy != "pivot-supertrend" || a.Timeframe != "15m" || a.Symbol != "SOL_USDT" || a.Note != "base" ||
+		a.Bars != len(data) || a.DataFingerprint != DataFingerprint(data) {
+		t.Fatalf("ข้อมูลการรันที่โหลด: %+v", a)
+	}
+	// วันที่เริ่ม/จบคือช่วงที่เทรดจริง (หลัง warmup) ไม่ใช่ช่วงที่ขอโหลด
+	if !a.Result.StartDate.Equal(base.StartDate) || !a.Result.EndDate.Equal(base.EndDate) ||
+		!a.Result.EndDate.Equal(base.EquityCurve[len(base.EquityCurve)-1].Time) {
+		t.Fatalf("ช่วงวันที่ %v → %v, ต้องการ %v → %v", a.Result.StartDate, a.Result.EndDate, base.StartDate, base.EndDate)
+	}
+	if a.Result.FinalCapital != base.FinalCapital || a.Result.TotalTrades != base.TotalTrades ||
+		!reflect.DeepEqual(a.Result.Metrics, base.Metrics) || len(a.Result.Trades) != len(base.Trades) {
+		t.Fatalf("ผลลัพธ์ที่โหลดไม่ตรงกับที่บันทึก")
+	}
+
+	// การรันเดียวกันต้องไม่ต่างกันเลย
+	same := DiffRuns(a, a)
+	if len(same.ConfigChanges) != 0 || len(same.Trades) != 0 || same.MatchedTrades != len(base.Trades) {
+		t.Fatalf("diff กับตัวเอง: config %v เทรดต่าง %d ตรงกัน %d", same.ConfigChanges, len(same.Trades), same.MatchedTrades)
+	}
+
+	b, err := store.LoadRun(idB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := DiffRuns(a, b)
+	if !diff.SameData || !reflect.DeepEqual(diff.ConfigChanges, []string{"params.atr_factor: 3 → 2"}) {
+		t.Fatalf("SameData %v ConfigChanges %v", diff.SameData, diff.ConfigChanges)
+	}
+	if diff.MatchedTrades+len(diff.Trades) < len(base.Trades) {
+		t.Fatalf("เทรดที่ตรง %d + ต่าง %d น้อยกว่าจำนวนเทรดของ A (%d)", diff.MatchedTrades, len(diff.Trades), len(base.Trades))
+	}
+
+	runs, err := store.ListRuns(RunFilter{Strategy: "pivot-supertrend"})
+	if err != nil || len(runs) != 2 || runs[0].ID != idB {
+		t.Fatalf("ListRuns: %d รายการ (%v), ต้องการ 2 ล่าสุดก่อน", len(runs), err)
+	}
+}
+
+func TestResultsStoreRejectsResultWithoutStrategy(t *testing.T) {
+	store, err := OpenResultsStore(filepath.Join(t.TempDir(), "results.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	_, err = store.SaveRun(&BacktestResult{Symbol: "SOL_USDT"}, syntheticCandles(10, 1), "")
+	if err == nil || !strings.Contains(err.Error(), "ไม่ระบุกลยุทธ์") {
+		t.Fatalf("SaveRun คืน %v, ต้องการ error เมื่อไม่มี config", err)
+	}
+}
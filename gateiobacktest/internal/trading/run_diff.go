@@ -0,0 +1,301 @@
+package trading
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MetricDiff ค่าตัวชี้วัดหนึ่งตัวของสองการรัน
+type MetricDiff struct {
+	Name  string  `json:"name"`
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	Delta float64 `json:"delta"` // B - A
+}
+
+// TradeDiffKind ชนิดของความต่างระหว่างเทรด
+type TradeDiffKind string
+
+const (
+	TradeChanged TradeDiffKind = "changed" // เข้าเทรดเดียวกันแต่ผลการปิดต่างกัน
+	TradeOnlyA   TradeDiffKind = "only_a"  // มีเฉพาะในการรัน A
+	TradeOnlyB   TradeDiffKind = "only_b"  // มีเฉพาะในการรัน B
+)
+
+// TradeDiff เทรดที่ต่างกันระหว่างสองการรัน (จับคู่ด้วยเวลาเข้า ทิศ และลำดับการปิด)
+type TradeDiff struct {
+	Kind    TradeDiffKind  `json:"kind"`
+	A       *BacktestTrade `json:"a,omitempty"`
+	B       *BacktestTrade `json:"b,omitempty"`
+	Changes []string       `json:"changes,omitempty"` // field ที่ต่างกันเมื่อ Kind = changed
+}
+
+// RunDiff ผลเปรียบเทียบสองการรันแบบตัวชี้วัดต่อตัวชี้วัดและเทรดต่อเทรด
+type RunDiff struct {
+	A, B          *StoredRun
+	SameData      bool         // ใช้ข้อมูลราคาชุดเดียวกัน (fingerprint ตรงกัน)
+	ConfigChanges []string     // "params.atr_factor: 3 → 2.5"
+	Metrics       []MetricDiff // ตัวชี้วัดทั้งหมดตามลำดับ
+	Trades        []TradeDiff  // เฉพาะเทรดที่ต่างกัน เรียงตามเวลาเข้า
+	MatchedTrades int          // จำนวนเทรดที่เหมือนกันทุก field ที่เปรียบเทียบ
+}
+
+// DiffRuns เปรียบเทียบการรัน a (ก่อน) กับ b (หลัง) ที่โหลดด้วย LoadRun
+func DiffRuns(a, b *StoredRun) *RunDiff {
+	diff := &RunDiff{
+		A:        a,
+		B:        b,
+		SameData: a.DataFingerprint == b.DataFingerprint,
+	}
+	diff.ConfigChanges = diffConfigs(a.Result.Config, b.Result.Config)
+
+	metricsA, metricsB := resultMetrics(a.Result), resultMetrics(b.Result)
+	for i, m := range metricsA {
+		diff.Metrics = append(diff.Metrics, MetricDiff{
+			Name:  m.name,
+			A:     m.value,
+			B:     metricsB[i].value,
+			Delta: metricsB[i].value - m.value,
+		})
+	}
+
+	diff.Trades, diff.MatchedTrades = diffTrades(a.Result.Trades, b.Result.Trades)
+	return diff
+}
+
+// namedMetric ตัวชี้วัดพร้อมชื่อสำหรับแสดงผล
+type namedMetric struct {
+	name  string
+	value float64
+}
+
+// resultMetrics ตัวชี้วัดของผลลัพธ์ที่ใช้เปรียบเทียบ (ลำดับคงที่)
+func resultMetrics(r *BacktestResult) []namedMetric {
+	m := r.Metrics
+	return []namedMetric{
+		{"Return %", r.TotalReturnPct},
+		{"Final Capital", r.FinalCapital},
+		{"Trades", float64(r.TotalTrades)},
+		{"Win Rate %", r.WinRate},
+		{"Max Drawdown %", r.MaxDrawdownPct},
+		{"Funding", r.TotalFunding},
+		{"Sharpe", m.SharpeRatio},
+		{"Sortino", m.SortinoRatio},
+		{"Calmar", m.CalmarRatio},
+		{"Annual Return %", m.AnnualReturnPct},
+		{"Profit Factor", m.ProfitFactor},
+		{"Expectancy", m.Expectancy},
+		{"Avg Win", m.AvgWin},
+		{"Avg Loss", m.AvgLoss},
+		{"Payoff", m.PayoffRatio},
+		{"Max Consec. Wins", float64(m.MaxConsecutiveWins)},
+		{"Max Consec. Losses", float64(m.MaxConsecutiveLosses)},
+		{"Exposure %", m.ExposurePct},
+		{"Avg Hold (h)", m.AvgDuration.Hours()},
+		{"Avg MAE %", m.AvgMAEPct},
+		{"Avg MFE %", m.AvgMFEPct},
+	}
+}
+
+// diffConfigs field ของ config ที่ต่างกัน (เทียบแบบ flatten จาก JSON)
+func diffConfigs(a, b *StrategyConfig) []string {
+	flatA, flatB := flattenConfig(a), flattenConfig(b)
+
+	keys := make(map[string]bool)
+	for key := range flatA {
+		keys[key] = true
+	}
+	for key := range flatB {
+		keys[key] = true
+	}
+
+	var changes []string
+	for key := range keys {
+		valueA, okA := flatA[key]
+		valueB, okB := flatB[key]
+		if !okA {
+			valueA = "-"
+		}
+		if !okB {
+			valueB = "-"
+		}
+		if valueA != valueB {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", key, valueA, valueB))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// flattenConfig แปลง config เป็น map ของ "a.b.c" → ค่า
+func flattenConfig(config *StrategyConfig) map[string]string {
+	flat := make(map[string]string)
+	if config == nil {
+		return flat
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return flat
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return flat
+	}
+
+	var walk func(prefix string, value any)
+	walk = func(prefix string, value any) {
+		if children, ok := value.(map[string]any); ok {
+			for key, child := range children {
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				walk(key, child)
+			}
+			return
+		}
+		encoded, _ := json.Marshal(value)
+		flat[prefix] = string(encoded)
+	}
+	walk("", tree)
+	return flat
+}
+
+// tradeKey กุญแจจับคู่เทรดระหว่างสองการรัน
+func tradeKey(trade BacktestTrade) string {
+	return fmt.Sprintf("%d|%s|%d", trade.EntryTime.Unix(), trade.Side, trade.ExitLeg)
+}
+
+// diffTrades จับคู่เทรดด้วยเวลาเข้า ทิศ และลำดับการปิด แล้วคืนเฉพาะเทรดที่ต่างกัน
+func diffTrades(a, b []BacktestTrade) ([]TradeDiff, int) {
+	pending := make(map[string][]int)
+	for i, trade := range b {
+		key := tradeKey(trade)
+		pending[key] = append(pending[key], i)
+	}
+
+	var diffs []TradeDiff
+	matched := 0
+	usedB := make([]bool, len(b))
+	for i := range a {
+		key := tradeKey(a[i])
+		candidates := pending[key]
+		if len(candidates) == 0 {
+			diffs = append(diffs, TradeDiff{Kind: TradeOnlyA, A: &a[i]})
+			continue
+		}
+		j := candidates[0]
+		pending[key] = candidates[1:]
+		usedB[j] = true
+
+		if changes := compareTrades(a[i], b[j]); len(changes) > 0 {
+			diffs = append(diffs, TradeDiff{Kind: TradeChanged, A: &a[i], B: &b[j], Changes: changes})
+		} else {
+			matched++
+		}
+	}
+	for j := range b {
+		if !usedB[j] {
+			diffs = append(diffs, TradeDiff{Kind: TradeOnlyB, B: &b[j]})
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].entryTime().Before(diffs[j].entryTime())
+	})
+	return diffs, matched
+}
+
+// entryTime เวลาเข้าเทรดของ diff
+func (d TradeDiff) entryTime() time.Time {
+	if d.A != nil {
+		return d.A.EntryTime
+	}
+	return d.B.EntryTime
+}
+
+// compareTrades field ที่ต่างกันของเทรดที่จับคู่แล้ว
+func compareTrades(a, b BacktestTrade) []string {
+	var changes []string
+	differs := func(x, y float64) bool {
+		return math.Abs(x-y) > 1e-9*math.Max(1, math.Max(math.Abs(x), math.Abs(y)))
+	}
+
+	if differs(a.EntryPrice, b.EntryPrice) {
+		changes = append(changes, fmt.Sprintf("entry %.4f → %.4f", a.EntryPrice, b.EntryPrice))
+	}
+	if differs(a.Quantity, b.Quantity) {
+		changes = append(changes, fmt.Sprintf("qty %.6f → %.6f", a.Quantity, b.Quantity))
+	}
+	if !a.ExitTime.Equal(b.ExitTime) {
+		changes = append(changes, fmt.Sprintf("exit %s → %s", a.ExitTime.UTC().Format("2006-01-02 15:04"), b.ExitTime.UTC().Format("2006-01-02 15:04")))
+	}
+	if differs(a.ExitPrice, b.ExitPrice) {
+		changes = append(changes, fmt.Sprintf("exit price %.4f → %.4f", a.ExitPrice, b.ExitPrice))
+	}
+	if a.ExitReason != b.ExitReason {
+		changes = append(changes, fmt.Sprintf("reason %q → %q", a.ExitReason, b.ExitReason))
+	}
+	if differs(a.NetPnL, b.NetPnL) {
+		changes = append(changes, fmt.Sprintf("net PnL %.2f → %.2f", a.NetPnL, b.NetPnL))
+	}
+	return changes
+}
+
+// Print แสดงผลเปรียบเทียบ (maxTrades = จำนวนเทรดที่ต่างกันที่แสดง, 0 = ทั้งหมด)
+func (d *RunDiff) Print(maxTrades int) {
+	fmt.Printf("\n🔀 ===== เปรียบเทียบการรัน #%d → #%d =====\n", d.A.ID, d.B.ID)
+	fmt.Printf("A: #%d %s %s %s (%s)\n", d.A.ID, d.A.Strategy, d.A.Symbol, d.A.Timeframe, d.A.CreatedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("B: #%d %s %s %s (%s)\n", d.B.ID, d.B.Strategy, d.B.Symbol, d.B.Timeframe, d.B.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if d.SameData {
+		fmt.Printf("📊 ข้อมูลราคาชุดเดียวกัน (%s, %d แท่ง)\n", d.A.DataFingerprint, d.A.Bars)
+	} else {
+		fmt.Printf("⚠️ ข้อมูลราคาต่างกัน: %s (%d แท่ง) → %s (%d แท่ง)\n",
+			d.A.DataFingerprint, d.A.Bars, d.B.DataFingerprint, d.B.Bars)
+	}
+
+	if len(d.ConfigChanges) == 0 {
+		fmt.Println("⚙️ config เหมือนกัน")
+	} else {
+		fmt.Println("⚙️ config ที่ต่างกัน:")
+		for _, change := range d.ConfigChanges {
+			fmt.Printf("   %s\n", change)
+		}
+	}
+
+	fmt.Printf("\n%-20s %14s %14s %14s\n", "Metric", "A", "B", "Δ")
+	for _, m := range d.Metrics {
+		marker := ""
+		if math.Abs(m.Delta) > 1e-9 {
+			marker = " *"
+		}
+		fmt.Printf("%-20s %14.4f %14.4f %+14.4f%s\n", m.Name, m.A, m.B, m.Delta, marker)
+	}
+
+	counts := map[TradeDiffKind]int{}
+	for _, trade := range d.Trades {
+		counts[trade.Kind]++
+	}
+	fmt.Printf("\n📋 เทรด: เหมือนกัน %d | เปลี่ยน %d | เฉพาะ A %d | เฉพาะ B %d\n",
+		d.MatchedTrades, counts[TradeChanged], counts[TradeOnlyA], counts[TradeOnlyB])
+
+	for i, trade := range d.Trades {
+		if maxTrades > 0 && i >= maxTrades {
+			fmt.Printf("   ... อีก %d รายการ\n", len(d.Trades)-maxTrades)
+			break
+		}
+		switch trade.Kind {
+		case TradeChanged:
+			fmt.Printf("   ~ %s %-5s %s\n", trade.A.EntryTime.UTC().Format("2006-01-02 15:04"), trade.A.Side, strings.Join(trade.Changes, ", "))
+		case TradeOnlyA:
+			fmt.Printf("   - %s %-5s @ %.4f → %.4f (%s) net %.2f\n", trade.A.EntryTime.UTC().Format("2006-01-02 15:04"),
+				trade.A.Side, trade.A.EntryPrice, trade.A.ExitPrice, trade.A.ExitReason, trade.A.NetPnL)
+		case TradeOnlyB:
+			fmt.Printf("   + %s %-5s @ %.4f → %.4f (%s) net %.2f\n", trade.B.EntryTime.UTC().Format("2006-01-02 15:04"),
+				trade.B.Side, trade.B.EntryPrice, trade.B.ExitPrice, trade.B.ExitReason, trade.B.NetPnL)
+		}
+	}
+}
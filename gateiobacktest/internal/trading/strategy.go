@@ -0,0 +1,261 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Strategy กลยุทธ์ที่เสียบเข้ากับ event loop กลางของ Backtester
+//
+// ลำดับการเรียกในแต่ละแท่งเทียน: funding → liquidation → ทยอยปิดตามแผนออก (scale-out) →
+// fill SL/TP ระหว่างแท่ง → จับคู่ pending order → MAE/MFE และเลื่อน SL ตามแผนออก → OnBar →
+// ExitSignal (ถ้ามี position) → pyramiding → EntrySignal + PositionSize (ถ้าไม่มี position)
+type Strategy interface {
+	// Name ชื่อกลยุทธ์สำหรับแสดงผล
+	Name() string
+	// WarmupBars จำนวนแท่งเทียนที่ต้องข้ามก่อนเริ่มเทรด (index เริ่มต้นของ loop)
+	WarmupBars() int
+	// Fees โมเดลค่าธรรมเนียมของกลยุทธ์
+	Fees(bt *Backtester) FeeModel
+	// OnBar เรียกทุกแท่งเทียนเพื่ออัปเดต indicators/state ของกลยุทธ์
+	OnBar(bt *Backtester)
+	// ExitSignal คืนเหตุผลการปิด position ("" = ถือต่อ) และปรับ SL/TP ได้
+	ExitSignal(bt *Backtester, pos *BacktestPosition) string
+	// EntrySignal คืนสัญญาณเข้า position (nil = ไม่เข้า)
+	EntrySignal(bt *Backtester) *EntrySignal
+	// PositionSize คำนวณขนาด position (0 = ยกเลิกการเข้า)
+	PositionSize(bt *Backtester, signal *EntrySignal) float64
+}
+
+// EntrySignal สัญญาณเข้า position จากกลยุทธ์
+type EntrySignal struct {
+	Side       string  // "LONG" or "SHORT"
+	StopLoss   float64 // ราคา Stop Loss
+	TakeProfit float64 // ราคา Take Profit
+	Leverage   float64 // leverage ของเทรดนี้ (0 = ใช้ค่าของบัญชี futures)
+	Reason     string  // เหตุผลการเข้า
+}
+
+// FeeModel โมเดลค่าธรรมเนียมของการเทรด
+type FeeModel struct {
+	EntryRate float64 `json:"entry_rate" yaml:"entry_rate"` // อัตราค่าธรรมเนียมขาเข้า (คิดจาก notional ตอนเปิด)
+	ExitRate  float64 `json:"exit_rate" yaml:"exit_rate"`   // อัตราค่าธรรมเนียมขาออก (คิดจาก notional ตอนปิด)
+
+	// ChargeEntryOnOpen หักค่าธรรมเนียมขาเข้าออกจากเงินทุนทันทีตอนเปิด position
+	// (Commission ของเทรดยังรวมขาเข้า แต่ตอนปิดหักจากเงินทุนเฉพาะขาออก)
+	ChargeEntryOnOpen bool `json:"charge_entry_on_open,omitempty" yaml:"charge_entry_on_open,omitempty"`
+}
+
+// EndOfDataExit กลยุทธ์ที่กำหนดเหตุผลการปิด position ตอนข้อมูลหมดเอง (ค่าเริ่มต้น END_OF_BACKTEST)
+type EndOfDataExit interface {
+	EndOfDataReason() string
+}
+
+// endOfDataReason เหตุผลการปิด position ที่ค้างอยู่ตอนจบ backtest
+func endOfDataReason(strategy Strategy) string {
+	if s, ok := strategy.(EndOfDataExit); ok {
+		return s.EndOfDataReason()
+	}
+	return "END_OF_BACKTEST"
+}
+
+// RunStrategy รัน backtest ด้วยกลยุทธ์ที่กำหนดผ่าน event loop เดียว
+func (bt *Backtester) RunStrategy(strategy Strategy) (*BacktestResult, error) {
+	bt.logf("🚀 เริ่มต้น %s สำหรับ %s\n", strategy.Name(), bt.symbol)
+	bt.logf("💰 เงินทุนเริ่มต้น: $%.2f\n", bt.initialCapital)
+
+	if len(bt.ohlcvData) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาสำหรับ backtest")
+	}
+
+	bt.fees = strategy.Fees(bt)
+	bt.strategyName = strategy.Name()
+	bt.haltErr = nil
+	bt.stream = nil
+
+	bt.resetEquity()
+
+	start, end := bt.tradingRange(strategy.WarmupBars())
+	for bt.currentIndex = start; bt.currentIndex < end; bt.currentIndex++ {
+		bt.stepBar(strategy)
+		if bt.haltErr != nil {
+			return nil, bt.haltErr
+		}
+
+		// equity แบบ mark-to-market ทุกแท่ง (รวม PnL ที่ยังไม่ปิด) สำหรับ drawdown และผลตอบแทนรายวัน
+		bt.recordEquity()
+	}
+
+	// ปิด position ถ้ามี (ตอนจบ backtest)
+	if bt.position != nil {
+		bt.closePosition(endOfDataReason(strategy))
+	}
+	bt.cancelOpenOrders("END_OF_BACKTEST")
+	bt.finishEquity()
+
+	return bt.calculateResults(), nil
+}
+
+// tradingRange ช่วง index ที่เทรด [start, end) ไม่น้อยกว่า warmup และอยู่ในช่วงวันที่ของ LoadOHLCVData
+func (bt *Backtester) tradingRange(warmup int) (start, end int) {
+	start, end = warmup, len(bt.ohlcvData)
+	if !bt.windowed {
+		return start, end
+	}
+
+	from, to := bt.startDate.Unix(), bt.endDate.Unix()
+	first := sort.Search(len(bt.ohlcvData), func(i int) bool {
+		return bt.ohlcvData[i].Timestamp >= from
+	})
+	if first > start {
+		start = first
+	}
+	end = sort.Search(len(bt.ohlcvData), func(i int) bool {
+		return bt.ohlcvData[i].Timestamp > to
+	})
+	return start, end
+}
+
+// stepBar ประมวลผลแท่งเทียน bt.currentIndex หนึ่งแท่งตามลำดับของ Strategy
+func (bt *Backtester) stepBar(strategy Strategy) {
+	candle := bt.ohlcvData[bt.currentIndex]
+	bt.currentTime = time.Unix(candle.Timestamp, 0)
+	bt.currentPrice = candle.Close
+
+	// เก็บ funding ของรอบที่ผ่านไประหว่างแท่งก่อนหน้ากับแท่งนี้
+	bt.settleFunding(candle)
+
+	// Liquidation, ทยอยปิดตามแผนออก และ fill SL/TP ระหว่างแท่งจาก High/Low ก่อนให้กลยุทธ์ตัดสินที่ราคาปิด
+	// แล้วจับคู่ pending order ตามเส้นทางราคาของแท่ง
+	if !bt.checkLiquidation(candle) {
+		bt.scaleOut(candle)
+		bt.checkIntrabarFill(candle)
+		bt.processOrders(candle)
+	}
+
+	// position ที่ยังเปิดอยู่ผ่านทั้งแท่ง: บันทึก MAE/MFE และนับ exposure
+	bt.barsTotal++
+	if bt.position != nil {
+		bt.barsInMarket++
+		bt.trackExcursion(candle.High, candle.Low)
+		bt.trailExitPlan(candle)
+	}
+
+	strategy.OnBar(bt)
+
+	// ตรวจสอบ position ที่เปิดอยู่
+	if bt.position != nil {
+		if reason := strategy.ExitSignal(bt, bt.position); reason != "" {
+			bt.closePosition(reason)
+		}
+	}
+
+	// เพิ่มไม้ (pyramiding) ถ้า position ยังเปิดอยู่และกำไรถึงเกณฑ์
+	bt.checkPyramid()
+
+	// หาโอกาสเทรดใหม่ (ถ้าไม่มี position)
+	if bt.position == nil {
+		if signal := strategy.EntrySignal(bt); signal != nil {
+			quantity := bt.applyEntryLimit(signal, strategy.PositionSize(bt, signal), bt.currentPrice)
+			if quantity > 0 {
+				bt.openPosition(signal, quantity)
+			}
+		}
+	}
+}
+
+// halt หยุด backtest หลังจบแท่งปัจจุบัน และให้ RunStrategy คืน err
+func (bt *Backtester) halt(err error) {
+	if bt.haltErr == nil {
+		bt.haltErr = err
+	}
+}
+
+// runStrategyResult รันกลยุทธ์และคืนผลลัพธ์เสมอ (สำหรับ runner ที่ไม่คืน error)
+func (bt *Backtester) runStrategyResult(strategy Strategy) *BacktestResult {
+	result, err := bt.RunStrategy(strategy)
+	if err != nil {
+		bt.logf("❌ %v\n", err)
+		return bt.calculateResults()
+	}
+	return result
+}
+
+// leveragedQuantity ขนาด position แบบ futures: เสี่ยง riskPct ของเงินทุนคูณ leverage
+// และจำกัด notional ไม่เกิน maxCapitalPct ของเงินทุนคูณ leverage
+func (bt *Backtester) leveragedQuantity(signal *EntrySignal, riskPct, leverage, maxCapitalPct float64) float64 {
+	riskAmount := bt.currentCapital * riskPct
+
+	var positionSize float64
+	if signal.Side == "LONG" {
+		positionSize = (riskAmount * leverage) / (bt.currentPrice - signal.StopLoss)
+	} else {
+		positionSize = (riskAmount * leverage) / (signal.StopLoss - bt.currentPrice)
+	}
+
+	maxValue := bt.currentCapital * maxCapitalPct * leverage
+	if positionSize*bt.currentPrice > maxValue {
+		positionSize = maxValue / bt.currentPrice
+	}
+
+	return positionSize
+}
+
+// atrStops คำนวณ Stop Loss / Take Profit จาก ATR x multiplier และ Risk:Reward
+func atrStops(side string, price, atr, atrMultiplier, riskReward float64) (stopLoss, takeProfit float64) {
+	if side == "LONG" {
+		stopLoss = price - (atr * atrMultiplier)
+		takeProfit = price + (atr * atrMultiplier * riskReward)
+	} else {
+		stopLoss = price + (atr * atrMultiplier)
+		takeProfit = price - (atr * atrMultiplier * riskReward)
+	}
+	return stopLoss, takeProfit
+}
+
+// Symbol คืนชื่อเหรียญที่กำลัง backtest
+func (bt *Backtester) Symbol() string {
+	return bt.symbol
+}
+
+// CurrentIndex คืน index ของแท่งเทียนปัจจุบัน
+func (bt *Backtester) CurrentIndex() int {
+	return bt.currentIndex
+}
+
+// CurrentTime คืนเวลาของแท่งเทียนปัจจุบัน
+func (bt *Backtester) CurrentTime() time.Time {
+	return bt.currentTime
+}
+
+// CurrentPrice คืนราคาปิดของแท่งเทียนปัจจุบัน
+func (bt *Backtester) CurrentPrice() float64 {
+	return bt.currentPrice
+}
+
+// Capital คืนเงินทุนปัจจุบัน
+func (bt *Backtester) Capital() float64 {
+	return bt.currentCapital
+}
+
+// Position คืน position ที่เปิดอยู่ (nil = ไม่มี)
+func (bt *Backtester) Position() *BacktestPosition {
+	return bt.position
+}
+
+// UnrealizedPnL กำไร/ขาดทุนที่ยังไม่ปิดของ position ที่ราคาปัจจุบัน (0 = ไม่มี position)
+func (bt *Backtester) UnrealizedPnL() float64 {
+	if bt.position == nil {
+		return 0
+	}
+	if bt.position.Side == "LONG" {
+		return (bt.currentPrice - bt.position.EntryPrice) * bt.position.Quantity
+	}
+	return (bt.position.EntryPrice - bt.currentPrice) * bt.position.Quantity
+}
+
+// Candles คืนแท่งเทียนตั้งแต่ต้นจนถึงแท่งปัจจุบัน (ไม่มีข้อมูลอนาคต)
+func (bt *Backtester) Candles() []OHLCV {
+	return bt.ohlcvData[:bt.currentIndex+1]
+}
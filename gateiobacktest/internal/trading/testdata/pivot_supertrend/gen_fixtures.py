@@ -0,0 +1,135 @@
+#!/usr/bin/env python3
+"""สร้าง fixture ของ TestPivotSuperTrendMatchesFixtureModel (pivot_supertrend_model_test.go) ใหม่
+
+คำนวณทีละแท่งตาม pivot_supertrend.pine (na = None) โดยไม่ขึ้นกับโค้ด Go
+ค่าเป็นของโมเดลนี้ที่อ่านจากสคริปต์เอง ไม่ได้รันหรือ export จาก TradingView
+คอลัมน์ตรงกับการ Export chart data ของสคริปต์นั้น ถ้าจะเทียบกับ TradingView จริง
+ให้ export จากกราฟแล้วเพิ่มเป็น fixture แยก
+
+ta.pivothigh/ta.pivotlow: แท่งกลางต้องสูง/ต่ำกว่าทุกแท่งในหน้าต่าง prd แท่งซ้ายขวาแบบเข้มงวด
+และค่าจะปรากฏที่แท่ง prd แท่งหลัง pivot (ตอนที่ยืนยันได้)
+
+    python3 gen_fixtures.py
+"""
+
+import random
+
+# (ชื่อไฟล์, seed, prd, factor, atr period)
+FIXTURES = [
+    ("prd2_factor3_atr10.csv", 2401, 2, 3.0, 10),
+    ("prd3_factor2.5_atr14.csv", 2402, 3, 2.5, 14),
+    ("prd1_factor2_atr5.csv", 2403, 1, 2.0, 5),
+]
+
+BARS = 500
+START = 1735689600  # 2025-01-01 00:00 UTC
+STEP = 3600         # 1h
+
+
+def candles(seed):
+    rng = random.Random(seed)
+    price = 100.0
+    rows = []
+    for i in range(BARS):
+        open_ = price
+        price *= 1 + rng.gauss(0, 0.01)
+        high = max(open_, price) * (1 + rng.random() * 0.005)
+        low = min(open_, price) * (1 - rng.random() * 0.005)
+        rows.append([START + i * STEP, round(open_, 4), round(high, 4),
+                     round(low, 4), round(price, 4), round(1000 + rng.random() * 9000, 2)])
+    return rows
+
+
+def rma(src, n):
+    out, prev = [], None
+    for i in range(len(src)):
+        if prev is None:
+            window = src[i - n + 1:i + 1] if i >= n - 1 else None
+            cur = None
+            if window is not None and all(v is not None for v in window):
+                total = 0.0
+                for v in window:
+                    total += v
+                cur = total / n
+        else:
+            cur = (1 / n) * src[i] + (1 - 1 / n) * prev
+        out.append(cur)
+        prev = cur
+    return out
+
+
+def pivot(values, i, prd, higher):
+    """ค่าของ ta.pivothigh/ta.pivotlow(prd, prd) ที่แท่ง i"""
+    c = i - prd
+    if c - prd < 0:
+        return None
+    for j in range(c - prd, i + 1):
+        if j == c:
+            continue
+        if (higher and values[j] >= values[c]) or (not higher and values[j] <= values[c]):
+            return None
+    return values[c]
+
+
+def series(rows, prd, factor, pd):
+    high = [r[2] for r in rows]
+    low = [r[3] for r in rows]
+    close = [r[4] for r in rows]
+
+    tr = [high[0] - low[0]] + [
+        max(high[i] - low[i], abs(high[i] - close[i - 1]), abs(low[i] - close[i - 1]))
+        for i in range(1, len(rows))
+    ]
+    atr = rma(tr, pd)
+
+    out = []
+    center = prev_tup = prev_tdown = prev_trend = None
+    for i in range(len(rows)):
+        ph = pivot(high, i, prd, True)
+        pl = pivot(low, i, prd, False)
+        lastpp = ph if ph is not None else pl
+        if lastpp is not None:
+            center = lastpp if center is None else (center * 2 + lastpp) / 3
+
+        up = dn = None
+        if center is not None and atr[i] is not None:
+            up = center - factor * atr[i]
+            dn = center + factor * atr[i]
+
+        c1 = close[i - 1] if i > 0 else None
+        if c1 is not None and prev_tup is not None and c1 > prev_tup:
+            tup = None if up is None else max(up, prev_tup)
+        else:
+            tup = up
+        if c1 is not None and prev_tdown is not None and c1 < prev_tdown:
+            tdown = None if dn is None else min(dn, prev_tdown)
+        else:
+            tdown = dn
+
+        if prev_tdown is not None and close[i] > prev_tdown:
+            trend = 1
+        elif prev_tup is not None and close[i] < prev_tup:
+            trend = -1
+        else:
+            trend = 1 if prev_trend is None else prev_trend
+
+        out.append((center, up, dn, trend, tup if trend == 1 else tdown))
+        prev_tup, prev_tdown, prev_trend = tup, tdown, trend
+    return out
+
+
+def fmt(v):
+    return "" if v is None else repr(float(v))
+
+
+def main():
+    for name, seed, prd, factor, pd in FIXTURES:
+        rows = candles(seed)
+        with open(name, "w") as f:
+            f.write("time,open,high,low,close,Volume,center,up,dn,trend,supertrend\n")
+            for row, values in zip(rows, series(rows, prd, factor, pd)):
+                f.write(",".join([str(row[0])] + [repr(v) for v in row[1:]] + [fmt(v) for v in values]) + "\n")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,199 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+)
+
+// WalkForwardConfig การตั้งค่า walk-forward analysis
+type WalkForwardConfig struct {
+	InSampleDays    int             `json:"in_sample_days"`     // ความยาวช่วง optimize
+	OutOfSampleDays int             `json:"out_of_sample_days"` // ความยาวช่วงทดสอบหลัง optimize
+	StepDays        int             `json:"step_days"`          // ระยะเลื่อนแต่ละ fold (0 = เท่ากับ OutOfSampleDays)
+	Optimizer       OptimizerConfig `json:"optimizer"`
+}
+
+// WalkForwardFold ผลของ fold หนึ่ง
+type WalkForwardFold struct {
+	Index           int            `json:"index"`
+	InSampleStart   time.Time      `json:"in_sample_start"`
+	InSampleEnd     time.Time      `json:"in_sample_end"`
+	OutSampleStart  time.Time      `json:"out_of_sample_start"`
+	OutSampleEnd    time.Time      `json:"out_of_sample_end"`
+	Params          StrategyParams `json:"params"` // พารามิเตอร์ที่ดีที่สุดจาก in-sample
+	InSampleReturn  float64        `json:"in_sample_return_pct"`
+	OutSampleReturn float64        `json:"out_of_sample_return_pct"`
+	OutSampleTrades int            `json:"out_of_sample_trades"`
+	Efficiency      float64        `json:"efficiency"` // ผลตอบแทนต่อวัน OOS / IS (0 เมื่อ IS ไม่มีกำไร)
+}
+
+// WalkForwardResult ผลรวมของ walk-forward analysis
+type WalkForwardResult struct {
+	Symbol          string            `json:"symbol"`
+	InitialCapital  float64           `json:"initial_capital"`
+	FinalCapital    float64           `json:"final_capital"`
+	TotalReturnPct  float64           `json:"total_return_pct"` // ผลตอบแทน OOS ต่อเนื่องทุก fold
+	MaxDrawdownPct  float64           `json:"max_drawdown_pct"`
+	EfficiencyRatio float64           `json:"efficiency_ratio"` // ผลตอบแทนรายปี OOS เฉลี่ย / IS เฉลี่ย (0 เมื่อ IS เฉลี่ยไม่มีกำไร)
+	Folds           []WalkForwardFold `json:"folds"`
+	Equity          []DailyReturn     `json:"equity"` // equity OOS รายวันที่ต่อกันทุก fold
+	Trades          []BacktestTrade   `json:"trades"`
+}
+
+// WalkForward optimize บนช่วง in-sample แล้วทดสอบพารามิเตอร์ที่ดีที่สุดบนช่วง out-of-sample ถัดไป
+// เลื่อนไปเรื่อย ๆ จนหมดข้อมูล โดยเงินทุนของแต่ละ fold ต่อจาก fold ก่อนหน้า
+func (o *Optimizer) WalkForward(config WalkForwardConfig) (*WalkForwardResult, error) {
+	if config.InSampleDays <= 0 || config.OutOfSampleDays <= 0 {
+		return nil, fmt.Errorf("in-sample และ out-of-sample ต้องมากกว่า 0 วัน")
+	}
+	if len(o.data) == 0 {
+		return nil, fmt.Errorf("ไม่มีข้อมูลราคาสำหรับ walk-forward")
+	}
+
+	step := config.StepDays
+	if step <= 0 {
+		step = config.OutOfSampleDays
+	}
+
+	// fold แรกเริ่มที่ต้นช่วงของ optimizer (ข้อมูลก่อนหน้าใช้เป็น warmup เท่านั้น)
+	dataStart, dataEnd := o.start, o.end
+
+	result := &WalkForwardResult{
+		Symbol:         o.symbol,
+		InitialCapital: o.initialCapital,
+	}
+	capital := o.initialCapital
+	peak := capital
+	var sumIS, sumOOS float64
+
+	writeLog(o.log, "🚶 Walk-forward %s: IS %d วัน / OOS %d วัน / step %d วัน\n",
+		o.symbol, config.InSampleDays, config.OutOfSampleDays, step)
+
+	for isStart := dataStart; ; isStart = isStart.AddDate(0, 0, step) {
+		isEnd := isStart.AddDate(0, 0, config.InSampleDays)
+		oosEnd := isEnd.AddDate(0, 0, config.OutOfSampleDays)
+		if !isEnd.Before(dataEnd) {
+			break
+		}
+		if oosEnd.After(dataEnd) {
+			oosEnd = dataEnd
+		}
+
+		fold := WalkForwardFold{
+			Index:          len(result.Folds) + 1,
+			InSampleStart:  isStart,
+			InSampleEnd:    isEnd,
+			OutSampleStart: isEnd,
+			OutSampleEnd:   oosEnd,
+		}
+
+		// In-sample: หาพารามิเตอร์ที่ดีที่สุด
+		inSample := *o
+		inSample.SetWindow(isStart, isEnd.Add(-time.Second))
+		runs, err := inSample.Run(config.Optimizer)
+		if err != nil {
+			return nil, fmt.Errorf("fold %d: %v", fold.Index, err)
+		}
+		if len(runs) == 0 || runs[0].Error != "" {
+			return nil, fmt.Errorf("fold %d: ไม่มีพารามิเตอร์ที่ใช้ได้", fold.Index)
+		}
+		fold.Params = runs[0].Params
+		fold.InSampleReturn = runs[0].TotalReturnPct
+
+		// Out-of-sample: ทดสอบด้วยพารามิเตอร์ที่ได้ โดยใช้เงินทุนต่อจาก fold ก่อน
+		oos, err := o.runWindow(fold.Params, capital, isEnd, oosEnd)
+		if err != nil {
+			return nil, fmt.Errorf("fold %d: %v", fold.Index, err)
+		}
+		fold.OutSampleReturn = oos.TotalReturnPct
+		fold.OutSampleTrades = oos.TotalTrades
+
+		isDays := isEnd.Sub(isStart).Hours() / 24
+		oosDays := oosEnd.Sub(isEnd).Hours() / 24
+		fold.Efficiency = walkForwardEfficiency(fold.InSampleReturn, isDays, fold.OutSampleReturn, oosDays)
+		sumIS += fold.InSampleReturn / isDays * 365
+		if oosDays > 0 {
+			sumOOS += fold.OutSampleReturn / oosDays * 365
+		}
+
+		// drawdown วัดจาก equity mark-to-market รายแท่งของช่วง OOS
+		for _, point := range oos.EquityCurve {
+			if point.Equity > peak {
+				peak = point.Equity
+			}
+			if dd := (peak - point.Equity) / peak * 100; dd > result.MaxDrawdownPct {
+				result.MaxDrawdownPct = dd
+			}
+		}
+
+		// ต่อ equity รายวันและ trades ของช่วง OOS (ผลตอบแทนวันแรกของ fold เทียบกับวันสุดท้ายของ fold ก่อน)
+		for _, point := range oos.DailyReturns {
+			result.Equity = appendDailyReturn(result.Equity, point.Date, point.Equity, o.initialCapital)
+		}
+		result.Trades = append(result.Trades, oos.Trades...)
+		capital = oos.FinalCapital
+
+		writeLog(o.log, "📁 Fold %d: IS %s→%s %.2f%% | OOS %s→%s %.2f%% (%d เทรด) | WFE %.2f\n",
+			fold.Index, isStart.Format("2006-01-02"), isEnd.Format("2006-01-02"), fold.InSampleReturn,
+			isEnd.Format("2006-01-02"), oosEnd.Format("2006-01-02"), fold.OutSampleReturn,
+			fold.OutSampleTrades, fold.Efficiency)
+
+		result.Folds = append(result.Folds, fold)
+		if !oosEnd.Before(dataEnd) {
+			break
+		}
+	}
+
+	if len(result.Folds) == 0 {
+		return nil, fmt.Errorf("ข้อมูลไม่พอสำหรับ fold แรก (ต้องมากกว่า %d วัน)", config.InSampleDays)
+	}
+
+	result.FinalCapital = capital
+	result.TotalReturnPct = (capital - o.initialCapital) / o.initialCapital * 100
+	if sumIS > 0 {
+		result.EfficiencyRatio = sumOOS / sumIS
+	}
+
+	return result, nil
+}
+
+// walkForwardEfficiency ผลตอบแทนต่อวันของ OOS เทียบกับ IS
+// (0 เมื่อ IS ขาดทุนหรือเสมอตัว เพราะอัตราส่วนกลับเครื่องหมายและไม่บอกอะไร)
+func walkForwardEfficiency(isReturn, isDays, oosReturn, oosDays float64) float64 {
+	if isReturn <= 0 || isDays <= 0 || oosDays <= 0 {
+		return 0
+	}
+	return (oosReturn / oosDays) / (isReturn / isDays)
+}
+
+// runWindow รัน backtest ด้วยพารามิเตอร์และเงินทุนที่กำหนดในช่วงเวลาหนึ่ง (ปิด log ระหว่างรัน)
+func (o *Optimizer) runWindow(params StrategyParams, capital float64, start, end time.Time) (*BacktestResult, error) {
+	bt, err := o.newBacktester(params, capital, start, end.Add(-time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return o.runner(bt)
+}
+
+// PrintSummary แสดงสรุปผล walk-forward
+func (r *WalkForwardResult) PrintSummary() {
+	fmt.Printf("\n🚶 ===== ผล Walk-Forward %s (%d folds) =====\n", r.Symbol, len(r.Folds))
+	fmt.Printf("%-5s %-23s %9s %-23s %9s %7s %6s  %s\n",
+		"Fold", "In-Sample", "IS%", "Out-of-Sample", "OOS%", "Trades", "WFE", "Params (pivot/atr/factor/ema/atrx/rr)")
+	for _, f := range r.Folds {
+		p := f.Params
+		wfe := "-"
+		if f.InSampleReturn > 0 {
+			wfe = fmt.Sprintf("%.2f", f.Efficiency)
+		}
+		fmt.Printf("%-5d %s→%s %9.2f %s→%s %9.2f %7d %6s  %d/%d/%.2f/%d/%.2f/%.2f\n",
+			f.Index,
+			f.InSampleStart.Format("2006-01-02"), f.InSampleEnd.Format("2006-01-02"), f.InSampleReturn,
+			f.OutSampleStart.Format("2006-01-02"), f.OutSampleEnd.Format("2006-01-02"), f.OutSampleReturn,
+			f.OutSampleTrades, wfe,
+			p.PivotPeriod, p.ATRPeriod, p.ATRFactor, p.EMAPeriod, p.ATRMultiplier, p.RiskReward)
+	}
+	fmt.Printf("\n💰 OOS ต่อเนื่อง: $%.2f → $%.2f (%.2f%%)\n", r.InitialCapital, r.FinalCapital, r.TotalReturnPct)
+	fmt.Printf("📉 Max Drawdown (OOS): %.2f%%\n", r.MaxDrawdownPct)
+	fmt.Printf("⚖️ Walk-Forward Efficiency: %.2f (>0.5 ถือว่าพารามิเตอร์ไม่ overfit มาก)\n", r.EfficiencyRatio)
+}
@@ -0,0 +1,97 @@
+package trading
+
+import (
+	"math"
+	"testing"
+)
+
+// holdForever จำนวนแท่ง 15m ที่ holdStrategy ถือเกินทุกชุดข้อมูลใน test
+const holdForever = 1 << 20
+
+// vCandles แท่ง 1h เริ่มเที่ยงคืน UTC ราคาลงเส้นตรงจาก 200 ถึง 100 ใน days/2 วันแรกแล้วขึ้นกลับถึง 200
+func vCandles(days int) []OHLCV {
+	const start = 1_699_920_000 // 2023-11-14 00:00 UTC
+	n := days * 24
+	data := make([]OHLCV, n)
+	for i := range data {
+		price := 100 + 100*math.Abs(float64(i-n/2))/float64(n/2)
+		data[i] = OHLCV{Timestamp: start + int64(i)*3600, Open: price, High: price, Low: price, Close: price, Volume: 1000}
+	}
+	return data
+}
+
+// checkDayOverDay ตรวจว่า Return ของแต่ละวันเทียบกับ equity ปิดวันก่อนหน้า (วันแรกเทียบกับ base)
+func checkDayOverDay(t *testing.T, days []DailyReturn, base float64) {
+	t.Helper()
+	if len(days) < 2 {
+		t.Fatalf("มีข้อมูลรายวัน %d วัน", len(days))
+	}
+	prev := base
+	for i, day := range days {
+		if want := (day.Equity - prev) / prev * 100; !approx(day.Return, want) {
+			t.Fatalf("วันที่ %d (%s): return %v, ต้องการ %v เทียบกับวันก่อนหน้า", i, day.Date.Format("2006-01-02"), day.Return, want)
+		}
+		prev = day.Equity
+	}
+}
+
+func TestWalkForwardEfficiencyNeedsProfitableInSample(t *testing.T) {
+	// ถือ LONG ตลอดช่วง: fold 1-2 มี IS ขาดทุน (ขาลง) ส่วน fold 3 มี IS กำไร
+	runner := func(bt *Backtester) (*BacktestResult, error) {
+		return bt.RunStrategy(&holdStrategy{sizePct: 1, hold: holdForever})
+	}
+	optimizer := NewOptimizer("TEST_USDT", vCandles(40), 1000, runner)
+	result, err := optimizer.WalkForward(WalkForwardConfig{
+		InSampleDays:    10,
+		OutOfSampleDays: 10,
+		Optimizer: OptimizerConfig{
+			Space:     []ParamRange{{Name: "atr_factor", Values: []float64{3}}},
+			Method:    GridSearch,
+			Workers:   1,
+			Objective: ObjectiveReturn,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Folds) != 3 {
+		t.Fatalf("ได้ %d folds, ต้องการ 3", len(result.Folds))
+	}
+	for i, fold := range result.Folds[:2] {
+		if fold.InSampleReturn >= 0 || fold.Efficiency != 0 {
+			t.Fatalf("fold %d: IS %.2f%% WFE %v, ต้องการ IS ขาดทุนและ WFE 0", i+1, fold.InSampleReturn, fold.Efficiency)
+		}
+	}
+	last := result.Folds[2]
+	want := (last.OutSampleReturn / last.OutSampleEnd.Sub(last.OutSampleStart).Hours()) /
+		(last.InSampleReturn / last.InSampleEnd.Sub(last.InSampleStart).Hours())
+	if last.InSampleReturn <= 0 || !approx(last.Efficiency, want) {
+		t.Fatalf("fold 3: IS %.2f%% WFE %v, ต้องการ %v", last.InSampleReturn, last.Efficiency, want)
+	}
+	// IS รายปีเฉลี่ยขาดทุน (-25%, -33%, +50%) จึงไม่มี efficiency รวม
+	if result.EfficiencyRatio != 0 {
+		t.Fatalf("EfficiencyRatio %v, ต้องการ 0 เมื่อ IS เฉลี่ยไม่มีกำไร", result.EfficiencyRatio)
+	}
+
+	// equity OOS ที่ต่อกันต้องเป็นผลตอบแทนรายวันต่อเนื่องข้าม fold ไม่ใช่ผลตอบแทนสะสม
+	checkDayOverDay(t, result.Equity, result.InitialCapital)
+	if end := result.Equity[len(result.Equity)-1].Equity; !approx(end, result.FinalCapital) {
+		t.Fatalf("equity วันสุดท้าย %v, เงินทุนสุดท้าย %v", end, result.FinalCapital)
+	}
+}
+
+func TestDailyReturnsAreDayOverDay(t *testing.T) {
+	data := vCandles(6)
+	result := runScripted(t, data, &holdStrategy{sizePct: 1, hold: holdForever}, nil)
+	checkDayOverDay(t, result.DailyReturns, result.InitialCapital)
+
+	p := newTestPortfolio(t, PortfolioConfig{InitialCapital: 1000, MaxSymbolExposure: 0.5}, 2,
+		func(int) []OHLCV { return data },
+		func() Strategy { return &holdStrategy{sizePct: 0.5, hold: holdForever} })
+	portfolio, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDayOverDay(t, portfolio.DailyReturns, portfolio.InitialCapital)
+}